@@ -2,12 +2,16 @@ package main
 
 import (
 	"context"
+	"errors"
+	"flag"
 	"fmt"
 	"log/slog"
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
 	"syscall"
+	"text/tabwriter"
 
 	"github.com/thetnaingtn/dirty-hand/internal/config"
 	"github.com/thetnaingtn/dirty-hand/server"
@@ -31,6 +35,26 @@ func main() {
 		os.Exit(1)
 	}
 
+	if args := flag.Args(); len(args) > 0 {
+		if args[0] != "migrate" {
+			slog.Error("unknown command", "command", args[0])
+			os.Exit(2)
+		}
+
+		err := runMigrate(context.Background(), driver, args[1:])
+		driver.Close()
+		if err != nil {
+			slog.Error("migration failed", "error", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if err := driver.Migrator().Check(context.Background()); err != nil {
+		slog.Error("database schema does not match the migrations", "error", err)
+		os.Exit(1)
+	}
+
 	ctx, cancel := context.WithCancel(context.Background())
 
 	store := store.NewStore(driver, config)
@@ -39,6 +63,7 @@ func main() {
 	if err != nil {
 		cancel()
 		slog.Error("failed to create server", "error", err)
+		os.Exit(1)
 	}
 
 	c := make(chan os.Signal, 1)
@@ -64,3 +89,69 @@ func main() {
 
 	<-ctx.Done()
 }
+
+const migrateUsage = "usage: migrate status | up | down-to <version>"
+
+// runMigrate runs the migrate command: status lists the migrations, up
+// applies the pending ones and down-to reverts those newer than a version.
+func runMigrate(ctx context.Context, driver store.Driver, args []string) error {
+	if len(args) == 0 {
+		return errors.New(migrateUsage)
+	}
+
+	migrator := driver.Migrator()
+
+	switch args[0] {
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tNAME\tSTATUS\tAPPLIED")
+		for _, status := range statuses {
+			state, applied := "pending", ""
+			if status.Applied {
+				state, applied = "applied", status.AppliedTime.Local().Format("2006-01-02 15:04:05")
+			}
+			if status.Modified {
+				state = "modified"
+			}
+			if status.Unknown {
+				state = "unknown"
+			}
+			fmt.Fprintf(w, "%04d\t%s\t%s\t%s\n", status.Version, status.Name, state, applied)
+		}
+		return w.Flush()
+	case "up":
+		applied, err := migrator.Up(ctx)
+		for _, migration := range applied {
+			slog.Info("applied migration", "version", migration.Version, "name", migration.Name)
+		}
+		if err != nil {
+			return err
+		}
+		if len(applied) == 0 {
+			slog.Info("database schema is up to date", "version", migrator.Latest())
+		}
+		return nil
+	case "down-to":
+		if len(args) != 2 {
+			return errors.New(migrateUsage)
+		}
+
+		version, err := strconv.ParseInt(args[1], 10, 64)
+		if err != nil || version < 0 {
+			return fmt.Errorf("invalid version %q", args[1])
+		}
+
+		reverted, err := migrator.DownTo(ctx, version)
+		for _, migration := range reverted {
+			slog.Info("reverted migration", "version", migration.Version, "name", migration.Name)
+		}
+		return err
+	default:
+		return errors.New(migrateUsage)
+	}
+}
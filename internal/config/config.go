@@ -6,6 +6,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/spf13/viper"
 )
@@ -18,6 +19,21 @@ type Config struct {
 	// Database configuration
 	Database DatabaseConfig `mapstructure:"database" yaml:"database"`
 
+	// Session configuration
+	Session SessionConfig `mapstructure:"session" yaml:"session"`
+
+	// Password policy and hashing configuration
+	Password PasswordConfig `mapstructure:"password" yaml:"password"`
+
+	// Sign-in brute-force protection configuration
+	SignIn SignInConfig `mapstructure:"sign_in" yaml:"sign_in"`
+
+	// Registration configuration
+	Registration RegistrationConfig `mapstructure:"registration" yaml:"registration"`
+
+	// OpenID Connect single sign-on configuration
+	OIDC OIDCConfig `mapstructure:"oidc" yaml:"oidc"`
+
 	// Environment
 	Environment string `mapstructure:"environment" yaml:"environment"`
 }
@@ -26,11 +42,126 @@ type Config struct {
 type ServerConfig struct {
 	Addr string `mapstructure:"addr" yaml:"addr"`
 	Port string `mapstructure:"port" yaml:"port"`
+	// TrustedProxies lists the IP addresses and CIDR ranges of reverse
+	// proxies whose X-Forwarded-For header is trusted to name the client
+	TrustedProxies []string `mapstructure:"trusted_proxies" yaml:"trusted_proxies"`
 }
 
+// Database drivers
+const (
+	DatabaseSQLite   = "sqlite"
+	DatabasePostgres = "postgres"
+	DatabaseMySQL    = "mysql"
+)
+
 // DatabaseConfig holds database-specific configuration
 type DatabaseConfig struct {
-	DSN string `mapstructure:"dsn" yaml:"dsn"`
+	// Driver is the database the DSN points to, "sqlite", "postgres" or "mysql"
+	Driver string `mapstructure:"driver" yaml:"driver"`
+	DSN    string `mapstructure:"dsn" yaml:"dsn"`
+}
+
+// SessionConfig holds sign-in session configuration
+type SessionConfig struct {
+	// IdleTimeout ends a session that has not been used for this long, 0
+	// turns the idle timeout off
+	IdleTimeout time.Duration `mapstructure:"idle_timeout" yaml:"idle_timeout"`
+	// AbsoluteLifetime ends a session this long after sign-in, however active
+	// it is. It must be positive, every session has an absolute limit
+	AbsoluteLifetime time.Duration `mapstructure:"absolute_lifetime" yaml:"absolute_lifetime"`
+	// CleanupInterval is how often expired sessions are deleted
+	CleanupInterval time.Duration `mapstructure:"cleanup_interval" yaml:"cleanup_interval"`
+	// CookieKeys authenticate session cookies. The first key signs new
+	// cookies and every key is accepted when verifying, so keys can be rotated
+	// by prepending a new one and removing the old one later.
+	CookieKeys []string `mapstructure:"cookie_keys" yaml:"cookie_keys"`
+	// EncryptCookie encrypts session cookies so their content is not readable
+	EncryptCookie bool `mapstructure:"encrypt_cookie" yaml:"encrypt_cookie"`
+}
+
+// PasswordConfig holds the password policy and how passwords are hashed
+type PasswordConfig struct {
+	MinLength int `mapstructure:"min_length" yaml:"min_length"`
+	// MaxLength of 0 allows passwords of any length
+	MaxLength     int  `mapstructure:"max_length" yaml:"max_length"`
+	RequireUpper  bool `mapstructure:"require_upper" yaml:"require_upper"`
+	RequireLower  bool `mapstructure:"require_lower" yaml:"require_lower"`
+	RequireDigit  bool `mapstructure:"require_digit" yaml:"require_digit"`
+	RequireSymbol bool `mapstructure:"require_symbol" yaml:"require_symbol"`
+	// RejectCommon rejects passwords from a built-in list of common ones
+	RejectCommon bool `mapstructure:"reject_common" yaml:"reject_common"`
+	// RejectUsername rejects passwords that contain the username
+	RejectUsername bool `mapstructure:"reject_username" yaml:"reject_username"`
+	// Algorithm hashes new passwords, either "argon2id" or "bcrypt". Hashes
+	// made with another algorithm or other parameters are upgraded when
+	// their user signs in.
+	Algorithm  string         `mapstructure:"algorithm" yaml:"algorithm"`
+	BcryptCost int            `mapstructure:"bcrypt_cost" yaml:"bcrypt_cost"`
+	Argon2id   Argon2idConfig `mapstructure:"argon2id" yaml:"argon2id"`
+}
+
+// Argon2idConfig holds the argon2id hashing parameters
+type Argon2idConfig struct {
+	// Memory in KiB
+	Memory      uint32 `mapstructure:"memory" yaml:"memory"`
+	Iterations  uint32 `mapstructure:"iterations" yaml:"iterations"`
+	Parallelism uint8  `mapstructure:"parallelism" yaml:"parallelism"`
+}
+
+// SignInConfig holds the limits on failed sign-in attempts, tracked per
+// username and per client IP address
+type SignInConfig struct {
+	// MaxUserFailures locks a username out after this many failures, 0 never does
+	MaxUserFailures int `mapstructure:"max_user_failures" yaml:"max_user_failures"`
+	// MaxIPFailures locks a client IP out after this many failures, 0 never does
+	MaxIPFailures int `mapstructure:"max_ip_failures" yaml:"max_ip_failures"`
+	// LockoutDuration is how long a lockout lasts
+	LockoutDuration time.Duration `mapstructure:"lockout_duration" yaml:"lockout_duration"`
+	// BackoffBase is the wait after the first failure of a username, doubled
+	// after each further failure up to BackoffMax
+	BackoffBase time.Duration `mapstructure:"backoff_base" yaml:"backoff_base"`
+	BackoffMax  time.Duration `mapstructure:"backoff_max" yaml:"backoff_max"`
+	// FailureWindow is how long failures are remembered
+	FailureWindow time.Duration `mapstructure:"failure_window" yaml:"failure_window"`
+}
+
+// Registration modes
+const (
+	RegistrationOpen       = "open"
+	RegistrationInviteOnly = "invite-only"
+	RegistrationClosed     = "closed"
+)
+
+// RegistrationConfig holds who may create an account
+type RegistrationConfig struct {
+	// Mode is "open" to let anyone sign up, "invite-only" to require an
+	// invitation code, or "closed" to also stop identity providers from
+	// creating accounts. The first admin can sign up in any mode.
+	Mode string `mapstructure:"mode" yaml:"mode"`
+	// InvitationTTL is how long invitations stay valid by default
+	InvitationTTL time.Duration `mapstructure:"invitation_ttl" yaml:"invitation_ttl"`
+}
+
+// OIDCConfig holds OpenID Connect single sign-on configuration
+type OIDCConfig struct {
+	Enabled bool `mapstructure:"enabled" yaml:"enabled"`
+	// Issuer is the identity provider's issuer URL, used for discovery
+	Issuer       string `mapstructure:"issuer" yaml:"issuer"`
+	ClientID     string `mapstructure:"client_id" yaml:"client_id"`
+	ClientSecret string `mapstructure:"client_secret" yaml:"client_secret"`
+	// RedirectURL is the callback URL registered with the identity provider,
+	// ending in /auth/oidc/callback
+	RedirectURL string   `mapstructure:"redirect_url" yaml:"redirect_url"`
+	Scopes      []string `mapstructure:"scopes" yaml:"scopes"`
+	// UsernameClaim names the claim used as the username of new users
+	UsernameClaim string `mapstructure:"username_claim" yaml:"username_claim"`
+	// GroupsClaim names the claim listing the user's groups
+	GroupsClaim string `mapstructure:"groups_claim" yaml:"groups_claim"`
+	// DefaultRole is given to new users none of whose groups has a role
+	DefaultRole string `mapstructure:"default_role" yaml:"default_role"`
+	// GroupRoles maps group names to roles. A user with several mapped groups
+	// gets the most privileged role.
+	GroupRoles map[string]string `mapstructure:"group_roles" yaml:"group_roles"`
 }
 
 // NewConfig creates a new configuration instance
@@ -92,10 +223,58 @@ func setDefaults(v *viper.Viper) {
 	// Server defaults
 	v.SetDefault("server.addr", "localhost")
 	v.SetDefault("server.port", "8080")
+	v.SetDefault("server.trusted_proxies", []string{})
 
 	// Database defaults
+	v.SetDefault("database.driver", DatabaseSQLite)
 	v.SetDefault("database.dsn", "")
 
+	// Session defaults
+	v.SetDefault("session.idle_timeout", "336h")
+	v.SetDefault("session.absolute_lifetime", "720h")
+	v.SetDefault("session.cleanup_interval", "1h")
+	v.SetDefault("session.cookie_keys", []string{})
+	v.SetDefault("session.encrypt_cookie", false)
+
+	// Password defaults
+	v.SetDefault("password.min_length", 8)
+	v.SetDefault("password.max_length", 128)
+	v.SetDefault("password.require_upper", false)
+	v.SetDefault("password.require_lower", false)
+	v.SetDefault("password.require_digit", false)
+	v.SetDefault("password.require_symbol", false)
+	v.SetDefault("password.reject_common", true)
+	v.SetDefault("password.reject_username", true)
+	v.SetDefault("password.algorithm", "argon2id")
+	v.SetDefault("password.bcrypt_cost", 10)
+	v.SetDefault("password.argon2id.memory", 19456)
+	v.SetDefault("password.argon2id.iterations", 2)
+	v.SetDefault("password.argon2id.parallelism", 1)
+
+	// Sign-in defaults
+	v.SetDefault("sign_in.max_user_failures", 5)
+	v.SetDefault("sign_in.max_ip_failures", 20)
+	v.SetDefault("sign_in.lockout_duration", "15m")
+	v.SetDefault("sign_in.backoff_base", "1s")
+	v.SetDefault("sign_in.backoff_max", "1m")
+	v.SetDefault("sign_in.failure_window", "1h")
+
+	// Registration defaults
+	v.SetDefault("registration.mode", "open")
+	v.SetDefault("registration.invitation_ttl", "168h")
+
+	// OIDC defaults
+	v.SetDefault("oidc.enabled", false)
+	v.SetDefault("oidc.issuer", "")
+	v.SetDefault("oidc.client_id", "")
+	v.SetDefault("oidc.client_secret", "")
+	v.SetDefault("oidc.redirect_url", "")
+	v.SetDefault("oidc.scopes", []string{"openid", "profile", "email"})
+	v.SetDefault("oidc.username_claim", "preferred_username")
+	v.SetDefault("oidc.groups_claim", "groups")
+	v.SetDefault("oidc.default_role", "product:view")
+	v.SetDefault("oidc.group_roles", map[string]string{})
+
 	// Environment default
 	v.SetDefault("environment", "development")
 }
@@ -146,10 +325,57 @@ func bindEnvironmentVariables(v *viper.Viper) {
 	// Server configuration
 	v.BindEnv("server.addr", "SERVER_ADDR", "ADDR")
 	v.BindEnv("server.port", "SERVER_PORT", "PORT")
+	v.BindEnv("server.trusted_proxies", "SERVER_TRUSTED_PROXIES")
 
 	// Database configuration
+	v.BindEnv("database.driver", "DATABASE_DRIVER")
 	v.BindEnv("database.dsn", "DATABASE_DSN")
 
+	// Session configuration
+	v.BindEnv("session.idle_timeout", "SESSION_IDLE_TIMEOUT")
+	v.BindEnv("session.absolute_lifetime", "SESSION_ABSOLUTE_LIFETIME")
+	v.BindEnv("session.cleanup_interval", "SESSION_CLEANUP_INTERVAL")
+	v.BindEnv("session.cookie_keys", "SESSION_COOKIE_KEYS")
+	v.BindEnv("session.encrypt_cookie", "SESSION_ENCRYPT_COOKIE")
+
+	// Password configuration
+	v.BindEnv("password.min_length", "PASSWORD_MIN_LENGTH")
+	v.BindEnv("password.max_length", "PASSWORD_MAX_LENGTH")
+	v.BindEnv("password.require_upper", "PASSWORD_REQUIRE_UPPER")
+	v.BindEnv("password.require_lower", "PASSWORD_REQUIRE_LOWER")
+	v.BindEnv("password.require_digit", "PASSWORD_REQUIRE_DIGIT")
+	v.BindEnv("password.require_symbol", "PASSWORD_REQUIRE_SYMBOL")
+	v.BindEnv("password.reject_common", "PASSWORD_REJECT_COMMON")
+	v.BindEnv("password.reject_username", "PASSWORD_REJECT_USERNAME")
+	v.BindEnv("password.algorithm", "PASSWORD_ALGORITHM")
+	v.BindEnv("password.bcrypt_cost", "PASSWORD_BCRYPT_COST")
+	v.BindEnv("password.argon2id.memory", "PASSWORD_ARGON2ID_MEMORY")
+	v.BindEnv("password.argon2id.iterations", "PASSWORD_ARGON2ID_ITERATIONS")
+	v.BindEnv("password.argon2id.parallelism", "PASSWORD_ARGON2ID_PARALLELISM")
+
+	// Sign-in configuration
+	v.BindEnv("sign_in.max_user_failures", "SIGN_IN_MAX_USER_FAILURES")
+	v.BindEnv("sign_in.max_ip_failures", "SIGN_IN_MAX_IP_FAILURES")
+	v.BindEnv("sign_in.lockout_duration", "SIGN_IN_LOCKOUT_DURATION")
+	v.BindEnv("sign_in.backoff_base", "SIGN_IN_BACKOFF_BASE")
+	v.BindEnv("sign_in.backoff_max", "SIGN_IN_BACKOFF_MAX")
+	v.BindEnv("sign_in.failure_window", "SIGN_IN_FAILURE_WINDOW")
+
+	// Registration configuration
+	v.BindEnv("registration.mode", "REGISTRATION_MODE")
+	v.BindEnv("registration.invitation_ttl", "REGISTRATION_INVITATION_TTL")
+
+	// OIDC configuration
+	v.BindEnv("oidc.enabled", "OIDC_ENABLED")
+	v.BindEnv("oidc.issuer", "OIDC_ISSUER")
+	v.BindEnv("oidc.client_id", "OIDC_CLIENT_ID")
+	v.BindEnv("oidc.client_secret", "OIDC_CLIENT_SECRET")
+	v.BindEnv("oidc.redirect_url", "OIDC_REDIRECT_URL")
+	v.BindEnv("oidc.scopes", "OIDC_SCOPES")
+	v.BindEnv("oidc.username_claim", "OIDC_USERNAME_CLAIM")
+	v.BindEnv("oidc.groups_claim", "OIDC_GROUPS_CLAIM")
+	v.BindEnv("oidc.default_role", "OIDC_DEFAULT_ROLE")
+
 	// Environment
 	v.BindEnv("environment", "ENVIRONMENT")
 }
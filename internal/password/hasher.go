@@ -0,0 +1,163 @@
+package password
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/thetnaingtn/dirty-hand/internal/config"
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	AlgorithmBcrypt   = "bcrypt"
+	AlgorithmArgon2id = "argon2id"
+)
+
+const bcryptMaxBytes = 72
+
+var errUnknownHash = errors.New("unknown password hash format")
+
+// Hasher hashes new passwords with the configured algorithm and verifies
+// hashes made with any supported algorithm, so that the algorithm or its
+// parameters can change while old hashes keep working.
+type Hasher struct {
+	algorithm  string
+	bcryptCost int
+	argon2id   argon2idParams
+}
+
+type argon2idParams struct {
+	memory      uint32
+	iterations  uint32
+	parallelism uint8
+	saltLength  int
+	keyLength   uint32
+}
+
+func NewHasher(cfg config.PasswordConfig) (*Hasher, error) {
+	h := &Hasher{
+		algorithm:  cfg.Algorithm,
+		bcryptCost: cfg.BcryptCost,
+		argon2id: argon2idParams{
+			memory:      cfg.Argon2id.Memory,
+			iterations:  cfg.Argon2id.Iterations,
+			parallelism: cfg.Argon2id.Parallelism,
+			saltLength:  16,
+			keyLength:   32,
+		},
+	}
+
+	switch h.algorithm {
+	case AlgorithmBcrypt:
+		if h.bcryptCost < bcrypt.MinCost || h.bcryptCost > bcrypt.MaxCost {
+			return nil, fmt.Errorf("bcrypt cost must be between %d and %d", bcrypt.MinCost, bcrypt.MaxCost)
+		}
+	case AlgorithmArgon2id:
+		if h.argon2id.memory == 0 || h.argon2id.iterations == 0 || h.argon2id.parallelism == 0 {
+			return nil, errors.New("argon2id memory, iterations and parallelism must be positive")
+		}
+	default:
+		return nil, fmt.Errorf("unsupported password hashing algorithm %q", h.algorithm)
+	}
+
+	return h, nil
+}
+
+// Hash returns the hash of a password in the format of the configured
+// algorithm.
+func (h *Hasher) Hash(password string) (string, error) {
+	if h.algorithm == AlgorithmBcrypt {
+		hash, err := bcrypt.GenerateFromPassword([]byte(password), h.bcryptCost)
+		if err != nil {
+			return "", err
+		}
+		return string(hash), nil
+	}
+
+	salt := make([]byte, h.argon2id.saltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+
+	p := h.argon2id
+	key := argon2.IDKey([]byte(password), salt, p.iterations, p.memory, p.parallelism, p.keyLength)
+
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s", argon2.Version, p.memory, p.iterations, p.parallelism,
+		base64.RawStdEncoding.EncodeToString(salt), base64.RawStdEncoding.EncodeToString(key)), nil
+}
+
+// Verify reports whether password matches hash, whichever supported
+// algorithm made it.
+func (h *Hasher) Verify(hash, password string) (bool, error) {
+	switch {
+	case isBcryptHash(hash):
+		err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
+		if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+			return false, nil
+		}
+		return err == nil, err
+	case strings.HasPrefix(hash, "$argon2id$"):
+		p, salt, key, err := decodeArgon2idHash(hash)
+		if err != nil {
+			return false, err
+		}
+		computed := argon2.IDKey([]byte(password), salt, p.iterations, p.memory, p.parallelism, uint32(len(key)))
+		return subtle.ConstantTimeCompare(computed, key) == 1, nil
+	default:
+		return false, errUnknownHash
+	}
+}
+
+// NeedsRehash reports whether hash was made with another algorithm or other
+// parameters than the configured ones.
+func (h *Hasher) NeedsRehash(hash string) bool {
+	switch h.algorithm {
+	case AlgorithmBcrypt:
+		cost, err := bcrypt.Cost([]byte(hash))
+		return err != nil || cost != h.bcryptCost
+	default:
+		p, _, _, err := decodeArgon2idHash(hash)
+		return err != nil || p.memory != h.argon2id.memory || p.iterations != h.argon2id.iterations || p.parallelism != h.argon2id.parallelism
+	}
+}
+
+func isBcryptHash(hash string) bool {
+	return strings.HasPrefix(hash, "$2a$") || strings.HasPrefix(hash, "$2b$") || strings.HasPrefix(hash, "$2y$")
+}
+
+// decodeArgon2idHash parses a hash in the PHC string format
+// "$argon2id$v=19$m=<memory>,t=<iterations>,p=<parallelism>$<salt>$<key>".
+func decodeArgon2idHash(hash string) (argon2idParams, []byte, []byte, error) {
+	var p argon2idParams
+
+	parts := strings.Split(hash, "$")
+	if len(parts) != 6 || parts[1] != AlgorithmArgon2id {
+		return p, nil, nil, errUnknownHash
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return p, nil, nil, errUnknownHash
+	}
+
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &p.memory, &p.iterations, &p.parallelism); err != nil {
+		return p, nil, nil, errUnknownHash
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return p, nil, nil, errUnknownHash
+	}
+
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil || len(key) == 0 {
+		return p, nil, nil, errUnknownHash
+	}
+
+	return p, salt, key, nil
+}
@@ -0,0 +1,117 @@
+package password
+
+import (
+	"bufio"
+	_ "embed"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/thetnaingtn/dirty-hand/internal/config"
+)
+
+//go:embed common_passwords.txt
+var commonPasswordList string
+
+var commonPasswords = parseCommonPasswords(commonPasswordList)
+
+// Violation describes one way a password breaks the policy.
+type Violation struct {
+	Reason      string
+	Description string
+}
+
+// Policy decides which passwords users may choose.
+type Policy struct {
+	config config.PasswordConfig
+}
+
+func NewPolicy(cfg config.PasswordConfig) *Policy {
+	return &Policy{config: cfg}
+}
+
+// Validate returns every rule the password breaks, or nil when it is
+// acceptable for the user.
+func (p *Policy) Validate(username, password string) []Violation {
+	var violations []Violation
+	cfg := p.config
+
+	length := utf8.RuneCountInString(password)
+	if length < cfg.MinLength {
+		violations = append(violations, Violation{
+			Reason:      "TOO_SHORT",
+			Description: fmt.Sprintf("must be at least %d characters long", cfg.MinLength),
+		})
+	}
+
+	if cfg.MaxLength > 0 && length > cfg.MaxLength {
+		violations = append(violations, Violation{
+			Reason:      "TOO_LONG",
+			Description: fmt.Sprintf("must be at most %d characters long", cfg.MaxLength),
+		})
+	}
+
+	// bcrypt cannot hash longer passwords.
+	if cfg.Algorithm == AlgorithmBcrypt && len(password) > bcryptMaxBytes {
+		violations = append(violations, Violation{
+			Reason:      "TOO_LONG",
+			Description: fmt.Sprintf("must be at most %d bytes long", bcryptMaxBytes),
+		})
+	}
+
+	var hasUpper, hasLower, hasDigit, hasSymbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			hasUpper = true
+		case unicode.IsLower(r):
+			hasLower = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r) || unicode.IsSpace(r):
+			hasSymbol = true
+		}
+	}
+
+	if cfg.RequireUpper && !hasUpper {
+		violations = append(violations, Violation{Reason: "MISSING_UPPERCASE", Description: "must contain an uppercase letter"})
+	}
+
+	if cfg.RequireLower && !hasLower {
+		violations = append(violations, Violation{Reason: "MISSING_LOWERCASE", Description: "must contain a lowercase letter"})
+	}
+
+	if cfg.RequireDigit && !hasDigit {
+		violations = append(violations, Violation{Reason: "MISSING_DIGIT", Description: "must contain a digit"})
+	}
+
+	if cfg.RequireSymbol && !hasSymbol {
+		violations = append(violations, Violation{Reason: "MISSING_SYMBOL", Description: "must contain a symbol"})
+	}
+
+	if cfg.RejectCommon && commonPasswords[strings.ToLower(password)] {
+		violations = append(violations, Violation{Reason: "TOO_COMMON", Description: "is too common"})
+	}
+
+	if cfg.RejectUsername && username != "" && strings.Contains(strings.ToLower(password), strings.ToLower(username)) {
+		violations = append(violations, Violation{Reason: "CONTAINS_USERNAME", Description: "must not contain the username"})
+	}
+
+	return violations
+}
+
+func parseCommonPasswords(list string) map[string]bool {
+	passwords := map[string]bool{}
+
+	scanner := bufio.NewScanner(strings.NewReader(list))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		passwords[strings.ToLower(line)] = true
+	}
+
+	return passwords
+}
@@ -25,16 +25,17 @@ const (
 )
 
 type Product struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
-	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
-	Description   string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
-	Price         float64                `protobuf:"fixed64,4,opt,name=price,proto3" json:"price,omitempty"`
-	Cover         string                 `protobuf:"bytes,5,opt,name=cover,proto3" json:"cover,omitempty"`
-	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
-	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Id             int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name           string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Description    string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	Price          float64                `protobuf:"fixed64,4,opt,name=price,proto3" json:"price,omitempty"`
+	Cover          string                 `protobuf:"bytes,5,opt,name=cover,proto3" json:"cover,omitempty"`
+	CreatedAt      *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt      *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	OrganizationId int64                  `protobuf:"varint,8,opt,name=organization_id,json=organizationId,proto3" json:"organization_id,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
 }
 
 func (x *Product) Reset() {
@@ -116,6 +117,13 @@ func (x *Product) GetUpdatedAt() *timestamppb.Timestamp {
 	return nil
 }
 
+func (x *Product) GetOrganizationId() int64 {
+	if x != nil {
+		return x.OrganizationId
+	}
+	return 0
+}
+
 type CreateProductRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
@@ -260,15 +268,67 @@ func (x *UpdateProductRequest) GetCover() string {
 	return ""
 }
 
-type ListProductsRequest struct {
+type GetProductRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetProductRequest) Reset() {
+	*x = GetProductRequest{}
+	mi := &file_api_v1_product_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetProductRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetProductRequest) ProtoMessage() {}
+
+func (x *GetProductRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_product_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetProductRequest.ProtoReflect.Descriptor instead.
+func (*GetProductRequest) Descriptor() ([]byte, []int) {
+	return file_api_v1_product_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GetProductRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type ListProductsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Maximum number of products to return. Defaults to 50, capped at 1000.
+	PageSize int32 `protobuf:"varint,1,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	// Token returned as next_page_token by a previous call.
+	PageToken string `protobuf:"bytes,2,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	// One of name, price, created_at or updated_at, optionally followed by
+	// asc or desc, e.g. "price desc". Defaults to creation order.
+	OrderBy       string         `protobuf:"bytes,3,opt,name=order_by,json=orderBy,proto3" json:"order_by,omitempty"`
+	Filter        *ProductFilter `protobuf:"bytes,4,opt,name=filter,proto3" json:"filter,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *ListProductsRequest) Reset() {
 	*x = ListProductsRequest{}
-	mi := &file_api_v1_product_proto_msgTypes[3]
+	mi := &file_api_v1_product_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -280,7 +340,7 @@ func (x *ListProductsRequest) String() string {
 func (*ListProductsRequest) ProtoMessage() {}
 
 func (x *ListProductsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_v1_product_proto_msgTypes[3]
+	mi := &file_api_v1_product_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -293,19 +353,144 @@ func (x *ListProductsRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListProductsRequest.ProtoReflect.Descriptor instead.
 func (*ListProductsRequest) Descriptor() ([]byte, []int) {
-	return file_api_v1_product_proto_rawDescGZIP(), []int{3}
+	return file_api_v1_product_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ListProductsRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListProductsRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+func (x *ListProductsRequest) GetOrderBy() string {
+	if x != nil {
+		return x.OrderBy
+	}
+	return ""
+}
+
+func (x *ListProductsRequest) GetFilter() *ProductFilter {
+	if x != nil {
+		return x.Filter
+	}
+	return nil
+}
+
+type ProductFilter struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Case-insensitive substring match on the product name.
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	MinPrice      *float64               `protobuf:"fixed64,2,opt,name=min_price,json=minPrice,proto3,oneof" json:"min_price,omitempty"`
+	MaxPrice      *float64               `protobuf:"fixed64,3,opt,name=max_price,json=maxPrice,proto3,oneof" json:"max_price,omitempty"`
+	CreatedAfter  *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=created_after,json=createdAfter,proto3" json:"created_after,omitempty"`
+	CreatedBefore *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=created_before,json=createdBefore,proto3" json:"created_before,omitempty"`
+	UpdatedAfter  *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=updated_after,json=updatedAfter,proto3" json:"updated_after,omitempty"`
+	UpdatedBefore *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=updated_before,json=updatedBefore,proto3" json:"updated_before,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProductFilter) Reset() {
+	*x = ProductFilter{}
+	mi := &file_api_v1_product_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProductFilter) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProductFilter) ProtoMessage() {}
+
+func (x *ProductFilter) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_product_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProductFilter.ProtoReflect.Descriptor instead.
+func (*ProductFilter) Descriptor() ([]byte, []int) {
+	return file_api_v1_product_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ProductFilter) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ProductFilter) GetMinPrice() float64 {
+	if x != nil && x.MinPrice != nil {
+		return *x.MinPrice
+	}
+	return 0
+}
+
+func (x *ProductFilter) GetMaxPrice() float64 {
+	if x != nil && x.MaxPrice != nil {
+		return *x.MaxPrice
+	}
+	return 0
+}
+
+func (x *ProductFilter) GetCreatedAfter() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAfter
+	}
+	return nil
+}
+
+func (x *ProductFilter) GetCreatedBefore() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedBefore
+	}
+	return nil
+}
+
+func (x *ProductFilter) GetUpdatedAfter() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAfter
+	}
+	return nil
+}
+
+func (x *ProductFilter) GetUpdatedBefore() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedBefore
+	}
+	return nil
 }
 
 type ListProductsResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Products      []*Product             `protobuf:"bytes,1,rep,name=products,proto3" json:"products,omitempty"`
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Products []*Product             `protobuf:"bytes,1,rep,name=products,proto3" json:"products,omitempty"`
+	// Empty when there are no more pages.
+	NextPageToken string `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	// Number of products matching the filter across all pages.
+	TotalSize     int32 `protobuf:"varint,3,opt,name=total_size,json=totalSize,proto3" json:"total_size,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *ListProductsResponse) Reset() {
 	*x = ListProductsResponse{}
-	mi := &file_api_v1_product_proto_msgTypes[4]
+	mi := &file_api_v1_product_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -317,7 +502,7 @@ func (x *ListProductsResponse) String() string {
 func (*ListProductsResponse) ProtoMessage() {}
 
 func (x *ListProductsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_api_v1_product_proto_msgTypes[4]
+	mi := &file_api_v1_product_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -330,7 +515,7 @@ func (x *ListProductsResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListProductsResponse.ProtoReflect.Descriptor instead.
 func (*ListProductsResponse) Descriptor() ([]byte, []int) {
-	return file_api_v1_product_proto_rawDescGZIP(), []int{4}
+	return file_api_v1_product_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *ListProductsResponse) GetProducts() []*Product {
@@ -340,6 +525,190 @@ func (x *ListProductsResponse) GetProducts() []*Product {
 	return nil
 }
 
+func (x *ListProductsResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+func (x *ListProductsResponse) GetTotalSize() int32 {
+	if x != nil {
+		return x.TotalSize
+	}
+	return 0
+}
+
+type SearchProductsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Search terms matched against product names and descriptions. Every term
+	// must match and each one is treated as a prefix.
+	Q string `protobuf:"bytes,1,opt,name=q,proto3" json:"q,omitempty"`
+	// Maximum number of results to return. Defaults to 20, capped at 100.
+	PageSize      int32 `protobuf:"varint,2,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchProductsRequest) Reset() {
+	*x = SearchProductsRequest{}
+	mi := &file_api_v1_product_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchProductsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchProductsRequest) ProtoMessage() {}
+
+func (x *SearchProductsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_product_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchProductsRequest.ProtoReflect.Descriptor instead.
+func (*SearchProductsRequest) Descriptor() ([]byte, []int) {
+	return file_api_v1_product_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *SearchProductsRequest) GetQ() string {
+	if x != nil {
+		return x.Q
+	}
+	return ""
+}
+
+func (x *SearchProductsRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+type ProductSearchResult struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Product *Product               `protobuf:"bytes,1,opt,name=product,proto3" json:"product,omitempty"`
+	// Product name with the matched terms wrapped in <mark></mark>.
+	NameHighlight string `protobuf:"bytes,2,opt,name=name_highlight,json=nameHighlight,proto3" json:"name_highlight,omitempty"`
+	// Fragment of the description around the matched terms, wrapped the same way.
+	DescriptionSnippet string `protobuf:"bytes,3,opt,name=description_snippet,json=descriptionSnippet,proto3" json:"description_snippet,omitempty"`
+	// Relevance of the match, higher is better.
+	Score         float64 `protobuf:"fixed64,4,opt,name=score,proto3" json:"score,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProductSearchResult) Reset() {
+	*x = ProductSearchResult{}
+	mi := &file_api_v1_product_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProductSearchResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProductSearchResult) ProtoMessage() {}
+
+func (x *ProductSearchResult) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_product_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProductSearchResult.ProtoReflect.Descriptor instead.
+func (*ProductSearchResult) Descriptor() ([]byte, []int) {
+	return file_api_v1_product_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ProductSearchResult) GetProduct() *Product {
+	if x != nil {
+		return x.Product
+	}
+	return nil
+}
+
+func (x *ProductSearchResult) GetNameHighlight() string {
+	if x != nil {
+		return x.NameHighlight
+	}
+	return ""
+}
+
+func (x *ProductSearchResult) GetDescriptionSnippet() string {
+	if x != nil {
+		return x.DescriptionSnippet
+	}
+	return ""
+}
+
+func (x *ProductSearchResult) GetScore() float64 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
+type SearchProductsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Results       []*ProductSearchResult `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchProductsResponse) Reset() {
+	*x = SearchProductsResponse{}
+	mi := &file_api_v1_product_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchProductsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchProductsResponse) ProtoMessage() {}
+
+func (x *SearchProductsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_product_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchProductsResponse.ProtoReflect.Descriptor instead.
+func (*SearchProductsResponse) Descriptor() ([]byte, []int) {
+	return file_api_v1_product_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *SearchProductsResponse) GetResults() []*ProductSearchResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
 type DeleteProductRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
@@ -349,7 +718,7 @@ type DeleteProductRequest struct {
 
 func (x *DeleteProductRequest) Reset() {
 	*x = DeleteProductRequest{}
-	mi := &file_api_v1_product_proto_msgTypes[5]
+	mi := &file_api_v1_product_proto_msgTypes[10]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -361,7 +730,7 @@ func (x *DeleteProductRequest) String() string {
 func (*DeleteProductRequest) ProtoMessage() {}
 
 func (x *DeleteProductRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_v1_product_proto_msgTypes[5]
+	mi := &file_api_v1_product_proto_msgTypes[10]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -374,7 +743,7 @@ func (x *DeleteProductRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DeleteProductRequest.ProtoReflect.Descriptor instead.
 func (*DeleteProductRequest) Descriptor() ([]byte, []int) {
-	return file_api_v1_product_proto_rawDescGZIP(), []int{5}
+	return file_api_v1_product_proto_rawDescGZIP(), []int{10}
 }
 
 func (x *DeleteProductRequest) GetId() int64 {
@@ -388,7 +757,7 @@ var File_api_v1_product_proto protoreflect.FileDescriptor
 
 const file_api_v1_product_proto_rawDesc = "" +
 	"\n" +
-	"\x14api/v1/product.proto\x12\x06api.v1\x1a\x1cgoogle/api/annotations.proto\x1a\x1fgoogle/protobuf/timestamp.proto\x1a\x1bgoogle/protobuf/empty.proto\"\xf1\x01\n" +
+	"\x14api/v1/product.proto\x12\x06api.v1\x1a\x1cgoogle/api/annotations.proto\x1a\x1fgoogle/protobuf/timestamp.proto\x1a\x1bgoogle/protobuf/empty.proto\"\x9a\x02\n" +
 	"\aProduct\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x12\n" +
 	"\x04name\x18\x02 \x01(\tR\x04name\x12 \n" +
@@ -398,7 +767,8 @@ const file_api_v1_product_proto_rawDesc = "" +
 	"\n" +
 	"created_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
 	"\n" +
-	"updated_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"x\n" +
+	"updated_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x12'\n" +
+	"\x0forganization_id\x18\b \x01(\x03R\x0eorganizationId\"x\n" +
 	"\x14CreateProductRequest\x12\x12\n" +
 	"\x04name\x18\x01 \x01(\tR\x04name\x12 \n" +
 	"\vdescription\x18\x02 \x01(\tR\vdescription\x12\x14\n" +
@@ -409,16 +779,51 @@ const file_api_v1_product_proto_rawDesc = "" +
 	"\x04name\x18\x02 \x01(\tR\x04name\x12 \n" +
 	"\vdescription\x18\x03 \x01(\tR\vdescription\x12\x14\n" +
 	"\x05price\x18\x04 \x01(\x01R\x05price\x12\x14\n" +
-	"\x05cover\x18\x05 \x01(\tR\x05cover\"\x15\n" +
-	"\x13ListProductsRequest\"C\n" +
+	"\x05cover\x18\x05 \x01(\tR\x05cover\"#\n" +
+	"\x11GetProductRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\"\x9b\x01\n" +
+	"\x13ListProductsRequest\x12\x1b\n" +
+	"\tpage_size\x18\x01 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x02 \x01(\tR\tpageToken\x12\x19\n" +
+	"\border_by\x18\x03 \x01(\tR\aorderBy\x12-\n" +
+	"\x06filter\x18\x04 \x01(\v2\x15.api.v1.ProductFilterR\x06filter\"\x8b\x03\n" +
+	"\rProductFilter\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12 \n" +
+	"\tmin_price\x18\x02 \x01(\x01H\x00R\bminPrice\x88\x01\x01\x12 \n" +
+	"\tmax_price\x18\x03 \x01(\x01H\x01R\bmaxPrice\x88\x01\x01\x12?\n" +
+	"\rcreated_after\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\fcreatedAfter\x12A\n" +
+	"\x0ecreated_before\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\rcreatedBefore\x12?\n" +
+	"\rupdated_after\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\fupdatedAfter\x12A\n" +
+	"\x0eupdated_before\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\rupdatedBeforeB\f\n" +
+	"\n" +
+	"_min_priceB\f\n" +
+	"\n" +
+	"_max_price\"\x8a\x01\n" +
 	"\x14ListProductsResponse\x12+\n" +
-	"\bproducts\x18\x01 \x03(\v2\x0f.api.v1.ProductR\bproducts\"&\n" +
+	"\bproducts\x18\x01 \x03(\v2\x0f.api.v1.ProductR\bproducts\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\x12\x1d\n" +
+	"\n" +
+	"total_size\x18\x03 \x01(\x05R\ttotalSize\"B\n" +
+	"\x15SearchProductsRequest\x12\f\n" +
+	"\x01q\x18\x01 \x01(\tR\x01q\x12\x1b\n" +
+	"\tpage_size\x18\x02 \x01(\x05R\bpageSize\"\xae\x01\n" +
+	"\x13ProductSearchResult\x12)\n" +
+	"\aproduct\x18\x01 \x01(\v2\x0f.api.v1.ProductR\aproduct\x12%\n" +
+	"\x0ename_highlight\x18\x02 \x01(\tR\rnameHighlight\x12/\n" +
+	"\x13description_snippet\x18\x03 \x01(\tR\x12descriptionSnippet\x12\x14\n" +
+	"\x05score\x18\x04 \x01(\x01R\x05score\"O\n" +
+	"\x16SearchProductsResponse\x125\n" +
+	"\aresults\x18\x01 \x03(\v2\x1b.api.v1.ProductSearchResultR\aresults\"&\n" +
 	"\x14DeleteProductRequest\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\x03R\x02id2\x8a\x03\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id2\xcd\x04\n" +
 	"\x0eProductService\x12W\n" +
 	"\rCreateProduct\x12\x1c.api.v1.CreateProductRequest\x1a\x0f.api.v1.Product\"\x17\x82\xd3\xe4\x93\x02\x11:\x01*\"\f/v1/products\x12\\\n" +
-	"\rUpdateProduct\x12\x1c.api.v1.UpdateProductRequest\x1a\x0f.api.v1.Product\"\x1c\x82\xd3\xe4\x93\x02\x16:\x01*2\x11/v1/products/{id}\x12_\n" +
-	"\fListProducts\x12\x1b.api.v1.ListProductsRequest\x1a\x1c.api.v1.ListProductsResponse\"\x14\x82\xd3\xe4\x93\x02\x0e\x12\f/v1/products\x12`\n" +
+	"\rUpdateProduct\x12\x1c.api.v1.UpdateProductRequest\x1a\x0f.api.v1.Product\"\x1c\x82\xd3\xe4\x93\x02\x16:\x01*2\x11/v1/products/{id}\x12S\n" +
+	"\n" +
+	"GetProduct\x12\x19.api.v1.GetProductRequest\x1a\x0f.api.v1.Product\"\x19\x82\xd3\xe4\x93\x02\x13\x12\x11/v1/products/{id}\x12_\n" +
+	"\fListProducts\x12\x1b.api.v1.ListProductsRequest\x1a\x1c.api.v1.ListProductsResponse\"\x14\x82\xd3\xe4\x93\x02\x0e\x12\f/v1/products\x12l\n" +
+	"\x0eSearchProducts\x12\x1d.api.v1.SearchProductsRequest\x1a\x1e.api.v1.SearchProductsResponse\"\x1b\x82\xd3\xe4\x93\x02\x15\x12\x13/v1/products:search\x12`\n" +
 	"\rDeleteProduct\x12\x1c.api.v1.DeleteProductRequest\x1a\x16.google.protobuf.Empty\"\x19\x82\xd3\xe4\x93\x02\x13*\x11/v1/products/{id}B\x83\x01\n" +
 	"\n" +
 	"com.api.v1B\fProductProtoP\x01Z.github.com/thetnaingtn/dirty-hand/api/v1;apiv1\xa2\x02\x03AXX\xaa\x02\x06Api.V1\xca\x02\x06Api\\V1\xe2\x02\x12Api\\V1\\GPBMetadata\xea\x02\aApi::V1b\x06proto3"
@@ -435,34 +840,50 @@ func file_api_v1_product_proto_rawDescGZIP() []byte {
 	return file_api_v1_product_proto_rawDescData
 }
 
-var file_api_v1_product_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
+var file_api_v1_product_proto_msgTypes = make([]protoimpl.MessageInfo, 11)
 var file_api_v1_product_proto_goTypes = []any{
-	(*Product)(nil),               // 0: api.v1.Product
-	(*CreateProductRequest)(nil),  // 1: api.v1.CreateProductRequest
-	(*UpdateProductRequest)(nil),  // 2: api.v1.UpdateProductRequest
-	(*ListProductsRequest)(nil),   // 3: api.v1.ListProductsRequest
-	(*ListProductsResponse)(nil),  // 4: api.v1.ListProductsResponse
-	(*DeleteProductRequest)(nil),  // 5: api.v1.DeleteProductRequest
-	(*timestamppb.Timestamp)(nil), // 6: google.protobuf.Timestamp
-	(*emptypb.Empty)(nil),         // 7: google.protobuf.Empty
+	(*Product)(nil),                // 0: api.v1.Product
+	(*CreateProductRequest)(nil),   // 1: api.v1.CreateProductRequest
+	(*UpdateProductRequest)(nil),   // 2: api.v1.UpdateProductRequest
+	(*GetProductRequest)(nil),      // 3: api.v1.GetProductRequest
+	(*ListProductsRequest)(nil),    // 4: api.v1.ListProductsRequest
+	(*ProductFilter)(nil),          // 5: api.v1.ProductFilter
+	(*ListProductsResponse)(nil),   // 6: api.v1.ListProductsResponse
+	(*SearchProductsRequest)(nil),  // 7: api.v1.SearchProductsRequest
+	(*ProductSearchResult)(nil),    // 8: api.v1.ProductSearchResult
+	(*SearchProductsResponse)(nil), // 9: api.v1.SearchProductsResponse
+	(*DeleteProductRequest)(nil),   // 10: api.v1.DeleteProductRequest
+	(*timestamppb.Timestamp)(nil),  // 11: google.protobuf.Timestamp
+	(*emptypb.Empty)(nil),          // 12: google.protobuf.Empty
 }
 var file_api_v1_product_proto_depIdxs = []int32{
-	6, // 0: api.v1.Product.created_at:type_name -> google.protobuf.Timestamp
-	6, // 1: api.v1.Product.updated_at:type_name -> google.protobuf.Timestamp
-	0, // 2: api.v1.ListProductsResponse.products:type_name -> api.v1.Product
-	1, // 3: api.v1.ProductService.CreateProduct:input_type -> api.v1.CreateProductRequest
-	2, // 4: api.v1.ProductService.UpdateProduct:input_type -> api.v1.UpdateProductRequest
-	3, // 5: api.v1.ProductService.ListProducts:input_type -> api.v1.ListProductsRequest
-	5, // 6: api.v1.ProductService.DeleteProduct:input_type -> api.v1.DeleteProductRequest
-	0, // 7: api.v1.ProductService.CreateProduct:output_type -> api.v1.Product
-	0, // 8: api.v1.ProductService.UpdateProduct:output_type -> api.v1.Product
-	4, // 9: api.v1.ProductService.ListProducts:output_type -> api.v1.ListProductsResponse
-	7, // 10: api.v1.ProductService.DeleteProduct:output_type -> google.protobuf.Empty
-	7, // [7:11] is the sub-list for method output_type
-	3, // [3:7] is the sub-list for method input_type
-	3, // [3:3] is the sub-list for extension type_name
-	3, // [3:3] is the sub-list for extension extendee
-	0, // [0:3] is the sub-list for field type_name
+	11, // 0: api.v1.Product.created_at:type_name -> google.protobuf.Timestamp
+	11, // 1: api.v1.Product.updated_at:type_name -> google.protobuf.Timestamp
+	5,  // 2: api.v1.ListProductsRequest.filter:type_name -> api.v1.ProductFilter
+	11, // 3: api.v1.ProductFilter.created_after:type_name -> google.protobuf.Timestamp
+	11, // 4: api.v1.ProductFilter.created_before:type_name -> google.protobuf.Timestamp
+	11, // 5: api.v1.ProductFilter.updated_after:type_name -> google.protobuf.Timestamp
+	11, // 6: api.v1.ProductFilter.updated_before:type_name -> google.protobuf.Timestamp
+	0,  // 7: api.v1.ListProductsResponse.products:type_name -> api.v1.Product
+	0,  // 8: api.v1.ProductSearchResult.product:type_name -> api.v1.Product
+	8,  // 9: api.v1.SearchProductsResponse.results:type_name -> api.v1.ProductSearchResult
+	1,  // 10: api.v1.ProductService.CreateProduct:input_type -> api.v1.CreateProductRequest
+	2,  // 11: api.v1.ProductService.UpdateProduct:input_type -> api.v1.UpdateProductRequest
+	3,  // 12: api.v1.ProductService.GetProduct:input_type -> api.v1.GetProductRequest
+	4,  // 13: api.v1.ProductService.ListProducts:input_type -> api.v1.ListProductsRequest
+	7,  // 14: api.v1.ProductService.SearchProducts:input_type -> api.v1.SearchProductsRequest
+	10, // 15: api.v1.ProductService.DeleteProduct:input_type -> api.v1.DeleteProductRequest
+	0,  // 16: api.v1.ProductService.CreateProduct:output_type -> api.v1.Product
+	0,  // 17: api.v1.ProductService.UpdateProduct:output_type -> api.v1.Product
+	0,  // 18: api.v1.ProductService.GetProduct:output_type -> api.v1.Product
+	6,  // 19: api.v1.ProductService.ListProducts:output_type -> api.v1.ListProductsResponse
+	9,  // 20: api.v1.ProductService.SearchProducts:output_type -> api.v1.SearchProductsResponse
+	12, // 21: api.v1.ProductService.DeleteProduct:output_type -> google.protobuf.Empty
+	16, // [16:22] is the sub-list for method output_type
+	10, // [10:16] is the sub-list for method input_type
+	10, // [10:10] is the sub-list for extension type_name
+	10, // [10:10] is the sub-list for extension extendee
+	0,  // [0:10] is the sub-list for field type_name
 }
 
 func init() { file_api_v1_product_proto_init() }
@@ -470,13 +891,14 @@ func file_api_v1_product_proto_init() {
 	if File_api_v1_product_proto != nil {
 		return
 	}
+	file_api_v1_product_proto_msgTypes[5].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_api_v1_product_proto_rawDesc), len(file_api_v1_product_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   6,
+			NumMessages:   11,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
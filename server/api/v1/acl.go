@@ -3,12 +3,12 @@ package v1
 import (
 	"context"
 	"errors"
-	"fmt"
 	"net/http"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/thetnaingtn/dirty-hand/internal/config"
 	"github.com/thetnaingtn/dirty-hand/store"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
@@ -21,21 +21,68 @@ type ContextKey int
 const (
 	userIdContextKey ContextKey = iota
 	sessionIdContextKey
+	accessTokenContextKey
+	organizationIdContextKey
+	auditCallContextKey
 )
 
+// organizationIdMetadataKey names the metadata, or HTTP header, that selects
+// the organization a product call acts on.
+const organizationIdMetadataKey = "x-organization-id"
+
 var authticationAllowListMethods = map[string]bool{
 	"/api.v1.UserService/CreateUser":    true,
 	"/api.v1.UserService/CreateSession": true,
+	"/api.v1.UserService/ResetPassword": true,
+
+	"/api.v1.UserService/VerifySecondFactor": true,
+}
+
+// methodRequiredRoles maps every method that needs authentication to the role
+// required to call it. An empty role only requires the caller to be signed in.
+// Methods missing from the table are restricted to admins, and admins may call
+// every method.
+var methodRequiredRoles = map[string]store.Role{
+	"/api.v1.ProductService/GetProduct":     store.RoleProductView,
+	"/api.v1.ProductService/ListProducts":   store.RoleProductView,
+	"/api.v1.ProductService/SearchProducts": store.RoleProductView,
+	"/api.v1.ProductService/CreateProduct":  store.RoleProductEdit,
+	"/api.v1.ProductService/UpdateProduct":  store.RoleProductEdit,
+	"/api.v1.ProductService/DeleteProduct":  store.RoleProductEdit,
+
+	"/api.v1.UserService/DeleteSession":  "",
+	"/api.v1.UserService/ChangePassword": "",
+	"/api.v1.UserService/ListSessions":   "",
+	"/api.v1.UserService/RevokeSession":  "",
+
+	"/api.v1.UserService/EnrollTOTP":  "",
+	"/api.v1.UserService/VerifyTOTP":  "",
+	"/api.v1.UserService/DisableTOTP": "",
+
+	"/api.v1.OrganizationService/ListOrganizations": "",
+
+	"/api.v1.UserService/CreateAccessToken": "",
+	"/api.v1.UserService/ListAccessTokens":  "",
+	"/api.v1.UserService/RevokeAccessToken": "",
 }
 
 type GRPCAuthInterceptor struct {
-	store *store.Store
+	store       *store.Store
+	config      *config.Config
+	cookieCodec *sessionCookieCodec
 }
 
-func NewGRPCAuthInterceptor(store *store.Store) *GRPCAuthInterceptor {
-	return &GRPCAuthInterceptor{
-		store: store,
+func NewGRPCAuthInterceptor(store *store.Store, config *config.Config) (*GRPCAuthInterceptor, error) {
+	cookieCodec, err := newSessionCookieCodec(config.Session)
+	if err != nil {
+		return nil, err
 	}
+
+	return &GRPCAuthInterceptor{
+		store:       store,
+		config:      config,
+		cookieCodec: cookieCodec,
+	}, nil
 }
 
 func (in *GRPCAuthInterceptor) AuthenticateInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
@@ -44,23 +91,58 @@ func (in *GRPCAuthInterceptor) AuthenticateInterceptor(ctx context.Context, req
 		return nil, status.Error(codes.Unauthenticated, "failed to parse metadata")
 	}
 
+	// A bearer token is an explicit credential, so an invalid one is rejected
+	// instead of falling back to the session cookie.
+	if token := getBearerTokenFromMetadata(md); token != "" {
+		user, accessToken, err := in.authenticateByAccessToken(ctx, token)
+		if err != nil {
+			return nil, err
+		}
+
+		ctx = context.WithValue(ctx, userIdContextKey, user.ID)
+		ctx = context.WithValue(ctx, accessTokenContextKey, accessToken)
+		setAuditActor(ctx, user.ID)
+
+		if err := in.store.UpdateAccessTokenLastUsedTime(ctx, accessToken.ID, time.Now()); err != nil {
+			return nil, status.Error(codes.Internal, "failed to update access token last used time")
+		}
+
+		var role store.Role
+		ctx, role, err = in.withOrganization(ctx, md, info.FullMethod, user)
+		if err != nil {
+			return nil, err
+		}
+
+		if !isUnauthorizeAllowMethod(info.FullMethod) && !isMethodPermittedForToken(info.FullMethod, role, accessToken.Scopes) {
+			return nil, status.Errorf(codes.PermissionDenied, "access token is not allowed to call %s", info.FullMethod)
+		}
+
+		return handler(ctx, req)
+	}
+
 	if sessionCookieValue, err := getSessionIDFromMetadata(md); err == nil && sessionCookieValue != "" {
-		user, err := in.authenticateBySession(ctx, sessionCookieValue)
+		user, sessionId, err := in.authenticateBySession(ctx, sessionCookieValue)
 		if err == nil && user != nil {
-			_, sessionId, parsedErr := parseSessionCookieValue(sessionCookieValue)
-			if parsedErr != nil {
-				return nil, status.Error(codes.Unauthenticated, "failed to parsed session cookie")
-			}
-
 			ctx = context.WithValue(ctx, userIdContextKey, user.ID)
+			setAuditActor(ctx, user.ID)
 			if sessionId != "" {
 				ctx = context.WithValue(ctx, sessionIdContextKey, sessionId)
 			}
 
-			if err := in.updateLastAccessedTime(ctx, sessionId); err != nil {
+			if err := in.updateLastAccessedTime(ctx, user.ID, sessionId); err != nil {
 				return nil, status.Error(codes.Internal, "failed to update last accessed time")
 			}
 
+			var role store.Role
+			ctx, role, err = in.withOrganization(ctx, md, info.FullMethod, user)
+			if err != nil {
+				return nil, err
+			}
+
+			if !isUnauthorizeAllowMethod(info.FullMethod) && !isMethodPermitted(info.FullMethod, role) {
+				return nil, status.Errorf(codes.PermissionDenied, "role %q is not allowed to call %s", role, info.FullMethod)
+			}
+
 			return handler(ctx, req)
 		}
 
@@ -73,64 +155,163 @@ func (in *GRPCAuthInterceptor) AuthenticateInterceptor(ctx context.Context, req
 	return nil, status.Error(codes.Unauthenticated, "authentication required")
 }
 
-func (in *GRPCAuthInterceptor) updateLastAccessedTime(ctx context.Context, sessionId string) error {
-	return in.store.UpdateLastAccessedTime(ctx, sessionId, time.Now())
+// withOrganization resolves the active organization of a product call and
+// returns the role the user holds in it, which replaces their own role for
+// the call. Other calls are returned unchanged with the user's role.
+func (in *GRPCAuthInterceptor) withOrganization(ctx context.Context, md metadata.MD, method string, user *store.User) (context.Context, store.Role, error) {
+	if !isOrganizationScopedMethod(method) {
+		return ctx, user.Role, nil
+	}
+
+	organizationId, role, err := in.resolveOrganization(ctx, md, user)
+	if err != nil {
+		return nil, "", err
+	}
+
+	setAuditOrganization(ctx, organizationId)
+
+	return context.WithValue(ctx, organizationIdContextKey, organizationId), role, nil
 }
 
-func (in *GRPCAuthInterceptor) authenticateBySession(ctx context.Context, sessionCookieValue string) (*store.User, error) {
-	userId, sessionId, err := parseSessionCookieValue(sessionCookieValue)
+// resolveOrganization returns the organization selected by the metadata and
+// the user's role in it. Without the metadata the Default organization is
+// used. Admins act as admins of every organization.
+func (in *GRPCAuthInterceptor) resolveOrganization(ctx context.Context, md metadata.MD, user *store.User) (int64, store.Role, error) {
+	var organizationId int64
+	if values := md.Get(organizationIdMetadataKey); len(values) > 0 {
+		var err error
+		organizationId, err = strconv.ParseInt(strings.TrimSpace(values[0]), 10, 64)
+		if err != nil {
+			return 0, "", status.Errorf(codes.InvalidArgument, "invalid %s", organizationIdMetadataKey)
+		}
+
+		if user.Role == store.RoleAdmin {
+			organization, err := in.store.GetOrganization(ctx, &store.FindOrganization{ID: &organizationId})
+			if err != nil {
+				return 0, "", status.Error(codes.Internal, "failed to get organization")
+			}
+
+			if organization == nil {
+				return 0, "", status.Errorf(codes.NotFound, "organization %d not found", organizationId)
+			}
+		}
+	} else {
+		organization, err := in.store.GetDefaultOrganization(ctx)
+		if err != nil {
+			return 0, "", status.Error(codes.Internal, "failed to get the default organization")
+		}
 
+		organizationId = organization.ID
+	}
+
+	if user.Role == store.RoleAdmin {
+		return organizationId, store.RoleAdmin, nil
+	}
+
+	membership, err := in.store.GetMembership(ctx, &store.FindMembership{OrganizationID: &organizationId, UserID: &user.ID})
 	if err != nil {
-		return nil, status.Error(codes.Unauthenticated, "invalid session cookie format")
+		return 0, "", status.Error(codes.Internal, "failed to get membership")
+	}
+
+	// Organizations the user does not belong to are reported the same way
+	// whether they exist or not.
+	if membership == nil {
+		return 0, "", status.Errorf(codes.PermissionDenied, "not a member of organization %d", organizationId)
+	}
+
+	return organizationId, membership.Role, nil
+}
+
+func (in *GRPCAuthInterceptor) updateLastAccessedTime(ctx context.Context, userId int64, sessionId string) error {
+	return in.store.UpdateLastAccessedTime(ctx, userId, sessionId, time.Now())
+}
+
+// authenticateBySession returns the user and session ID identified by a
+// session cookie. The cookie is verified before anything is looked up.
+func (in *GRPCAuthInterceptor) authenticateBySession(ctx context.Context, sessionCookieValue string) (*store.User, string, error) {
+	userId, sessionId, err := in.cookieCodec.decode(sessionCookieValue)
+	if err != nil {
+		return nil, "", status.Error(codes.Unauthenticated, "invalid session cookie")
 	}
 
 	user, err := in.store.GetUser(ctx, &store.FindUser{
 		ID: &userId,
 	})
 	if err != nil {
-		return nil, status.Error(codes.Unauthenticated, "failed to get user")
+		return nil, "", status.Error(codes.Unauthenticated, "failed to get user")
 	}
 
 	if user == nil {
-		return nil, status.Error(codes.Unauthenticated, "user not found")
+		return nil, "", status.Error(codes.Unauthenticated, "user not found")
+	}
+
+	if user.Disabled {
+		return nil, "", status.Error(codes.Unauthenticated, "user is disabled")
 	}
 
 	sessions, err := in.store.GetUserSessions(ctx, userId)
+	if err != nil {
+		return nil, "", status.Error(codes.Unauthenticated, "failed to get user sessions")
+	}
 
 	if valid := in.validateUserSession(sessions, sessionId); !valid {
-		return nil, status.Error(codes.Unauthenticated, "session invalid or expired")
+		return nil, "", status.Error(codes.Unauthenticated, "session invalid or expired")
 	}
 
-	return user, nil
+	return user, sessionId, nil
+}
+
+// authenticateByAccessToken returns the owner of a personal access token
+// together with the token.
+func (in *GRPCAuthInterceptor) authenticateByAccessToken(ctx context.Context, token string) (*store.User, *store.AccessToken, error) {
+	if !strings.HasPrefix(token, accessTokenPrefix) {
+		return nil, nil, status.Error(codes.Unauthenticated, "invalid access token")
+	}
+
+	tokenHash := hashToken(token)
+	accessToken, err := in.store.GetAccessToken(ctx, &store.FindAccessToken{TokenHash: &tokenHash})
+	if err != nil {
+		return nil, nil, status.Error(codes.Internal, "failed to get access token")
+	}
+
+	if accessToken == nil {
+		return nil, nil, status.Error(codes.Unauthenticated, "invalid access token")
+	}
+
+	if accessToken.ExpiresAt != nil && accessToken.ExpiresAt.Before(time.Now()) {
+		return nil, nil, status.Error(codes.Unauthenticated, "access token expired")
+	}
+
+	user, err := in.store.GetUser(ctx, &store.FindUser{ID: &accessToken.UserID})
+	if err != nil {
+		return nil, nil, status.Error(codes.Internal, "failed to get user")
+	}
+
+	if user == nil || user.Disabled {
+		return nil, nil, status.Error(codes.Unauthenticated, "invalid access token")
+	}
+
+	return user, accessToken, nil
 }
 
 func (in *GRPCAuthInterceptor) validateUserSession(sessions []*store.Session, sessionId string) bool {
 	for _, session := range sessions {
 		if session != nil && session.SessionID == sessionId {
-			expiredTime := session.LastAccessedTime.Add(14 * 24 * time.Hour)
-			if expiredTime.Before(time.Now()) {
-				return false
-			} else {
-				return true
-			}
+			return !isSessionExpired(session, in.config.Session.IdleTimeout, time.Now())
 		}
 	}
 
 	return false
 }
 
-func parseSessionCookieValue(sessionId string) (int64, string, error) {
-	splits := strings.SplitN(sessionId, "-", 2)
-	if len(splits) > 2 {
-		return 0, "", errors.New("invalid session cookie format")
-	}
-
-	userId, err := strconv.ParseInt(splits[0], 10, 64)
-	if err != nil {
-		return 0, "", fmt.Errorf("invalid user id in cookie: %v", err)
+// isSessionExpired reports whether a session is past its idle timeout or
+// its absolute expiry.
+func isSessionExpired(session *store.Session, idleTimeout time.Duration, now time.Time) bool {
+	if idleTimeout > 0 && session.LastAccessedTime.Add(idleTimeout).Before(now) {
+		return true
 	}
 
-	return userId, splits[1], nil
+	return !session.ExpiresAt.IsZero() && session.ExpiresAt.Before(now)
 }
 
 func getSessionIDFromMetadata(md metadata.MD) (string, error) {
@@ -154,6 +335,69 @@ func getSessionIDFromMetadata(md metadata.MD) (string, error) {
 	return sessionId, nil
 }
 
+// getBearerTokenFromMetadata returns the token of an "authorization: Bearer"
+// header, or an empty string when there is none.
+func getBearerTokenFromMetadata(md metadata.MD) string {
+	for _, v := range md.Get("authorization") {
+		scheme, token, ok := strings.Cut(v, " ")
+		if ok && strings.EqualFold(scheme, "Bearer") {
+			return strings.TrimSpace(token)
+		}
+	}
+
+	return ""
+}
+
+// isOrganizationScopedMethod reports whether method acts on the data of one
+// organization.
+func isOrganizationScopedMethod(method string) bool {
+	return strings.HasPrefix(method, "/api.v1.ProductService/")
+}
+
 func isUnauthorizeAllowMethod(method string) bool {
 	return authticationAllowListMethods[method]
 }
+
+func isMethodPermitted(method string, role store.Role) bool {
+	if role == store.RoleAdmin {
+		return true
+	}
+
+	required, ok := methodRequiredRoles[method]
+	if !ok {
+		return false
+	}
+
+	return roleSatisfies(role, required)
+}
+
+// isMethodPermittedForToken reports whether an access token may call method.
+// Both the owner's role and one of the token's scopes must allow it.
+func isMethodPermittedForToken(method string, role store.Role, scopes []store.Role) bool {
+	if !isMethodPermitted(method, role) {
+		return false
+	}
+
+	for _, scope := range scopes {
+		if isMethodPermitted(method, scope) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// roleSatisfies reports whether role grants the permissions of required.
+// Admins hold every permission and editors may also view products.
+func roleSatisfies(role, required store.Role) bool {
+	switch {
+	case required == "":
+		return true
+	case role == store.RoleAdmin:
+		return true
+	case role == store.RoleProductEdit && required == store.RoleProductView:
+		return true
+	default:
+		return role == required
+	}
+}
@@ -0,0 +1,289 @@
+package v1
+
+import (
+	"context"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	apiv1 "github.com/thetnaingtn/dirty-hand/proto/gen/api/v1"
+	"github.com/thetnaingtn/dirty-hand/store"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestIsMethodPermitted(t *testing.T) {
+	tests := []struct {
+		method string
+		role   store.Role
+		want   bool
+	}{
+		{"/api.v1.ProductService/GetProduct", store.RoleProductView, true},
+		{"/api.v1.ProductService/SearchProducts", store.RoleProductView, true},
+		{"/api.v1.ProductService/CreateProduct", store.RoleProductView, false},
+		{"/api.v1.ProductService/DeleteProduct", store.RoleProductView, false},
+		{"/api.v1.ProductService/ListProducts", store.RoleProductEdit, true},
+		{"/api.v1.ProductService/UpdateProduct", store.RoleProductEdit, true},
+		{"/api.v1.ProductService/DeleteProduct", store.RoleAdmin, true},
+		{"/api.v1.ProductService/GetProduct", "", false},
+
+		{"/api.v1.UserService/ChangePassword", store.RoleProductView, true},
+		{"/api.v1.UserService/DeleteSession", store.RoleProductView, true},
+		{"/api.v1.UserService/DeleteSession", "", true},
+
+		// Methods missing from the table are restricted to admins.
+		{"/api.v1.UserService/ListUsers", store.RoleProductEdit, false},
+		{"/api.v1.UserService/ListUsers", store.RoleAdmin, true},
+		{"/api.v1.UserService/NotAMethod", store.RoleProductEdit, false},
+	}
+
+	for _, test := range tests {
+		if got := isMethodPermitted(test.method, test.role); got != test.want {
+			t.Errorf("isMethodPermitted(%s, %q) = %t, want %t", test.method, test.role, got, test.want)
+		}
+	}
+}
+
+// TestMethodRequiredRoles checks that the role table names real methods, and
+// that a method added to a service is only left admin-only on purpose.
+func TestMethodRequiredRoles(t *testing.T) {
+	adminOnly := []string{
+		"/api.v1.AuditService/ListAuditEvents",
+		"/api.v1.OrganizationService/CreateOrganization",
+		"/api.v1.OrganizationService/DeleteOrganization",
+		"/api.v1.OrganizationService/DeleteMembership",
+		"/api.v1.OrganizationService/ListMemberships",
+		"/api.v1.OrganizationService/SetMembership",
+		"/api.v1.UserService/CreateInvitation",
+		"/api.v1.UserService/CreatePasswordResetToken",
+		"/api.v1.UserService/DeleteInvitation",
+		"/api.v1.UserService/DeleteUser",
+		"/api.v1.UserService/DisableUser",
+		"/api.v1.UserService/GetUser",
+		"/api.v1.UserService/ListInvitations",
+		"/api.v1.UserService/ListUsers",
+		"/api.v1.UserService/UnlockUser",
+		"/api.v1.UserService/UpdateUserRole",
+	}
+
+	methods := map[string]bool{}
+	var gotAdminOnly []string
+	for _, service := range []grpc.ServiceDesc{
+		apiv1.AuditService_ServiceDesc,
+		apiv1.OrganizationService_ServiceDesc,
+		apiv1.ProductService_ServiceDesc,
+		apiv1.UserService_ServiceDesc,
+	} {
+		for _, method := range service.Methods {
+			fullMethod := "/" + service.ServiceName + "/" + method.MethodName
+			methods[fullMethod] = true
+
+			_, listed := methodRequiredRoles[fullMethod]
+			if !listed && !isUnauthorizeAllowMethod(fullMethod) {
+				gotAdminOnly = append(gotAdminOnly, fullMethod)
+			}
+		}
+	}
+
+	for method := range methodRequiredRoles {
+		if !methods[method] {
+			t.Errorf("methodRequiredRoles lists unknown method %s", method)
+		}
+	}
+	for method := range authticationAllowListMethods {
+		if !methods[method] {
+			t.Errorf("authticationAllowListMethods lists unknown method %s", method)
+		}
+	}
+
+	slices.Sort(adminOnly)
+	slices.Sort(gotAdminOnly)
+	if !slices.Equal(gotAdminOnly, adminOnly) {
+		t.Errorf("admin-only methods = %v, want %v", gotAdminOnly, adminOnly)
+	}
+}
+
+func TestIsMethodPermittedForToken(t *testing.T) {
+	tests := []struct {
+		method string
+		role   store.Role
+		scopes []store.Role
+		want   bool
+	}{
+		{"/api.v1.ProductService/GetProduct", store.RoleAdmin, []store.Role{store.RoleProductView}, true},
+		{"/api.v1.ProductService/DeleteProduct", store.RoleAdmin, []store.Role{store.RoleProductView}, false},
+		{"/api.v1.ProductService/DeleteProduct", store.RoleAdmin, []store.Role{store.RoleProductView, store.RoleProductEdit}, true},
+		{"/api.v1.UserService/ListUsers", store.RoleAdmin, []store.Role{store.RoleProductEdit}, false},
+		{"/api.v1.UserService/ListUsers", store.RoleAdmin, []store.Role{store.RoleAdmin}, true},
+		// A scope grants nothing its owner's role does not.
+		{"/api.v1.ProductService/CreateProduct", store.RoleProductView, []store.Role{store.RoleProductEdit}, false},
+		{"/api.v1.UserService/ListUsers", store.RoleProductEdit, []store.Role{store.RoleAdmin}, false},
+		{"/api.v1.ProductService/GetProduct", store.RoleProductEdit, nil, false},
+	}
+
+	for _, test := range tests {
+		if got := isMethodPermittedForToken(test.method, test.role, test.scopes); got != test.want {
+			t.Errorf("isMethodPermittedForToken(%s, %q, %v) = %t, want %t", test.method, test.role, test.scopes, got, test.want)
+		}
+	}
+}
+
+func TestAuthenticateInterceptorAccessToken(t *testing.T) {
+	s := newTestService(t, nil)
+	in, err := NewGRPCAuthInterceptor(&s.store, s.config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	admin := createTestUser(t, s, "admin", store.RoleAdmin)
+
+	token, err := s.CreateAccessToken(withUser(context.Background(), admin.ID), &apiv1.CreateAccessTokenRequest{
+		Name:   "read only",
+		Scopes: []apiv1.Role{apiv1.Role_PRODUCT_VIEWER},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	md := metadata.Pairs("authorization", "Bearer "+token.Token)
+	tests := []struct {
+		method string
+		md     metadata.MD
+		want   codes.Code
+	}{
+		{"/api.v1.ProductService/ListProducts", md, codes.OK},
+		{"/api.v1.ProductService/DeleteProduct", md, codes.PermissionDenied},
+		{"/api.v1.UserService/ListUsers", md, codes.PermissionDenied},
+		{"/api.v1.ProductService/ListProducts", metadata.Pairs("authorization", "Bearer "+accessTokenPrefix+"unknown"), codes.Unauthenticated},
+		// An invalid token does not fall back to the session cookie.
+		{"/api.v1.UserService/CreateSession", metadata.Pairs("authorization", "Bearer unknown"), codes.Unauthenticated},
+	}
+
+	for _, test := range tests {
+		if _, err := intercept(in.AuthenticateInterceptor, test.method, test.md); status.Code(err) != test.want {
+			t.Errorf("%s with %v = %v, want %s", test.method, test.md.Get("authorization"), err, test.want)
+		}
+	}
+}
+
+// intercept runs a unary interceptor for a call of method with the given
+// incoming metadata, and returns the context the handler was called with.
+func intercept(interceptor grpc.UnaryServerInterceptor, method string, md metadata.MD) (context.Context, error) {
+	var handled context.Context
+	ctx := metadata.NewIncomingContext(context.Background(), md)
+	_, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: method}, func(ctx context.Context, req any) (any, error) {
+		handled = ctx
+		return nil, nil
+	})
+
+	return handled, err
+}
+
+func TestAuthenticateInterceptorOrganization(t *testing.T) {
+	s := newTestService(t, nil)
+	in, err := NewGRPCAuthInterceptor(&s.store, s.config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	acme := createTestOrganization(t, s, "acme")
+	globex := createTestOrganization(t, s, "globex")
+	initech := createTestOrganization(t, s, "initech")
+
+	defaultOrganization, err := s.store.GetDefaultOrganization(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	admin := createTestUser(t, s, "admin", store.RoleAdmin)
+	// The role of a user in an organization replaces their own role.
+	alice := createTestUser(t, s, "alice", store.RoleProductEdit)
+	bob := createTestUser(t, s, "bob", store.RoleProductView)
+	// Users who sign up join the Default organization.
+	carol, err := s.CreateUser(ctx, &apiv1.CreateUserRequest{Username: "carol", Password: testPassword})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, membership := range []*store.Membership{
+		{OrganizationID: acme.ID, UserID: alice.ID, Role: store.RoleProductView},
+		{OrganizationID: globex.ID, UserID: alice.ID, Role: store.RoleProductEdit},
+		{OrganizationID: acme.ID, UserID: bob.ID, Role: store.RoleProductView},
+	} {
+		membership.CreatedTime = time.Now()
+		if _, err := s.store.UpsertMembership(ctx, membership); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	const (
+		getProduct    = "/api.v1.ProductService/GetProduct"
+		deleteProduct = "/api.v1.ProductService/DeleteProduct"
+	)
+	tests := []struct {
+		name             string
+		user             *store.User
+		organizationId   string
+		method           string
+		want             codes.Code
+		wantOrganization int64
+	}{
+		{"default organization", &store.User{ID: carol.Id}, "", getProduct, codes.OK, defaultOrganization.ID},
+		{"default organization's role", &store.User{ID: carol.Id}, "", deleteProduct, codes.PermissionDenied, 0},
+		{"default organization of a non-member", alice, "", getProduct, codes.PermissionDenied, 0},
+		{"selected organization", alice, strconv.FormatInt(globex.ID, 10), deleteProduct, codes.OK, globex.ID},
+		{"selected organization's role", alice, strconv.FormatInt(acme.ID, 10), deleteProduct, codes.PermissionDenied, 0},
+		{"padded id", alice, " " + strconv.FormatInt(acme.ID, 10) + " ", getProduct, codes.OK, acme.ID},
+		{"invalid id", alice, "acme", getProduct, codes.InvalidArgument, 0},
+		{"other organization", bob, strconv.FormatInt(globex.ID, 10), getProduct, codes.PermissionDenied, 0},
+		{"missing organization", bob, "999", getProduct, codes.PermissionDenied, 0},
+		{"admin in any organization", admin, strconv.FormatInt(initech.ID, 10), deleteProduct, codes.OK, initech.ID},
+		{"admin in a missing organization", admin, "999", getProduct, codes.NotFound, 0},
+		{"admin in the default organization", admin, "", deleteProduct, codes.OK, defaultOrganization.ID},
+		{"not a product call", alice, "acme", "/api.v1.UserService/ListSessions", codes.OK, 0},
+	}
+
+	for _, test := range tests {
+		md := metadata.Pairs("cookie", "user_session="+newTestSessionCookie(t, s, test.user.ID))
+		if test.organizationId != "" {
+			md.Set(organizationIdMetadataKey, test.organizationId)
+		}
+
+		handled, err := intercept(in.AuthenticateInterceptor, test.method, md)
+		if status.Code(err) != test.want {
+			t.Errorf("%s: %s = %v, want %s", test.name, test.method, err, test.want)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+
+		organizationId, ok := handled.Value(organizationIdContextKey).(int64)
+		if organizationId != test.wantOrganization || ok != (test.wantOrganization != 0) {
+			t.Errorf("%s: %s acts in organization %d, want %d", test.name, test.method, organizationId, test.wantOrganization)
+		}
+	}
+}
+
+// newTestSessionCookie signs the user in and returns the value of their
+// session cookie.
+func newTestSessionCookie(t *testing.T, s *APIV1Service, userId int64) string {
+	t.Helper()
+
+	ctx, stream := withCall(context.Background())
+	if _, err := s.doSignIn(ctx, userId, false); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, cookie := range stream.header.Get("Set-Cookie") {
+		if value, ok := strings.CutPrefix(cookie, "user_session="); ok {
+			value, _, _ = strings.Cut(value, ";")
+			return value
+		}
+	}
+
+	t.Fatal("doSignIn set no session cookie")
+	return ""
+}
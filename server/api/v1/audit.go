@@ -0,0 +1,163 @@
+package v1
+
+import (
+	"context"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/thetnaingtn/dirty-hand/internal/config"
+	"github.com/thetnaingtn/dirty-hand/store"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// auditCall holds what the interceptors learn about the caller of an audited
+// call. The auth interceptor runs inside the audit interceptor and fills it
+// in, so that calls it rejects are recorded with their actor as well.
+type auditCall struct {
+	actorId        *int64
+	organizationId *int64
+}
+
+type GRPCAuditInterceptor struct {
+	store          *store.Store
+	trustedProxies trustedProxies
+}
+
+func NewGRPCAuditInterceptor(store *store.Store, config *config.Config) (*GRPCAuditInterceptor, error) {
+	trustedProxies, err := newTrustedProxies(config.Server)
+	if err != nil {
+		return nil, err
+	}
+
+	return &GRPCAuditInterceptor{
+		store:          store,
+		trustedProxies: trustedProxies,
+	}, nil
+}
+
+// AuditInterceptor records an audit event for every mutating call, together
+// with the changes the store made while handling it.
+func (in *GRPCAuditInterceptor) AuditInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	if !isMutatingMethod(info.FullMethod) {
+		return handler(ctx, req)
+	}
+
+	call := &auditCall{}
+	ctx = context.WithValue(ctx, auditCallContextKey, call)
+	ctx, recorder := store.WithAuditRecorder(ctx)
+
+	resp, err := handler(ctx, req)
+
+	changes := recorder.Changes()
+	resourceType, resourceId := auditTarget(req, changes)
+	clientIP := in.trustedProxies.clientIP(ctx)
+
+	event := &store.AuditEvent{
+		CreatedTime:    time.Now(),
+		ActorID:        call.actorId,
+		OrganizationID: call.organizationId,
+		Method:         info.FullMethod,
+		ResourceType:   resourceType,
+		ResourceID:     resourceId,
+		Changes:        changes,
+		ClientIP:       clientIP,
+		Outcome:        status.Code(err).String(),
+	}
+	if err != nil {
+		event.ErrorMessage = status.Convert(err).Message()
+	}
+	if event.OrganizationID == nil {
+		if r, ok := req.(interface{ GetOrganizationId() int64 }); ok && r.GetOrganizationId() != 0 {
+			organizationId := r.GetOrganizationId()
+			event.OrganizationID = &organizationId
+		}
+	}
+
+	// The call has already been handled, so a failure to record it is only
+	// logged. The event is stored even when the client went away.
+	if _, err := in.store.CreateAuditEvent(context.WithoutCancel(ctx), event); err != nil {
+		slog.Error("failed to record audit event", "method", info.FullMethod, "error", err)
+	}
+
+	return resp, err
+}
+
+// setAuditActor records the user making an audited call.
+func setAuditActor(ctx context.Context, userId int64) {
+	if call, ok := ctx.Value(auditCallContextKey).(*auditCall); ok {
+		call.actorId = &userId
+	}
+}
+
+// setAuditOrganization records the organization an audited call acts on.
+func setAuditOrganization(ctx context.Context, organizationId int64) {
+	if call, ok := ctx.Value(auditCallContextKey).(*auditCall); ok {
+		call.organizationId = &organizationId
+	}
+}
+
+// isMutatingMethod reports whether method may change data. Methods that only
+// read are named Get, List or Search.
+func isMutatingMethod(method string) bool {
+	name := method[strings.LastIndex(method, "/")+1:]
+	for _, prefix := range []string{"Get", "List", "Search"} {
+		if strings.HasPrefix(name, prefix) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// auditTarget returns the resource a call targeted: the first resource it
+// changed or, when it changed nothing, the resource its request names. The
+// type of the latter is taken from the request message, e.g. "access_token"
+// for RevokeAccessTokenRequest.
+func auditTarget(req any, changes []store.AuditChange) (string, string) {
+	if len(changes) > 0 {
+		return changes[0].ResourceType, changes[0].ResourceID
+	}
+
+	var resourceId string
+	switch r := req.(type) {
+	case interface{ GetId() int64 }:
+		if r.GetId() != 0 {
+			resourceId = strconv.FormatInt(r.GetId(), 10)
+		}
+	case interface{ GetId() string }:
+		resourceId = r.GetId()
+	}
+
+	message, ok := req.(proto.Message)
+	if resourceId == "" || !ok {
+		return "", ""
+	}
+
+	return requestResourceType(string(message.ProtoReflect().Descriptor().Name())), resourceId
+}
+
+// requestResourceType turns a request message name such as
+// "RevokeAccessTokenRequest" into the snake case resource type
+// "access_token" by dropping the leading verb and the Request suffix.
+func requestResourceType(name string) string {
+	name = strings.TrimSuffix(name, "Request")
+
+	var b strings.Builder
+	for i, r := range name {
+		if i > 0 && unicode.IsUpper(r) {
+			if b.Len() > 0 {
+				b.WriteByte('_')
+			}
+			b.WriteRune(unicode.ToLower(r))
+		} else if b.Len() > 0 {
+			b.WriteRune(r)
+		}
+	}
+
+	return b.String()
+}
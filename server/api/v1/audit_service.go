@@ -0,0 +1,172 @@
+package v1
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"strconv"
+
+	apiv1 "github.com/thetnaingtn/dirty-hand/proto/gen/api/v1"
+	"github.com/thetnaingtn/dirty-hand/store"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	defaultAuditEventPageSize = 50
+	maxAuditEventPageSize     = 1000
+)
+
+func (s *APIV1Service) ListAuditEvents(ctx context.Context, req *apiv1.ListAuditEventsRequest) (*apiv1.ListAuditEventsResponse, error) {
+	pageSize := int(req.GetPageSize())
+	if pageSize <= 0 {
+		pageSize = defaultAuditEventPageSize
+	} else if pageSize > maxAuditEventPageSize {
+		pageSize = maxAuditEventPageSize
+	}
+
+	find := buildFindAuditEvent(req.GetFilter())
+
+	if req.GetPageToken() != "" {
+		beforeId, err := decodeAuditEventPageToken(req.GetPageToken())
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid page token: %v", err)
+		}
+		find.BeforeID = &beforeId
+	}
+
+	// Fetch one extra row to find out whether there is a next page.
+	limit := pageSize + 1
+	find.Limit = &limit
+
+	events, err := s.store.ListAuditEvents(ctx, find)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list audit events: %v", err)
+	}
+
+	resp := &apiv1.ListAuditEventsResponse{}
+	if len(events) > pageSize {
+		events = events[:pageSize]
+		resp.NextPageToken = encodeAuditEventPageToken(events[len(events)-1].ID)
+	}
+
+	resp.Events = make([]*apiv1.AuditEvent, 0, len(events))
+	for _, event := range events {
+		converted, err := convertAuditEventFromStore(event)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to convert audit event: %v", err)
+		}
+		resp.Events = append(resp.Events, converted)
+	}
+
+	return resp, nil
+}
+
+func buildFindAuditEvent(filter *apiv1.AuditEventFilter) *store.FindAuditEvent {
+	find := &store.FindAuditEvent{}
+	if filter == nil {
+		return find
+	}
+
+	if v := filter.GetActorId(); v != 0 {
+		find.ActorID = &v
+	}
+	if v := filter.GetOrganizationId(); v != 0 {
+		find.OrganizationID = &v
+	}
+	if v := filter.GetMethod(); v != "" {
+		find.Method = &v
+	}
+	if v := filter.GetResourceType(); v != "" {
+		find.ResourceType = &v
+	}
+	if v := filter.GetResourceId(); v != "" {
+		find.ResourceID = &v
+	}
+	if v := filter.GetOutcome(); v != "" {
+		find.Outcome = &v
+	}
+	find.CreatedAfter = timestampToTime(filter.GetCreatedAfter())
+	find.CreatedBefore = timestampToTime(filter.GetCreatedBefore())
+
+	return find
+}
+
+// encodeAuditEventPageToken returns the cursor handed out as next_page_token
+// for a page ending with the given event.
+func encodeAuditEventPageToken(lastId int64) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatInt(lastId, 10)))
+}
+
+func decodeAuditEventPageToken(token string) (int64, error) {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return 0, err
+	}
+
+	return strconv.ParseInt(string(data), 10, 64)
+}
+
+func convertAuditEventFromStore(event *store.AuditEvent) (*apiv1.AuditEvent, error) {
+	resp := &apiv1.AuditEvent{
+		Id:           event.ID,
+		CreateTime:   timestamppb.New(event.CreatedTime),
+		Method:       event.Method,
+		ResourceType: event.ResourceType,
+		ResourceId:   event.ResourceID,
+		ClientIp:     event.ClientIP,
+		Outcome:      event.Outcome,
+		ErrorMessage: event.ErrorMessage,
+	}
+	if event.ActorID != nil {
+		resp.ActorId = *event.ActorID
+	}
+	if event.OrganizationID != nil {
+		resp.OrganizationId = *event.OrganizationID
+	}
+
+	for _, change := range event.Changes {
+		before, err := auditSnapshotToStruct(change.Before)
+		if err != nil {
+			return nil, err
+		}
+
+		after, err := auditSnapshotToStruct(change.After)
+		if err != nil {
+			return nil, err
+		}
+
+		resp.Changes = append(resp.Changes, &apiv1.AuditChange{
+			ResourceType: change.ResourceType,
+			ResourceId:   change.ResourceID,
+			Action:       change.Action,
+			Before:       before,
+			After:        after,
+		})
+	}
+
+	return resp, nil
+}
+
+// auditSnapshotToStruct converts a snapshot through JSON, which accepts the
+// slices and integers that structpb.NewStruct does not.
+func auditSnapshotToStruct(snapshot map[string]any) (*structpb.Struct, error) {
+	if snapshot == nil {
+		return nil, nil
+	}
+
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &structpb.Struct{}
+	if err := result.UnmarshalJSON(data); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
@@ -0,0 +1,184 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+
+	apiv1 "github.com/thetnaingtn/dirty-hand/proto/gen/api/v1"
+	"github.com/thetnaingtn/dirty-hand/store"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// auditCallFrom runs the audit interceptor for a call of method from the
+// peer address, with the given x-forwarded-for metadata, and returns the
+// event it recorded.
+func auditCallFrom(t *testing.T, s *APIV1Service, method, peerAddr string, forwardedFor []string, req any, handler grpc.UnaryHandler) (*store.AuditEvent, error) {
+	t.Helper()
+
+	in, err := NewGRPCAuditInterceptor(&s.store, s.config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	addr, err := net.ResolveTCPAddr("tcp", peerAddr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	md := metadata.MD{}
+	for _, value := range forwardedFor {
+		md.Append("x-forwarded-for", value)
+	}
+	ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: addr})
+	ctx = metadata.NewIncomingContext(ctx, md)
+
+	_, callErr := in.AuditInterceptor(ctx, req, &grpc.UnaryServerInfo{FullMethod: method}, handler)
+
+	limit := 1
+	events, err := s.store.ListAuditEvents(context.Background(), &store.FindAuditEvent{Method: &method, Limit: &limit})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) == 0 {
+		return nil, callErr
+	}
+
+	return events[0], callErr
+}
+
+func TestAuditInterceptorRedactsPasswordHashes(t *testing.T) {
+	s := newTestService(t, nil)
+	user := createTestUser(t, s, "alice", store.RoleProductView)
+
+	const method = "/api.v1.UserService/ChangePassword"
+	req := &apiv1.ChangePasswordRequest{CurrentPassword: testPassword, NewPassword: "clementine-otter-4"}
+	event, err := auditCallFrom(t, s, method, "203.0.113.9:5000", nil, req, func(ctx context.Context, req any) (any, error) {
+		setAuditActor(ctx, user.ID)
+		return s.ChangePassword(withUser(ctx, user.ID), req.(*apiv1.ChangePasswordRequest))
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if event == nil || event.ActorID == nil || *event.ActorID != user.ID || event.Outcome != codes.OK.String() {
+		t.Fatalf("audit event = %+v, want a successful call by %d", event, user.ID)
+	}
+
+	if len(event.Changes) != 1 || event.Changes[0].ResourceType != "user" || event.Changes[0].Action != store.AuditActionUpdate {
+		t.Fatalf("audit changes = %+v, want an update of the user", event.Changes)
+	}
+	change := event.Changes[0]
+	if change.Before["password_hash"] != "[redacted]" || change.After["password_hash"] != "[redacted]" {
+		t.Errorf("audit change = %+v, want the password hash redacted", change)
+	}
+}
+
+func TestAuditInterceptorConsumeInvitation(t *testing.T) {
+	s, adminCtx := newInviteOnlyTestService(t)
+
+	invitation, err := s.CreateInvitation(adminCtx, &apiv1.CreateInvitationRequest{Role: apiv1.Role_PRODUCT_EDITOR})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const method = "/api.v1.UserService/CreateUser"
+	req := &apiv1.CreateUserRequest{Username: "bob", Password: testPassword, InvitationCode: invitation.Code}
+	var user *apiv1.User
+	event, err := auditCallFrom(t, s, method, "203.0.113.9:5000", nil, req, func(ctx context.Context, req any) (any, error) {
+		user, err = s.CreateUser(ctx, req.(*apiv1.CreateUserRequest))
+		return user, err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var change *store.AuditChange
+	for i := range event.Changes {
+		if event.Changes[i].ResourceType == "invitation" {
+			change = &event.Changes[i]
+		}
+	}
+	if change == nil || change.Action != store.AuditActionUpdate {
+		t.Fatalf("audit changes = %+v, want an update of the invitation", event.Changes)
+	}
+	// The invitation was unused before the call.
+	if _, ok := change.Before["used_by"]; !ok || change.Before["used_by"] != nil {
+		t.Errorf("audit change before = %+v, want no used_by", change.Before)
+	}
+	if usedBy, ok := change.After["used_by"].(float64); !ok || int64(usedBy) != user.Id {
+		t.Errorf("audit change after = %+v, want used_by %d", change.After, user.Id)
+	}
+}
+
+func TestAuditInterceptorSkipsRolledBackChanges(t *testing.T) {
+	s := newTestService(t, nil)
+	organization := createTestOrganization(t, s, "acme")
+
+	const method = "/api.v1.ProductService/CreateProduct"
+	errAbort := errors.New("abort")
+	event, err := auditCallFrom(t, s, method, "203.0.113.9:5000", nil, &apiv1.CreateProductRequest{Name: "Red kettle"}, func(ctx context.Context, req any) (any, error) {
+		err := s.store.WithTx(ctx, func(ctx context.Context) error {
+			if _, err := s.store.CreateProduct(ctx, &store.Product{OrganizationID: organization.ID, Name: "Red kettle"}); err != nil {
+				return err
+			}
+			return errAbort
+		})
+		return nil, status.Errorf(codes.Internal, "failed to create product: %v", err)
+	})
+	if status.Code(err) != codes.Internal {
+		t.Fatalf("AuditInterceptor = %v, want the handler's error", err)
+	}
+
+	if event == nil || event.Outcome != codes.Internal.String() || event.ErrorMessage == "" {
+		t.Fatalf("audit event = %+v, want a failed call", event)
+	}
+	if len(event.Changes) != 0 {
+		t.Errorf("audit changes = %+v, want none of the rolled back transaction", event.Changes)
+	}
+
+	organizationId := organization.ID
+	if products, err := s.store.ListProducts(context.Background(), &store.FindProduct{OrganizationID: &organizationId}); err != nil || len(products) != 0 {
+		t.Errorf("ListProducts = %+v, %v; want none", products, err)
+	}
+}
+
+func TestAuditInterceptorClientIP(t *testing.T) {
+	s := newTestService(t, nil)
+
+	tests := []struct {
+		name         string
+		peer         string
+		forwardedFor []string
+		want         string
+	}{
+		{"direct client", "203.0.113.9:5000", nil, "203.0.113.9"},
+		{"direct client forging x-forwarded-for", "203.0.113.9:5000", []string{"198.51.100.1"}, "203.0.113.9"},
+		{"gateway", "127.0.0.1:40000", []string{"198.51.100.1", "203.0.113.9"}, "203.0.113.9"},
+	}
+
+	const method = "/api.v1.UserService/DeleteSession"
+	for _, test := range tests {
+		event, _ := auditCallFrom(t, s, method, test.peer, test.forwardedFor, &apiv1.DeleteSessionRequest{}, func(ctx context.Context, req any) (any, error) {
+			return nil, nil
+		})
+		if event == nil || event.ClientIP != test.want {
+			t.Errorf("audit event of a %s = %+v, want client IP %s", test.name, event, test.want)
+		}
+	}
+}
+
+func TestAuditInterceptorSkipsReads(t *testing.T) {
+	s := newTestService(t, nil)
+
+	const method = "/api.v1.ProductService/ListProducts"
+	event, err := auditCallFrom(t, s, method, "203.0.113.9:5000", nil, &apiv1.ListProductsRequest{}, func(ctx context.Context, req any) (any, error) {
+		return nil, nil
+	})
+	if err != nil || event != nil {
+		t.Errorf("audit event of %s = %+v, %v; want none", method, event, err)
+	}
+}
@@ -0,0 +1,136 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+
+	"github.com/thetnaingtn/dirty-hand/internal/config"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+// trustedProxies are the peers whose X-Forwarded-For hops are believed. The
+// loopback addresses are always trusted, since the gateway connects to the
+// gRPC server from them.
+type trustedProxies []netip.Prefix
+
+func newTrustedProxies(cfg config.ServerConfig) (trustedProxies, error) {
+	var proxies trustedProxies
+
+	// The gateway connects from the configured address when it is not a
+	// loopback or unspecified one.
+	if addr, err := netip.ParseAddr(cfg.Addr); err == nil && !addr.IsUnspecified() {
+		proxies = append(proxies, hostPrefix(addr))
+	}
+
+	for _, proxy := range cfg.TrustedProxies {
+		prefix, err := netip.ParsePrefix(proxy)
+		if err != nil {
+			addr, addrErr := netip.ParseAddr(proxy)
+			if addrErr != nil {
+				return nil, fmt.Errorf("invalid trusted proxy %q, want an IP address or CIDR range", proxy)
+			}
+			prefix = hostPrefix(addr)
+		}
+		proxies = append(proxies, prefix.Masked())
+	}
+
+	return proxies, nil
+}
+
+// hostPrefix returns the prefix that only contains addr.
+func hostPrefix(addr netip.Addr) netip.Prefix {
+	addr = addr.Unmap()
+	return netip.PrefixFrom(addr, addr.BitLen())
+}
+
+func (p trustedProxies) contains(addr netip.Addr) bool {
+	if addr.IsLoopback() {
+		return true
+	}
+
+	for _, prefix := range p {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// clientIP returns the IP address of the client that made the request. It
+// is the address of the peer unless the peer is a trusted proxy, in which
+// case the x-forwarded-for hops are walked from the right and the first one
+// that is not a trusted proxy is the client. Hops the client sent itself are
+// left of that one, so they cannot change the result.
+func (p trustedProxies) clientIP(ctx context.Context) string {
+	pr, ok := peer.FromContext(ctx)
+	if !ok || pr.Addr == nil {
+		return ""
+	}
+
+	client, ok := parseIP(pr.Addr.String())
+	if !ok {
+		return ""
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	hops := forwardedFor(md)
+	for i := len(hops) - 1; i >= 0 && p.contains(client); i-- {
+		hop, ok := parseIP(hops[i])
+		if !ok {
+			break
+		}
+		client = hop
+	}
+
+	return client.String()
+}
+
+// isSecureRequest reports whether the client made the request over HTTPS.
+// The X-Forwarded-Proto header only counts when the request came from a
+// trusted proxy, clients could set it on a plain HTTP request otherwise.
+func (p trustedProxies) isSecureRequest(r *http.Request) bool {
+	if r.TLS != nil {
+		return true
+	}
+
+	peer, ok := parseIP(r.RemoteAddr)
+	if !ok || !p.contains(peer) {
+		return false
+	}
+
+	return strings.EqualFold(r.Header.Get("X-Forwarded-Proto"), "https")
+}
+
+// forwardedFor returns the hops of every x-forwarded-for value, nearest
+// last. The gateway appends the hop it received the request from to the
+// values that came with it.
+func forwardedFor(md metadata.MD) []string {
+	var hops []string
+	for _, value := range md.Get("x-forwarded-for") {
+		for _, hop := range strings.Split(value, ",") {
+			hops = append(hops, strings.TrimSpace(hop))
+		}
+	}
+
+	return hops
+}
+
+// parseIP parses an IP address with or without a port.
+func parseIP(s string) (netip.Addr, bool) {
+	if host, _, err := net.SplitHostPort(s); err == nil {
+		s = host
+	}
+
+	addr, err := netip.ParseAddr(s)
+	if err != nil {
+		return netip.Addr{}, false
+	}
+
+	return addr.Unmap().WithZone(""), true
+}
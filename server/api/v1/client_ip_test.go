@@ -0,0 +1,116 @@
+package v1
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/thetnaingtn/dirty-hand/internal/config"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+func TestClientIP(t *testing.T) {
+	proxies, err := newTrustedProxies(config.ServerConfig{TrustedProxies: []string{"10.0.0.0/8", "192.0.2.7"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name         string
+		peer         string
+		forwardedFor []string
+		want         string
+	}{
+		{"direct client", "203.0.113.9:5000", nil, "203.0.113.9"},
+		{"untrusted peer sending x-forwarded-for", "203.0.113.9:5000", []string{"198.51.100.1"}, "203.0.113.9"},
+		{"gateway", "127.0.0.1:40000", []string{"203.0.113.9"}, "203.0.113.9"},
+		{"gateway with a forged hop", "127.0.0.1:40000", []string{"198.51.100.1, 203.0.113.9"}, "203.0.113.9"},
+		{"forged metadata before the gateway's", "127.0.0.1:40000", []string{"198.51.100.1", "203.0.113.9"}, "203.0.113.9"},
+		{"trusted proxies", "127.0.0.1:40000", []string{"198.51.100.1, 203.0.113.9, 10.1.2.3, 192.0.2.7"}, "203.0.113.9"},
+		{"trusted proxy as peer", "10.1.2.3:5000", []string{"203.0.113.9"}, "203.0.113.9"},
+		{"only trusted hops", "127.0.0.1:40000", []string{"10.1.2.3"}, "10.1.2.3"},
+		{"invalid hop", "127.0.0.1:40000", []string{"203.0.113.9, garbage, 10.1.2.3"}, "10.1.2.3"},
+		{"gateway without x-forwarded-for", "127.0.0.1:40000", nil, "127.0.0.1"},
+		{"IPv6", "[2001:db8::1]:5000", nil, "2001:db8::1"},
+		{"IPv4-mapped IPv6", "[::ffff:10.1.2.3]:5000", []string{"203.0.113.9"}, "203.0.113.9"},
+	}
+
+	for _, test := range tests {
+		addr, err := net.ResolveTCPAddr("tcp", test.peer)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: addr})
+		md := metadata.MD{}
+		for _, value := range test.forwardedFor {
+			md.Append("x-forwarded-for", value)
+		}
+		ctx = metadata.NewIncomingContext(ctx, md)
+
+		if got := proxies.clientIP(ctx); got != test.want {
+			t.Errorf("clientIP of %s = %q, want %q", test.name, got, test.want)
+		}
+	}
+
+	if got := proxies.clientIP(context.Background()); got != "" {
+		t.Errorf("clientIP without a peer = %q, want none", got)
+	}
+}
+
+func TestNewTrustedProxies(t *testing.T) {
+	if _, err := newTrustedProxies(config.ServerConfig{TrustedProxies: []string{"proxy.example.com"}}); err == nil {
+		t.Error("newTrustedProxies with a host name succeeded")
+	}
+
+	// The gateway connects from a configured address.
+	proxies, err := newTrustedProxies(config.ServerConfig{Addr: "192.0.2.1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr, _ := parseIP("192.0.2.1")
+	if !proxies.contains(addr) {
+		t.Error("the server address is not trusted")
+	}
+}
+
+func TestIsSecureRequest(t *testing.T) {
+	proxies, err := newTrustedProxies(config.ServerConfig{TrustedProxies: []string{"10.0.0.0/8"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name           string
+		remoteAddr     string
+		tls            bool
+		forwardedProto string
+		want           bool
+	}{
+		{"plain HTTP", "203.0.113.9:5000", false, "", false},
+		{"TLS", "203.0.113.9:5000", true, "", true},
+		{"forged x-forwarded-proto", "203.0.113.9:5000", false, "https", false},
+		{"trusted proxy over HTTPS", "10.1.2.3:5000", false, "https", true},
+		{"trusted proxy over HTTP", "10.1.2.3:5000", false, "http", false},
+		{"loopback proxy over HTTPS", "127.0.0.1:5000", false, "HTTPS", true},
+	}
+
+	for _, test := range tests {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.RemoteAddr = test.remoteAddr
+		if test.tls {
+			r.TLS = &tls.ConnectionState{}
+		}
+		if test.forwardedProto != "" {
+			r.Header.Set("X-Forwarded-Proto", test.forwardedProto)
+		}
+
+		if got := proxies.isSecureRequest(r); got != test.want {
+			t.Errorf("isSecureRequest of %s = %t, want %t", test.name, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package v1
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/thetnaingtn/dirty-hand/internal/config"
+	apiv1 "github.com/thetnaingtn/dirty-hand/proto/gen/api/v1"
+	"github.com/thetnaingtn/dirty-hand/store"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+var errInvalidInvitation = status.Error(codes.PermissionDenied, "invalid or expired invitation code")
+
+func (s *APIV1Service) CreateInvitation(ctx context.Context, req *apiv1.CreateInvitationRequest) (*apiv1.Invitation, error) {
+	userId, ok := ctx.Value(userIdContextKey).(int64)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "authentication required")
+	}
+
+	role, ok := convertUserRoleToStore(req.GetRole())
+	if !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid role %s", req.GetRole())
+	}
+
+	now := time.Now()
+	expiresAt := now.Add(s.config.Registration.InvitationTTL)
+	if req.GetExpiresAt() != nil {
+		expiresAt = req.GetExpiresAt().AsTime()
+		if !expiresAt.After(now) {
+			return nil, status.Error(codes.InvalidArgument, "expires_at must be in the future")
+		}
+	}
+
+	code, err := generateToken()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to generate invitation code: %v", err)
+	}
+
+	invitation, err := s.store.CreateInvitation(ctx, &store.Invitation{
+		CodeHash:    hashToken(code),
+		Role:        role,
+		CreatedBy:   userId,
+		CreatedTime: now,
+		ExpiresTime: expiresAt,
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to create invitation: %v", err)
+	}
+
+	// The code is only shown once, only its hash is stored.
+	resp := convertInvitationFromStore(invitation)
+	resp.Code = code
+
+	return resp, nil
+}
+
+func (s *APIV1Service) ListInvitations(ctx context.Context, req *apiv1.ListInvitationsRequest) (*apiv1.ListInvitationsResponse, error) {
+	invitations, err := s.store.ListInvitations(ctx, &store.FindInvitation{})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list invitations: %v", err)
+	}
+
+	resp := &apiv1.ListInvitationsResponse{}
+	for _, invitation := range invitations {
+		resp.Invitations = append(resp.Invitations, convertInvitationFromStore(invitation))
+	}
+
+	return resp, nil
+}
+
+func (s *APIV1Service) DeleteInvitation(ctx context.Context, req *apiv1.DeleteInvitationRequest) (*emptypb.Empty, error) {
+	invitation, err := s.store.GetInvitation(ctx, &store.FindInvitation{ID: &req.Id})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get invitation: %v", err)
+	}
+
+	if invitation == nil {
+		return nil, status.Error(codes.NotFound, "invitation not found")
+	}
+
+	if err := s.store.DeleteInvitation(ctx, invitation.ID); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to delete invitation: %v", err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+// findSignUpInvitation returns the invitation a new user signs up with, or
+// nil when registration is open and no code was given.
+func (s *APIV1Service) findSignUpInvitation(ctx context.Context, code string) (*store.Invitation, error) {
+	code = strings.TrimSpace(code)
+	if code == "" {
+		if s.config.Registration.Mode == config.RegistrationOpen {
+			return nil, nil
+		}
+		return nil, status.Error(codes.PermissionDenied, "an invitation code is required to sign up")
+	}
+
+	codeHash := hashToken(code)
+	invitation, err := s.store.GetInvitation(ctx, &store.FindInvitation{CodeHash: &codeHash})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get invitation: %v", err)
+	}
+
+	if invitation == nil || invitation.UsedTime != nil || !invitation.ExpiresTime.After(time.Now()) {
+		return nil, errInvalidInvitation
+	}
+
+	return invitation, nil
+}
+
+func convertInvitationFromStore(invitation *store.Invitation) *apiv1.Invitation {
+	resp := &apiv1.Invitation{
+		Id:        invitation.ID,
+		Role:      convertUserRoleFromStore(invitation.Role),
+		CreatedAt: timestamppb.New(invitation.CreatedTime),
+		ExpiresAt: timestamppb.New(invitation.ExpiresTime),
+	}
+	if invitation.UsedTime != nil {
+		resp.UsedAt = timestamppb.New(*invitation.UsedTime)
+	}
+	if invitation.UsedBy != nil {
+		resp.UsedByUserId = *invitation.UsedBy
+	}
+
+	return resp
+}
@@ -0,0 +1,155 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/thetnaingtn/dirty-hand/internal/config"
+	apiv1 "github.com/thetnaingtn/dirty-hand/proto/gen/api/v1"
+	"github.com/thetnaingtn/dirty-hand/store"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func newInviteOnlyTestService(t *testing.T) (*APIV1Service, context.Context) {
+	t.Helper()
+
+	s := newTestService(t, func(cfg *config.Config) {
+		cfg.Registration.Mode = config.RegistrationInviteOnly
+	})
+
+	// The first user becomes the admin without an invitation.
+	admin, err := s.CreateUser(context.Background(), &apiv1.CreateUserRequest{Username: "admin", Password: testPassword})
+	if err != nil {
+		t.Fatalf("CreateUser of the first user = %v", err)
+	}
+	if admin.Role != apiv1.Role_ADMIN {
+		t.Fatalf("the first user has role %s, want ADMIN", admin.Role)
+	}
+
+	return s, withUser(context.Background(), admin.Id)
+}
+
+func TestCreateUserWithInvitation(t *testing.T) {
+	s, adminCtx := newInviteOnlyTestService(t)
+	ctx := context.Background()
+
+	invitation, err := s.CreateInvitation(adminCtx, &apiv1.CreateInvitationRequest{Role: apiv1.Role_PRODUCT_EDITOR})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		code string
+		want codes.Code
+	}{
+		{"no code", "", codes.PermissionDenied},
+		{"unknown code", "unknown", codes.PermissionDenied},
+		{"code", " " + invitation.Code + " ", codes.OK},
+		{"used code", invitation.Code, codes.PermissionDenied},
+	}
+
+	for i, test := range tests {
+		username := fmt.Sprintf("user%d", i)
+		user, err := s.CreateUser(ctx, &apiv1.CreateUserRequest{Username: username, Password: testPassword, InvitationCode: test.code})
+		if status.Code(err) != test.want {
+			t.Fatalf("CreateUser with %s = %v, want %s", test.name, err, test.want)
+		}
+		if err != nil {
+			// Nobody signs up with a refused invitation.
+			if found, err := s.store.GetUser(ctx, &store.FindUser{Username: &username}); err != nil || found != nil {
+				t.Errorf("GetUser after CreateUser with %s = %+v, %v; want nil, nil", test.name, found, err)
+			}
+			continue
+		}
+
+		if user.Role != apiv1.Role_PRODUCT_EDITOR {
+			t.Errorf("CreateUser with %s has role %s, want the invitation's PRODUCT_EDITOR", test.name, user.Role)
+		}
+		defaultOrganization, err := s.store.GetDefaultOrganization(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		membership, err := s.store.GetMembership(ctx, &store.FindMembership{OrganizationID: &defaultOrganization.ID, UserID: &user.Id})
+		if err != nil || membership == nil || membership.Role != store.RoleProductEdit {
+			t.Errorf("membership of the user of CreateUser with %s = %+v, %v; want the invitation's role", test.name, membership, err)
+		}
+
+		invitations, err := s.ListInvitations(adminCtx, &apiv1.ListInvitationsRequest{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(invitations.Invitations) != 1 || invitations.Invitations[0].UsedByUserId != user.Id || invitations.Invitations[0].UsedAt == nil {
+			t.Errorf("ListInvitations after CreateUser = %v, want the invitation used by %d", invitations.Invitations, user.Id)
+		}
+	}
+}
+
+func TestCreateUserWithExpiredInvitation(t *testing.T) {
+	s, adminCtx := newInviteOnlyTestService(t)
+	adminId, _ := adminCtx.Value(userIdContextKey).(int64)
+
+	// CreateInvitation refuses to make invitations that are already expired.
+	now := time.Now()
+	if _, err := s.store.CreateInvitation(context.Background(), &store.Invitation{
+		CodeHash:    hashToken("expired-code"),
+		Role:        store.RoleProductEdit,
+		CreatedBy:   adminId,
+		CreatedTime: now.Add(-2 * time.Hour),
+		ExpiresTime: now.Add(-time.Hour),
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := s.CreateUser(context.Background(), &apiv1.CreateUserRequest{Username: "alice", Password: testPassword, InvitationCode: "expired-code"})
+	if status.Code(err) != codes.PermissionDenied {
+		t.Errorf("CreateUser with an expired invitation = %v, want PermissionDenied", err)
+	}
+
+	_, err = s.CreateInvitation(adminCtx, &apiv1.CreateInvitationRequest{Role: apiv1.Role_PRODUCT_VIEWER, ExpiresAt: timestamppb.New(now.Add(-time.Minute))})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("CreateInvitation in the past = %v, want InvalidArgument", err)
+	}
+}
+
+func TestCreateUserWithInvitationConcurrently(t *testing.T) {
+	s, adminCtx := newInviteOnlyTestService(t)
+
+	invitation, err := s.CreateInvitation(adminCtx, &apiv1.CreateInvitationRequest{Role: apiv1.Role_PRODUCT_EDITOR})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const attempts = 10
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	created := 0
+	for i := 0; i < attempts; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			username := fmt.Sprintf("user%d", i)
+			_, err := s.CreateUser(context.Background(), &apiv1.CreateUserRequest{Username: username, Password: testPassword, InvitationCode: invitation.Code})
+			switch status.Code(err) {
+			case codes.OK:
+				mu.Lock()
+				created++
+				mu.Unlock()
+			case codes.PermissionDenied:
+			default:
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if created != 1 {
+		t.Errorf("%d users signed up with one invitation, want 1", created)
+	}
+}
@@ -0,0 +1,669 @@
+package v1
+
+import (
+	"context"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"golang.org/x/oauth2"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"github.com/thetnaingtn/dirty-hand/internal/config"
+	apiv1 "github.com/thetnaingtn/dirty-hand/proto/gen/api/v1"
+	"github.com/thetnaingtn/dirty-hand/store"
+)
+
+const (
+	oidcLoginPath    = "/auth/oidc/login"
+	oidcCallbackPath = "/auth/oidc/callback"
+
+	oidcSecondFactorPath = "/auth/oidc/second-factor"
+
+	oidcStateCookie    = "oidc_state"
+	oidcStateCookieTTL = 10 * time.Minute
+
+	oidcChallengeCookie = "oidc_challenge"
+)
+
+// roleRanks orders roles from least to most privileged.
+var roleRanks = map[store.Role]int{
+	store.RoleProductView: 1,
+	store.RoleProductEdit: 2,
+	store.RoleAdmin:       3,
+}
+
+// oidcAuthenticator signs users in through an OpenID Connect identity
+// provider with the authorization code flow and PKCE. The state, nonce and
+// code verifier of a login in progress live in an encrypted cookie, so any
+// server instance can handle the callback.
+type oidcAuthenticator struct {
+	service *APIV1Service
+	config  config.OIDCConfig
+
+	defaultRole store.Role
+	groupRoles  map[string]store.Role
+	stateAEAD   cipher.AEAD
+
+	// The provider is discovered on first use so the server can start while
+	// the identity provider is unreachable.
+	mu       sync.Mutex
+	provider *oidc.Provider
+}
+
+// oidcLoginState is carried by the oidc_state cookie between the login
+// redirect and the callback.
+type oidcLoginState struct {
+	State        string `json:"state"`
+	Nonce        string `json:"nonce"`
+	CodeVerifier string `json:"code_verifier"`
+	Redirect     string `json:"redirect"`
+	RememberMe   bool   `json:"remember_me"`
+}
+
+// oidcChallengeState is carried by the oidc_challenge cookie between the
+// callback and the second factor form of users with two-factor
+// authentication.
+type oidcChallengeState struct {
+	Challenge string `json:"challenge"`
+	Redirect  string `json:"redirect"`
+}
+
+func newOIDCAuthenticator(service *APIV1Service, cfg *config.Config) (*oidcAuthenticator, error) {
+	oidcConfig := cfg.OIDC
+	if oidcConfig.Issuer == "" || oidcConfig.ClientID == "" || oidcConfig.RedirectURL == "" {
+		return nil, errors.New("oidc issuer, client_id and redirect_url are required")
+	}
+
+	defaultRole := store.Role(oidcConfig.DefaultRole)
+	if _, ok := roleRanks[defaultRole]; !ok {
+		return nil, fmt.Errorf("invalid oidc default_role %q", oidcConfig.DefaultRole)
+	}
+
+	groupRoles := make(map[string]store.Role, len(oidcConfig.GroupRoles))
+	for group, role := range oidcConfig.GroupRoles {
+		if _, ok := roleRanks[store.Role(role)]; !ok {
+			return nil, fmt.Errorf("invalid oidc role %q for group %q", role, group)
+		}
+		groupRoles[strings.ToLower(group)] = store.Role(role)
+	}
+
+	if len(cfg.Session.CookieKeys) == 0 {
+		return nil, errors.New("no session cookie key configured")
+	}
+
+	stateAEAD, err := newCookieAEAD(deriveCookieKey(cfg.Session.CookieKeys[0], "oidc state"))
+	if err != nil {
+		return nil, err
+	}
+
+	return &oidcAuthenticator{
+		service:     service,
+		config:      oidcConfig,
+		defaultRole: defaultRole,
+		groupRoles:  groupRoles,
+		stateAEAD:   stateAEAD,
+	}, nil
+}
+
+func (a *oidcAuthenticator) getProvider(ctx context.Context) (*oidc.Provider, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.provider != nil {
+		return a.provider, nil
+	}
+
+	provider, err := oidc.NewProvider(ctx, a.config.Issuer)
+	if err != nil {
+		return nil, err
+	}
+
+	a.provider = provider
+	return provider, nil
+}
+
+func (a *oidcAuthenticator) oauth2Config(provider *oidc.Provider) *oauth2.Config {
+	return &oauth2.Config{
+		ClientID:     a.config.ClientID,
+		ClientSecret: a.config.ClientSecret,
+		RedirectURL:  a.config.RedirectURL,
+		Endpoint:     provider.Endpoint(),
+		Scopes:       a.config.Scopes,
+	}
+}
+
+// handleLogin redirects the browser to the identity provider.
+func (a *oidcAuthenticator) handleLogin(w http.ResponseWriter, r *http.Request) {
+	provider, err := a.getProvider(r.Context())
+	if err != nil {
+		slog.Error("failed to discover oidc provider", "issuer", a.config.Issuer, "error", err)
+		http.Error(w, "identity provider unavailable", http.StatusBadGateway)
+		return
+	}
+
+	state, err := generateToken()
+	if err != nil {
+		http.Error(w, "failed to start sign-in", http.StatusInternalServerError)
+		return
+	}
+
+	nonce, err := generateToken()
+	if err != nil {
+		http.Error(w, "failed to start sign-in", http.StatusInternalServerError)
+		return
+	}
+
+	loginState := &oidcLoginState{
+		State:        state,
+		Nonce:        nonce,
+		CodeVerifier: oauth2.GenerateVerifier(),
+		Redirect:     sanitizeRedirect(r.URL.Query().Get("redirect")),
+		RememberMe:   r.URL.Query().Get("remember_me") == "true",
+	}
+
+	value, err := a.sealLoginState(loginState)
+	if err != nil {
+		http.Error(w, "failed to start sign-in", http.StatusInternalServerError)
+		return
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     oidcStateCookie,
+		Value:    value,
+		Path:     oidcCallbackPath,
+		MaxAge:   int(oidcStateCookieTTL.Seconds()),
+		HttpOnly: true,
+		Secure:   a.service.trustedProxies.isSecureRequest(r),
+		// The callback is a top-level navigation from the identity provider,
+		// which a SameSite=Strict cookie would not be sent with.
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	authURL := a.oauth2Config(provider).AuthCodeURL(state,
+		oidc.Nonce(nonce),
+		oauth2.S256ChallengeOption(loginState.CodeVerifier),
+	)
+	http.Redirect(w, r, authURL, http.StatusFound)
+}
+
+// handleCallback completes the sign-in when the identity provider redirects
+// back with an authorization code.
+func (a *oidcAuthenticator) handleCallback(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	cookie, err := r.Cookie(oidcStateCookie)
+	if err != nil {
+		http.Error(w, "sign-in expired, please try again", http.StatusBadRequest)
+		return
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     oidcStateCookie,
+		Path:     oidcCallbackPath,
+		MaxAge:   -1,
+		HttpOnly: true,
+		Secure:   a.service.trustedProxies.isSecureRequest(r),
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	loginState, err := a.openLoginState(cookie.Value)
+	if err != nil || subtle.ConstantTimeCompare([]byte(loginState.State), []byte(r.URL.Query().Get("state"))) != 1 {
+		http.Error(w, "invalid sign-in state", http.StatusBadRequest)
+		return
+	}
+
+	if errCode := r.URL.Query().Get("error"); errCode != "" {
+		slog.Warn("oidc sign-in failed", "error", errCode, "description", r.URL.Query().Get("error_description"))
+		http.Error(w, "sign-in was not completed", http.StatusUnauthorized)
+		return
+	}
+
+	provider, err := a.getProvider(ctx)
+	if err != nil {
+		slog.Error("failed to discover oidc provider", "issuer", a.config.Issuer, "error", err)
+		http.Error(w, "identity provider unavailable", http.StatusBadGateway)
+		return
+	}
+
+	token, err := a.oauth2Config(provider).Exchange(ctx, r.URL.Query().Get("code"), oauth2.VerifierOption(loginState.CodeVerifier))
+	if err != nil {
+		slog.Warn("failed to exchange oidc authorization code", "error", err)
+		http.Error(w, "failed to complete sign-in", http.StatusUnauthorized)
+		return
+	}
+
+	rawIDToken, ok := token.Extra("id_token").(string)
+	if !ok {
+		http.Error(w, "identity provider returned no id token", http.StatusUnauthorized)
+		return
+	}
+
+	idToken, err := provider.Verifier(&oidc.Config{ClientID: a.config.ClientID}).Verify(ctx, rawIDToken)
+	if err != nil {
+		slog.Warn("failed to verify oidc id token", "error", err)
+		http.Error(w, "invalid id token", http.StatusUnauthorized)
+		return
+	}
+
+	if subtle.ConstantTimeCompare([]byte(idToken.Nonce), []byte(loginState.Nonce)) != 1 {
+		http.Error(w, "invalid id token nonce", http.StatusUnauthorized)
+		return
+	}
+
+	var claims map[string]any
+	if err := idToken.Claims(&claims); err != nil {
+		http.Error(w, "invalid id token claims", http.StatusUnauthorized)
+		return
+	}
+
+	user, err := a.resolveUser(ctx, idToken.Issuer, idToken.Subject, claims)
+	if err != nil {
+		var httpErr *oidcError
+		if errors.As(err, &httpErr) {
+			http.Error(w, httpErr.message, httpErr.code)
+			return
+		}
+
+		slog.Error("failed to resolve oidc user", "subject", idToken.Subject, "error", err)
+		http.Error(w, "failed to sign in", http.StatusInternalServerError)
+		return
+	}
+
+	if user.Disabled {
+		http.Error(w, "user is disabled", http.StatusForbidden)
+		return
+	}
+
+	// The identity provider stands in for the password only, so users with
+	// two-factor authentication still have to enter a code.
+	totp, err := a.service.store.GetUserTOTP(ctx, user.ID)
+	if err != nil {
+		slog.Error("failed to get totp of oidc user", "user_id", user.ID, "error", err)
+		http.Error(w, "failed to sign in", http.StatusInternalServerError)
+		return
+	}
+
+	if totp != nil && totp.Enabled {
+		a.startSecondFactor(w, r, user.ID, loginState)
+		return
+	}
+
+	signInCtx, stream := a.newSignInContext(ctx, r)
+	if _, err := a.service.doSignIn(signInCtx, user.ID, loginState.RememberMe); err != nil {
+		slog.Error("failed to sign in oidc user", "user_id", user.ID, "error", err)
+		http.Error(w, "failed to sign in", http.StatusInternalServerError)
+		return
+	}
+
+	for _, cookie := range stream.header.Get("set-cookie") {
+		w.Header().Add("Set-Cookie", cookie)
+	}
+
+	http.Redirect(w, r, loginState.Redirect, http.StatusFound)
+}
+
+// startSecondFactor creates the second factor challenge of a sign-in and
+// sends the browser to the form that completes it. The challenge lives in a
+// cookie rather than the URL, so that it does not end up in logs.
+func (a *oidcAuthenticator) startSecondFactor(w http.ResponseWriter, r *http.Request, userId int64, loginState *oidcLoginState) {
+	resp, err := a.service.createSecondFactorChallenge(r.Context(), userId, loginState.RememberMe)
+	if err != nil {
+		slog.Error("failed to create second factor challenge", "user_id", userId, "error", err)
+		http.Error(w, "failed to sign in", http.StatusInternalServerError)
+		return
+	}
+
+	value, err := a.sealCookie(oidcChallengeCookie, &oidcChallengeState{Challenge: resp.Challenge, Redirect: loginState.Redirect})
+	if err != nil {
+		http.Error(w, "failed to sign in", http.StatusInternalServerError)
+		return
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     oidcChallengeCookie,
+		Value:    value,
+		Path:     oidcSecondFactorPath,
+		MaxAge:   int(secondFactorChallengeTTL.Seconds()),
+		HttpOnly: true,
+		Secure:   a.service.trustedProxies.isSecureRequest(r),
+		// The cookie is only needed when the form is posted from this site,
+		// so other sites cannot post codes for it.
+		SameSite: http.SameSiteStrictMode,
+	})
+
+	http.Redirect(w, r, oidcSecondFactorPath, http.StatusFound)
+}
+
+// handleSecondFactor shows the second factor form and completes the sign-in
+// once a valid code is posted.
+func (a *oidcAuthenticator) handleSecondFactor(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		renderSecondFactorForm(w, http.StatusOK, "")
+		return
+	}
+
+	var challengeState oidcChallengeState
+	cookie, err := r.Cookie(oidcChallengeCookie)
+	if err != nil || a.openCookie(oidcChallengeCookie, cookie.Value, &challengeState) != nil {
+		http.Error(w, "sign-in expired, please try again", http.StatusBadRequest)
+		return
+	}
+
+	signInCtx, stream := a.newSignInContext(r.Context(), r)
+	_, err = a.service.VerifySecondFactor(signInCtx, &apiv1.VerifySecondFactorRequest{
+		Challenge: challengeState.Challenge,
+		Code:      r.PostFormValue("code"),
+	})
+	if err != nil {
+		if status.Code(err) == codes.Internal {
+			slog.Error("failed to verify second factor of oidc user", "error", err)
+			http.Error(w, "failed to sign in", http.StatusInternalServerError)
+			return
+		}
+
+		renderSecondFactorForm(w, runtime.HTTPStatusFromCode(status.Code(err)), status.Convert(err).Message())
+		return
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     oidcChallengeCookie,
+		Path:     oidcSecondFactorPath,
+		MaxAge:   -1,
+		HttpOnly: true,
+		Secure:   a.service.trustedProxies.isSecureRequest(r),
+		SameSite: http.SameSiteStrictMode,
+	})
+
+	for _, cookie := range stream.header.Get("set-cookie") {
+		w.Header().Add("Set-Cookie", cookie)
+	}
+
+	http.Redirect(w, r, challengeState.Redirect, http.StatusSeeOther)
+}
+
+var secondFactorForm = template.Must(template.New("second-factor").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Two-factor authentication</title>
+</head>
+<body>
+<form method="post">
+<p>Enter the code from your authenticator app, or one of your recovery codes.</p>
+{{if .}}<p role="alert">{{.}}</p>{{end}}
+<input name="code" autocomplete="one-time-code" autofocus required>
+<button type="submit">Sign in</button>
+</form>
+</body>
+</html>
+`))
+
+func renderSecondFactorForm(w http.ResponseWriter, code int, message string) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(code)
+	secondFactorForm.Execute(w, message)
+}
+
+// oidcError is a sign-in failure that is reported to the browser as is.
+type oidcError struct {
+	code    int
+	message string
+}
+
+func (e *oidcError) Error() string {
+	return e.message
+}
+
+// resolveUser returns the user linked to the identity, provisioning a new
+// user on first sign-in. The role of a linked user follows its groups
+// whenever one of them is mapped to a role.
+func (a *oidcAuthenticator) resolveUser(ctx context.Context, issuer, subject string, claims map[string]any) (*store.User, error) {
+	s := a.service.store
+	groupRole, hasGroupRole := a.roleFromGroups(claims)
+
+	identity, err := s.GetUserIdentity(ctx, &store.FindUserIdentity{Issuer: &issuer, Subject: &subject})
+	if err != nil {
+		return nil, err
+	}
+
+	if identity != nil {
+		user, err := s.GetUser(ctx, &store.FindUser{ID: &identity.UserID})
+		if err != nil {
+			return nil, err
+		}
+
+		if user == nil {
+			return nil, &oidcError{code: http.StatusUnauthorized, message: "user not found"}
+		}
+
+		if hasGroupRole && user.Role != groupRole {
+			updated, err := s.UpdateUser(ctx, &store.UpdateUser{ID: user.ID, Role: &groupRole})
+			switch {
+			case errors.Is(err, store.ErrLastAdmin):
+				slog.Warn("keeping role of the last admin despite its oidc groups", "user_id", user.ID)
+			case err != nil:
+				return nil, err
+			default:
+				user = updated
+			}
+		}
+
+		return user, nil
+	}
+
+	username := claimString(claims, a.config.UsernameClaim)
+	if username == "" {
+		username = claimString(claims, "email")
+	}
+	if username == "" {
+		username = subject
+	}
+
+	if a.service.config.Registration.Mode == config.RegistrationClosed {
+		return nil, &oidcError{code: http.StatusForbidden, message: "registration is closed"}
+	}
+
+	existing, err := s.GetUser(ctx, &store.FindUser{Username: &username})
+	if err != nil {
+		return nil, err
+	}
+
+	if existing != nil {
+		return nil, &oidcError{code: http.StatusConflict, message: fmt.Sprintf("username %q is already taken", username)}
+	}
+
+	role := a.defaultRole
+	if hasGroupRole {
+		role = groupRole
+	}
+
+	// Provisioned users have no password and can only sign in through the
+	// identity provider until an admin issues a password reset token. The
+	// user is rolled back when its identity was linked in the meantime.
+	var user *store.User
+	err = s.WithTx(ctx, func(ctx context.Context) error {
+		var err error
+		if user, err = s.CreateUser(ctx, &store.User{
+			Username: username,
+			Role:     role,
+		}); err != nil {
+			return err
+		}
+
+		if _, err := s.CreateUserIdentity(ctx, &store.UserIdentity{
+			UserID:      user.ID,
+			Issuer:      issuer,
+			Subject:     subject,
+			CreatedTime: time.Now(),
+		}); err != nil {
+			return err
+		}
+
+		return a.service.joinDefaultOrganization(ctx, user)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	slog.Info("provisioned oidc user", "user_id", user.ID, "username", username, "role", role)
+
+	return user, nil
+}
+
+// roleFromGroups returns the most privileged role mapped to one of the
+// groups in the claims.
+func (a *oidcAuthenticator) roleFromGroups(claims map[string]any) (store.Role, bool) {
+	var groups []string
+	switch v := claims[a.config.GroupsClaim].(type) {
+	case string:
+		groups = []string{v}
+	case []any:
+		for _, group := range v {
+			if group, ok := group.(string); ok {
+				groups = append(groups, group)
+			}
+		}
+	}
+
+	var role store.Role
+	for _, group := range groups {
+		if mapped, ok := a.groupRoles[strings.ToLower(group)]; ok && roleRanks[mapped] > roleRanks[role] {
+			role = mapped
+		}
+	}
+
+	return role, role != ""
+}
+
+func claimString(claims map[string]any, name string) string {
+	v, _ := claims[name].(string)
+	return strings.TrimSpace(v)
+}
+
+func (a *oidcAuthenticator) sealLoginState(loginState *oidcLoginState) (string, error) {
+	return a.sealCookie(oidcStateCookie, loginState)
+}
+
+func (a *oidcAuthenticator) openLoginState(value string) (*oidcLoginState, error) {
+	var loginState oidcLoginState
+	if err := a.openCookie(oidcStateCookie, value, &loginState); err != nil {
+		return nil, err
+	}
+
+	return &loginState, nil
+}
+
+// sealCookie encrypts v for the cookie name. The name is authenticated too,
+// so that one cookie cannot be passed off as another.
+func (a *oidcAuthenticator) sealCookie(name string, v any) (string, error) {
+	payload, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, a.stateAEAD.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	sealed := a.stateAEAD.Seal(nonce, nonce, payload, []byte(name))
+	return base64.RawURLEncoding.EncodeToString(sealed), nil
+}
+
+func (a *oidcAuthenticator) openCookie(name, value string, v any) error {
+	sealed, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return err
+	}
+
+	nonceSize := a.stateAEAD.NonceSize()
+	if len(sealed) < nonceSize {
+		return errors.New("invalid oidc cookie")
+	}
+
+	payload, err := a.stateAEAD.Open(nil, sealed[:nonceSize], sealed[nonceSize:], []byte(name))
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(payload, v)
+}
+
+// sanitizeRedirect only allows redirects to a path on this site.
+func sanitizeRedirect(redirect string) string {
+	if !strings.HasPrefix(redirect, "/") || strings.HasPrefix(redirect, "//") || strings.HasPrefix(redirect, "/\\") {
+		return "/"
+	}
+
+	return redirect
+}
+
+// headerCaptureStream records the header metadata that gRPC handlers send, so
+// they can be reused from a plain HTTP handler.
+type headerCaptureStream struct {
+	header metadata.MD
+}
+
+func (s *headerCaptureStream) Method() string {
+	return oidcCallbackPath
+}
+
+func (s *headerCaptureStream) SetHeader(md metadata.MD) error {
+	s.header = metadata.Join(s.header, md)
+	return nil
+}
+
+func (s *headerCaptureStream) SendHeader(md metadata.MD) error {
+	return s.SetHeader(md)
+}
+
+func (s *headerCaptureStream) SetTrailer(metadata.MD) error {
+	return nil
+}
+
+// newSignInContext prepares a context for doSignIn from an HTTP request.
+// It carries the request's peer and client details as the gateway would
+// forward them, so the client IP is found the same way as for API calls, and
+// captures the session cookie doSignIn sets.
+func (a *oidcAuthenticator) newSignInContext(ctx context.Context, r *http.Request) (context.Context, *headerCaptureStream) {
+	if addrPort, err := netip.ParseAddrPort(r.RemoteAddr); err == nil {
+		ctx = peer.NewContext(ctx, &peer.Peer{Addr: net.TCPAddrFromAddrPort(addrPort)})
+	}
+
+	scheme := "http"
+	if a.service.trustedProxies.isSecureRequest(r) {
+		scheme = "https"
+	}
+
+	md := metadata.New(map[string]string{
+		"grpcgateway-user-agent": r.UserAgent(),
+		"grpcgateway-origin":     scheme + "://" + r.Host,
+	})
+	for _, value := range r.Header.Values("X-Forwarded-For") {
+		md.Append("x-forwarded-for", value)
+	}
+	ctx = metadata.NewIncomingContext(ctx, md)
+
+	stream := &headerCaptureStream{}
+	return grpc.NewContextWithServerTransportStream(ctx, stream), stream
+}
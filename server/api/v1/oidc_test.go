@@ -0,0 +1,509 @@
+package v1
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	jose "github.com/go-jose/go-jose/v4"
+	"github.com/go-jose/go-jose/v4/jwt"
+
+	"github.com/thetnaingtn/dirty-hand/internal/config"
+	"github.com/thetnaingtn/dirty-hand/store"
+)
+
+const (
+	testOIDCClientID    = "atlas"
+	testOIDCRedirectURL = "http://atlas.test" + oidcCallbackPath
+)
+
+// mockIssuer is an OpenID Connect identity provider that authorizes every
+// request right away, with the claims of the user passed to authorize.
+type mockIssuer struct {
+	server *httptest.Server
+	key    *rsa.PrivateKey
+	signer jose.Signer
+
+	mu             sync.Mutex
+	authorizations map[string]mockAuthorization
+}
+
+// mockAuthorization is an authorization code handed out by the issuer.
+type mockAuthorization struct {
+	nonce         string
+	codeChallenge string
+	claims        map[string]any
+}
+
+func newMockIssuer(t *testing.T) *mockIssuer {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	signer, err := jose.NewSigner(jose.SigningKey{Algorithm: jose.RS256, Key: jose.JSONWebKey{Key: key, KeyID: "test"}}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	issuer := &mockIssuer{key: key, signer: signer, authorizations: map[string]mockAuthorization{}}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", issuer.handleDiscovery)
+	mux.HandleFunc("/jwks", issuer.handleJWKS)
+	mux.HandleFunc("/token", issuer.handleToken)
+	issuer.server = httptest.NewServer(mux)
+	t.Cleanup(issuer.server.Close)
+
+	return issuer
+}
+
+func (m *mockIssuer) handleDiscovery(w http.ResponseWriter, r *http.Request) {
+	json.NewEncoder(w).Encode(map[string]any{
+		"issuer":                                m.server.URL,
+		"authorization_endpoint":                m.server.URL + "/authorize",
+		"token_endpoint":                        m.server.URL + "/token",
+		"jwks_uri":                              m.server.URL + "/jwks",
+		"id_token_signing_alg_values_supported": []string{"RS256"},
+	})
+}
+
+func (m *mockIssuer) handleJWKS(w http.ResponseWriter, r *http.Request) {
+	json.NewEncoder(w).Encode(jose.JSONWebKeySet{Keys: []jose.JSONWebKey{
+		{Key: &m.key.PublicKey, KeyID: "test", Algorithm: "RS256", Use: "sig"},
+	}})
+}
+
+// authorize plays the browser at the authorization endpoint: it checks the
+// request the login redirect sent the browser with and returns the callback
+// URL the issuer sends the browser back to.
+func (m *mockIssuer) authorize(t *testing.T, authURL string, claims map[string]any) string {
+	t.Helper()
+
+	u, err := url.Parse(authURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	query := u.Query()
+	if u.Scheme+"://"+u.Host+u.Path != m.server.URL+"/authorize" {
+		t.Fatalf("login redirected to %s, want the authorization endpoint", authURL)
+	}
+	for name, want := range map[string]string{
+		"client_id":             testOIDCClientID,
+		"redirect_uri":          testOIDCRedirectURL,
+		"response_type":         "code",
+		"code_challenge_method": "S256",
+	} {
+		if got := query.Get(name); got != want {
+			t.Fatalf("authorization request %s = %q, want %q", name, got, want)
+		}
+	}
+	if query.Get("state") == "" || query.Get("nonce") == "" || query.Get("code_challenge") == "" {
+		t.Fatalf("authorization request %s has no state, nonce or code challenge", authURL)
+	}
+
+	code, err := generateToken()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m.mu.Lock()
+	m.authorizations[code] = mockAuthorization{nonce: query.Get("nonce"), codeChallenge: query.Get("code_challenge"), claims: claims}
+	m.mu.Unlock()
+
+	callback := url.Values{"code": {code}, "state": {query.Get("state")}}
+	return query.Get("redirect_uri") + "?" + callback.Encode()
+}
+
+// handleToken exchanges an authorization code for an ID token, provided the
+// PKCE code verifier matches the challenge of the authorization.
+func (m *mockIssuer) handleToken(w http.ResponseWriter, r *http.Request) {
+	r.ParseForm()
+
+	m.mu.Lock()
+	authorization, ok := m.authorizations[r.Form.Get("code")]
+	delete(m.authorizations, r.Form.Get("code"))
+	m.mu.Unlock()
+
+	sum := sha256.Sum256([]byte(r.Form.Get("code_verifier")))
+	if !ok || base64.RawURLEncoding.EncodeToString(sum[:]) != authorization.codeChallenge {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"error":"invalid_grant"}`))
+		return
+	}
+
+	claims := map[string]any{
+		"iss":   m.server.URL,
+		"aud":   testOIDCClientID,
+		"iat":   time.Now().Unix(),
+		"exp":   time.Now().Add(time.Hour).Unix(),
+		"nonce": authorization.nonce,
+	}
+	for name, value := range authorization.claims {
+		claims[name] = value
+	}
+
+	idToken, err := jwt.Signed(m.signer).Claims(claims).Serialize()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]any{
+		"access_token": "access-token",
+		"token_type":   "Bearer",
+		"expires_in":   3600,
+		"id_token":     idToken,
+	})
+}
+
+// newOIDCTestService returns a service that signs users in through issuer.
+func newOIDCTestService(t *testing.T, issuer *mockIssuer, configure func(cfg *config.Config)) *APIV1Service {
+	t.Helper()
+
+	return newTestService(t, func(cfg *config.Config) {
+		cfg.OIDC = config.OIDCConfig{
+			Enabled:       true,
+			Issuer:        issuer.server.URL,
+			ClientID:      testOIDCClientID,
+			ClientSecret:  "secret",
+			RedirectURL:   testOIDCRedirectURL,
+			Scopes:        []string{"openid", "profile"},
+			UsernameClaim: "preferred_username",
+			GroupsClaim:   "groups",
+			DefaultRole:   string(store.RoleProductView),
+			GroupRoles:    map[string]string{"Admins": string(store.RoleAdmin), "editors": string(store.RoleProductEdit)},
+		}
+		if configure != nil {
+			configure(cfg)
+		}
+	})
+}
+
+// startOIDCLogin runs the login redirect and returns the URL of the
+// authorization endpoint and the state cookie.
+func startOIDCLogin(t *testing.T, s *APIV1Service) (string, *http.Cookie) {
+	t.Helper()
+
+	login := httptest.NewRecorder()
+	s.oidc.handleLogin(login, httptest.NewRequest(http.MethodGet, oidcLoginPath+"?redirect=/products", nil))
+	if login.Code != http.StatusFound {
+		t.Fatalf("login = %d %s, want a redirect", login.Code, login.Body)
+	}
+
+	cookie := findCookie(login.Result(), oidcStateCookie)
+	if cookie == nil {
+		t.Fatal("login set no state cookie")
+	}
+
+	return login.Header().Get("Location"), cookie
+}
+
+// finishOIDCLogin calls back with the callback URL of the issuer.
+func finishOIDCLogin(s *APIV1Service, callbackURL string, stateCookie *http.Cookie) *http.Response {
+	r := httptest.NewRequest(http.MethodGet, callbackURL, nil)
+	if stateCookie != nil {
+		r.AddCookie(stateCookie)
+	}
+
+	callback := httptest.NewRecorder()
+	s.oidc.handleCallback(callback, r)
+
+	return callback.Result()
+}
+
+// oidcSignIn signs in through issuer as the user of claims.
+func oidcSignIn(t *testing.T, s *APIV1Service, issuer *mockIssuer, claims map[string]any) *http.Response {
+	t.Helper()
+
+	authURL, stateCookie := startOIDCLogin(t, s)
+	return finishOIDCLogin(s, issuer.authorize(t, authURL, claims), stateCookie)
+}
+
+func findCookie(resp *http.Response, name string) *http.Cookie {
+	for _, cookie := range resp.Cookies() {
+		if cookie.Name == name && cookie.MaxAge >= 0 {
+			return cookie
+		}
+	}
+
+	return nil
+}
+
+// assertSignedIn checks that the callback signed the user in and sent the
+// browser on to where the login started.
+func assertSignedIn(t *testing.T, resp *http.Response) {
+	t.Helper()
+
+	if resp.StatusCode != http.StatusFound || resp.Header.Get("Location") != "/products" {
+		t.Fatalf("callback = %d to %q, want a redirect to /products", resp.StatusCode, resp.Header.Get("Location"))
+	}
+	if findCookie(resp, "user_session") == nil {
+		t.Fatal("callback set no session cookie")
+	}
+}
+
+func TestOIDCSignIn(t *testing.T) {
+	issuer := newMockIssuer(t)
+	s := newOIDCTestService(t, issuer, nil)
+	ctx := context.Background()
+
+	defaultOrganization, err := s.store.GetDefaultOrganization(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		claims   map[string]any
+		username string
+		role     store.Role
+	}{
+		{"without groups", map[string]any{"sub": "1", "preferred_username": "alice"}, "alice", store.RoleProductView},
+		{"with an unmapped group", map[string]any{"sub": "2", "preferred_username": "bob", "groups": []string{"sales"}}, "bob", store.RoleProductView},
+		{"with the most privileged group", map[string]any{"sub": "3", "preferred_username": "carol", "groups": []string{"editors", "ADMINS"}}, "carol", store.RoleAdmin},
+		{"with a single group", map[string]any{"sub": "4", "preferred_username": "dave", "groups": "editors"}, "dave", store.RoleProductEdit},
+		{"without a username", map[string]any{"sub": "5", "email": "erin@example.com"}, "erin@example.com", store.RoleProductView},
+	}
+
+	for _, test := range tests {
+		assertSignedIn(t, oidcSignIn(t, s, issuer, test.claims))
+
+		subject := test.claims["sub"].(string)
+		identity, err := s.store.GetUserIdentity(ctx, &store.FindUserIdentity{Issuer: &issuer.server.URL, Subject: &subject})
+		if err != nil || identity == nil {
+			t.Fatalf("GetUserIdentity of the user %s = %+v, %v; want the identity", test.name, identity, err)
+		}
+
+		user, err := s.store.GetUser(ctx, &store.FindUser{ID: &identity.UserID})
+		if err != nil || user == nil || user.Username != test.username || user.Role != test.role || user.PasswordHash != "" {
+			t.Errorf("user %s = %+v, %v; want %s with role %s and no password", test.name, user, err, test.username, test.role)
+		}
+
+		membership, err := s.store.GetMembership(ctx, &store.FindMembership{OrganizationID: &defaultOrganization.ID, UserID: &identity.UserID})
+		if err != nil || membership == nil || membership.Role != test.role {
+			t.Errorf("membership of the user %s in the default organization = %+v, %v; want role %s", test.name, membership, err, test.role)
+		}
+	}
+
+	// Later sign-ins find the user by its identity, and its role follows
+	// its groups.
+	assertSignedIn(t, oidcSignIn(t, s, issuer, map[string]any{"sub": "1", "preferred_username": "renamed", "groups": []string{"editors"}}))
+	username := "alice"
+	user, err := s.store.GetUser(ctx, &store.FindUser{Username: &username})
+	if err != nil || user == nil || user.Role != store.RoleProductEdit {
+		t.Fatalf("user after signing in with another group = %+v, %v; want alice with role %s", user, err, store.RoleProductEdit)
+	}
+}
+
+func TestOIDCSignInRejected(t *testing.T) {
+	issuer := newMockIssuer(t)
+	s := newOIDCTestService(t, issuer, nil)
+	createTestUser(t, s, "local", store.RoleProductView)
+
+	claims := map[string]any{"sub": "1", "preferred_username": "alice"}
+
+	tests := []struct {
+		name     string
+		callback func(t *testing.T) *http.Response
+		want     int
+	}{
+		{"without the state cookie", func(t *testing.T) *http.Response {
+			authURL, _ := startOIDCLogin(t, s)
+			return finishOIDCLogin(s, issuer.authorize(t, authURL, claims), nil)
+		}, http.StatusBadRequest},
+		{"with the state of another login", func(t *testing.T) *http.Response {
+			authURL, _ := startOIDCLogin(t, s)
+			_, otherCookie := startOIDCLogin(t, s)
+			return finishOIDCLogin(s, issuer.authorize(t, authURL, claims), otherCookie)
+		}, http.StatusBadRequest},
+		{"with a tampered state cookie", func(t *testing.T) *http.Response {
+			authURL, cookie := startOIDCLogin(t, s)
+			cookie.Value = cookie.Value[:len(cookie.Value)-2] + "AA"
+			return finishOIDCLogin(s, issuer.authorize(t, authURL, claims), cookie)
+		}, http.StatusBadRequest},
+		{"with the code of another login", func(t *testing.T) *http.Response {
+			// The state matches, but the code was issued for the code
+			// challenge of the other login, so the code verifier does not.
+			authURL, _ := startOIDCLogin(t, s)
+			otherAuthURL, otherCookie := startOIDCLogin(t, s)
+
+			callback, _ := url.Parse(issuer.authorize(t, authURL, claims))
+			otherCallback, _ := url.Parse(issuer.authorize(t, otherAuthURL, claims))
+			query := callback.Query()
+			query.Set("state", otherCallback.Query().Get("state"))
+			callback.RawQuery = query.Encode()
+
+			return finishOIDCLogin(s, callback.String(), otherCookie)
+		}, http.StatusUnauthorized},
+		{"with an error from the issuer", func(t *testing.T) *http.Response {
+			authURL, cookie := startOIDCLogin(t, s)
+			u, _ := url.Parse(authURL)
+			callback := url.Values{"error": {"access_denied"}, "state": {u.Query().Get("state")}}
+			return finishOIDCLogin(s, testOIDCRedirectURL+"?"+callback.Encode(), cookie)
+		}, http.StatusUnauthorized},
+		{"with the username of a local account", func(t *testing.T) *http.Response {
+			return oidcSignIn(t, s, issuer, map[string]any{"sub": "2", "preferred_username": "local"})
+		}, http.StatusConflict},
+	}
+
+	for _, test := range tests {
+		resp := test.callback(t)
+		if resp.StatusCode != test.want {
+			t.Errorf("callback %s = %d, want %d", test.name, resp.StatusCode, test.want)
+		}
+		if findCookie(resp, "user_session") != nil {
+			t.Errorf("callback %s set a session cookie", test.name)
+		}
+	}
+}
+
+func TestOIDCSignInClosedRegistration(t *testing.T) {
+	issuer := newMockIssuer(t)
+	s := newOIDCTestService(t, issuer, func(cfg *config.Config) {
+		cfg.Registration.Mode = config.RegistrationClosed
+	})
+
+	resp := oidcSignIn(t, s, issuer, map[string]any{"sub": "1", "preferred_username": "alice"})
+	if resp.StatusCode != http.StatusForbidden {
+		t.Fatalf("callback of a new user with closed registration = %d, want %d", resp.StatusCode, http.StatusForbidden)
+	}
+
+	users, err := s.store.ListUsers(context.Background(), &store.FindUser{})
+	if err != nil || len(users) != 0 {
+		t.Fatalf("ListUsers = %+v, %v; want none", users, err)
+	}
+}
+
+func TestOIDCSignInSecondFactor(t *testing.T) {
+	issuer := newMockIssuer(t)
+	s := newOIDCTestService(t, issuer, nil)
+	ctx := context.Background()
+	claims := map[string]any{"sub": "1", "preferred_username": "alice"}
+
+	assertSignedIn(t, oidcSignIn(t, s, issuer, claims))
+	username := "alice"
+	user, err := s.store.GetUser(ctx, &store.FindUser{Username: &username})
+	if err != nil || user == nil {
+		t.Fatalf("GetUser = %+v, %v; want alice", user, err)
+	}
+
+	key := enableTestTOTP(t, s, user.ID)
+
+	resp := oidcSignIn(t, s, issuer, claims)
+	if resp.StatusCode != http.StatusFound || resp.Header.Get("Location") != oidcSecondFactorPath {
+		t.Fatalf("callback of a user with two-factor authentication = %d to %q, want a redirect to %s", resp.StatusCode, resp.Header.Get("Location"), oidcSecondFactorPath)
+	}
+	if findCookie(resp, "user_session") != nil {
+		t.Fatal("callback of a user with two-factor authentication set a session cookie")
+	}
+	challengeCookie := findCookie(resp, oidcChallengeCookie)
+	if challengeCookie == nil {
+		t.Fatal("callback set no challenge cookie")
+	}
+
+	form := httptest.NewRecorder()
+	s.oidc.handleSecondFactor(form, httptest.NewRequest(http.MethodGet, oidcSecondFactorPath, nil))
+	if form.Code != http.StatusOK || !strings.Contains(form.Body.String(), `name="code"`) {
+		t.Fatalf("second factor form = %d %s, want the form", form.Code, form.Body)
+	}
+
+	postCode := func(code string, cookie *http.Cookie) *http.Response {
+		r := httptest.NewRequest(http.MethodPost, oidcSecondFactorPath, strings.NewReader(url.Values{"code": {code}}.Encode()))
+		r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		if cookie != nil {
+			r.AddCookie(cookie)
+		}
+
+		w := httptest.NewRecorder()
+		s.oidc.handleSecondFactor(w, r)
+		return w.Result()
+	}
+
+	current := currentTOTPStep()
+	code := generateTOTPCode(key, current)
+
+	if resp := postCode(code, nil); resp.StatusCode != http.StatusBadRequest || findCookie(resp, "user_session") != nil {
+		t.Fatalf("posting a code without the challenge cookie = %d, want %d", resp.StatusCode, http.StatusBadRequest)
+	}
+	if resp := postCode(generateTOTPCode(key, current+5), challengeCookie); resp.StatusCode != http.StatusUnauthorized || findCookie(resp, "user_session") != nil {
+		t.Fatalf("posting a wrong code = %d, want %d", resp.StatusCode, http.StatusUnauthorized)
+	}
+
+	resp = postCode(code, challengeCookie)
+	if resp.StatusCode != http.StatusSeeOther || resp.Header.Get("Location") != "/products" || findCookie(resp, "user_session") == nil {
+		t.Fatalf("posting the code = %d to %q, want a redirect to /products with a session cookie", resp.StatusCode, resp.Header.Get("Location"))
+	}
+
+	// The challenge is used up.
+	if resp := postCode(code, challengeCookie); resp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("posting the code again = %d, want %d", resp.StatusCode, http.StatusUnauthorized)
+	}
+}
+
+func TestOIDCSignInContextClientIP(t *testing.T) {
+	proxies, err := newTrustedProxies(config.ServerConfig{TrustedProxies: []string{"10.0.0.1"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := &oidcAuthenticator{service: &APIV1Service{trustedProxies: proxies}}
+
+	tests := []struct {
+		name         string
+		remoteAddr   string
+		forwardedFor string
+		want         string
+	}{
+		{"direct", "203.0.113.9:5000", "", "203.0.113.9"},
+		{"forged x-forwarded-for", "203.0.113.9:5000", "198.51.100.1", "203.0.113.9"},
+		{"trusted proxy", "10.0.0.1:5000", "198.51.100.1, 203.0.113.9", "203.0.113.9"},
+	}
+
+	for _, test := range tests {
+		r := httptest.NewRequest(http.MethodGet, oidcCallbackPath, nil)
+		r.RemoteAddr = test.remoteAddr
+		if test.forwardedFor != "" {
+			r.Header.Set("X-Forwarded-For", test.forwardedFor)
+		}
+
+		ctx, _ := a.newSignInContext(context.Background(), r)
+		if got := proxies.clientIP(ctx); got != test.want {
+			t.Errorf("client IP of a %s request = %q, want %q", test.name, got, test.want)
+		}
+	}
+}
+
+func TestSanitizeRedirect(t *testing.T) {
+	tests := []struct {
+		redirect string
+		want     string
+	}{
+		{"", "/"},
+		{"/products?page=2", "/products?page=2"},
+		{"https://evil.example.com", "/"},
+		{"//evil.example.com", "/"},
+		{"/\\evil.example.com", "/"},
+		{"products", "/"},
+	}
+
+	for _, test := range tests {
+		if got := sanitizeRedirect(test.redirect); got != test.want {
+			t.Errorf("sanitizeRedirect(%q) = %q, want %q", test.redirect, got, test.want)
+		}
+	}
+}
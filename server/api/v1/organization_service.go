@@ -0,0 +1,227 @@
+package v1
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	apiv1 "github.com/thetnaingtn/dirty-hand/proto/gen/api/v1"
+	"github.com/thetnaingtn/dirty-hand/store"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func (s *APIV1Service) CreateOrganization(ctx context.Context, req *apiv1.CreateOrganizationRequest) (*apiv1.Organization, error) {
+	name := strings.TrimSpace(req.GetName())
+	if name == "" {
+		return nil, status.Error(codes.InvalidArgument, "name is required")
+	}
+
+	existing, err := s.store.GetOrganization(ctx, &store.FindOrganization{Name: &name})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get organization: %v", err)
+	}
+
+	if existing != nil {
+		return nil, status.Errorf(codes.AlreadyExists, "organization %q already exists", name)
+	}
+
+	organization, err := s.store.CreateOrganization(ctx, &store.Organization{
+		Name:        name,
+		CreatedTime: time.Now(),
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to create organization: %v", err)
+	}
+
+	return convertOrganizationFromStore(organization, store.RoleAdmin), nil
+}
+
+func (s *APIV1Service) ListOrganizations(ctx context.Context, req *apiv1.ListOrganizationsRequest) (*apiv1.ListOrganizationsResponse, error) {
+	userId, ok := ctx.Value(userIdContextKey).(int64)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "authentication required")
+	}
+
+	user, err := s.getUserByID(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &apiv1.ListOrganizationsResponse{}
+
+	if user.Role == store.RoleAdmin {
+		organizations, err := s.store.ListOrganizations(ctx, &store.FindOrganization{})
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to list organizations: %v", err)
+		}
+
+		for _, organization := range organizations {
+			resp.Organizations = append(resp.Organizations, convertOrganizationFromStore(organization, store.RoleAdmin))
+		}
+
+		return resp, nil
+	}
+
+	memberships, err := s.store.ListMemberships(ctx, &store.FindMembership{UserID: &userId})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list memberships: %v", err)
+	}
+
+	roles := make(map[int64]store.Role, len(memberships))
+	for _, membership := range memberships {
+		roles[membership.OrganizationID] = membership.Role
+	}
+
+	organizations, err := s.store.ListOrganizations(ctx, &store.FindOrganization{MemberID: &userId})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list organizations: %v", err)
+	}
+
+	for _, organization := range organizations {
+		resp.Organizations = append(resp.Organizations, convertOrganizationFromStore(organization, roles[organization.ID]))
+	}
+
+	return resp, nil
+}
+
+func (s *APIV1Service) DeleteOrganization(ctx context.Context, req *apiv1.DeleteOrganizationRequest) (*emptypb.Empty, error) {
+	organization, err := s.getOrganizationByID(ctx, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	// Product calls without an organization and new users need it.
+	if organization.Name == store.DefaultOrganizationName {
+		return nil, status.Error(codes.FailedPrecondition, "the default organization cannot be deleted")
+	}
+
+	if err := s.store.DeleteOrganization(ctx, req.GetId()); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to delete organization: %v", err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (s *APIV1Service) ListMemberships(ctx context.Context, req *apiv1.ListMembershipsRequest) (*apiv1.ListMembershipsResponse, error) {
+	if _, err := s.getOrganizationByID(ctx, req.GetOrganizationId()); err != nil {
+		return nil, err
+	}
+
+	memberships, err := s.store.ListMemberships(ctx, &store.FindMembership{OrganizationID: &req.OrganizationId})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list memberships: %v", err)
+	}
+
+	resp := &apiv1.ListMembershipsResponse{}
+	for _, membership := range memberships {
+		user, err := s.getUserByID(ctx, membership.UserID)
+		if err != nil {
+			return nil, err
+		}
+
+		resp.Memberships = append(resp.Memberships, convertMembershipFromStore(membership, user))
+	}
+
+	return resp, nil
+}
+
+func (s *APIV1Service) SetMembership(ctx context.Context, req *apiv1.SetMembershipRequest) (*apiv1.Membership, error) {
+	role, ok := convertUserRoleToStore(req.GetRole())
+	if !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid role %s", req.GetRole())
+	}
+
+	if _, err := s.getOrganizationByID(ctx, req.GetOrganizationId()); err != nil {
+		return nil, err
+	}
+
+	user, err := s.getUserByID(ctx, req.GetUserId())
+	if err != nil {
+		return nil, err
+	}
+
+	membership, err := s.store.UpsertMembership(ctx, &store.Membership{
+		OrganizationID: req.GetOrganizationId(),
+		UserID:         user.ID,
+		Role:           role,
+		CreatedTime:    time.Now(),
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to set membership: %v", err)
+	}
+
+	return convertMembershipFromStore(membership, user), nil
+}
+
+func (s *APIV1Service) DeleteMembership(ctx context.Context, req *apiv1.DeleteMembershipRequest) (*emptypb.Empty, error) {
+	membership, err := s.store.GetMembership(ctx, &store.FindMembership{
+		OrganizationID: &req.OrganizationId,
+		UserID:         &req.UserId,
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get membership: %v", err)
+	}
+
+	if membership == nil {
+		return nil, status.Error(codes.NotFound, "membership not found")
+	}
+
+	if err := s.store.DeleteMembership(ctx, membership.OrganizationID, membership.UserID); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to delete membership: %v", err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+// joinDefaultOrganization makes a new user a member of the Default
+// organization, with the role of the user.
+func (s *APIV1Service) joinDefaultOrganization(ctx context.Context, user *store.User) error {
+	organization, err := s.store.GetDefaultOrganization(ctx)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.store.UpsertMembership(ctx, &store.Membership{
+		OrganizationID: organization.ID,
+		UserID:         user.ID,
+		Role:           user.Role,
+		CreatedTime:    time.Now(),
+	})
+	return err
+}
+
+func (s *APIV1Service) getOrganizationByID(ctx context.Context, id int64) (*store.Organization, error) {
+	organization, err := s.store.GetOrganization(ctx, &store.FindOrganization{ID: &id})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get organization: %v", err)
+	}
+
+	if organization == nil {
+		return nil, status.Errorf(codes.NotFound, "organization %d not found", id)
+	}
+
+	return organization, nil
+}
+
+func convertOrganizationFromStore(organization *store.Organization, role store.Role) *apiv1.Organization {
+	return &apiv1.Organization{
+		Id:        organization.ID,
+		Name:      organization.Name,
+		CreatedAt: timestamppb.New(organization.CreatedTime),
+		Role:      convertUserRoleFromStore(role),
+	}
+}
+
+func convertMembershipFromStore(membership *store.Membership, user *store.User) *apiv1.Membership {
+	return &apiv1.Membership{
+		OrganizationId: membership.OrganizationID,
+		UserId:         membership.UserID,
+		Username:       user.Username,
+		Role:           convertUserRoleFromStore(membership.Role),
+		CreatedAt:      timestamppb.New(membership.CreatedTime),
+	}
+}
@@ -0,0 +1,69 @@
+package v1
+
+import (
+	"context"
+	"testing"
+
+	apiv1 "github.com/thetnaingtn/dirty-hand/proto/gen/api/v1"
+	"github.com/thetnaingtn/dirty-hand/store"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestCreateUserJoinsDefaultOrganization(t *testing.T) {
+	s := newTestService(t, nil)
+	ctx := context.Background()
+
+	defaultOrganization, err := s.store.GetDefaultOrganization(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The first user becomes the admin, later ones product viewers.
+	tests := []struct {
+		username string
+		want     store.Role
+	}{
+		{"admin", store.RoleAdmin},
+		{"alice", store.RoleProductView},
+	}
+
+	for _, test := range tests {
+		user, err := s.CreateUser(ctx, &apiv1.CreateUserRequest{Username: test.username, Password: testPassword})
+		if err != nil {
+			t.Fatalf("CreateUser(%q) = %v", test.username, err)
+		}
+
+		membership, err := s.store.GetMembership(ctx, &store.FindMembership{OrganizationID: &defaultOrganization.ID, UserID: &user.Id})
+		if err != nil || membership == nil || membership.Role != test.want {
+			t.Errorf("membership of %s in the default organization = %+v, %v; want role %s", test.username, membership, err, test.want)
+		}
+	}
+}
+
+func TestDeleteOrganization(t *testing.T) {
+	s := newTestService(t, nil)
+	ctx := context.Background()
+
+	defaultOrganization, err := s.store.GetDefaultOrganization(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	acme := createTestOrganization(t, s, "acme")
+
+	tests := []struct {
+		name string
+		id   int64
+		want codes.Code
+	}{
+		{"default organization", defaultOrganization.ID, codes.FailedPrecondition},
+		{"organization", acme.ID, codes.OK},
+		{"deleted organization", acme.ID, codes.NotFound},
+	}
+
+	for _, test := range tests {
+		if _, err := s.DeleteOrganization(ctx, &apiv1.DeleteOrganizationRequest{Id: test.id}); status.Code(err) != test.want {
+			t.Errorf("DeleteOrganization of the %s = %v, want %s", test.name, err, test.want)
+		}
+	}
+}
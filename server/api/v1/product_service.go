@@ -2,20 +2,34 @@ package v1
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
 
 	apiv1 "github.com/thetnaingtn/dirty-hand/proto/gen/api/v1"
 	"github.com/thetnaingtn/dirty-hand/store"
 
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/emptypb"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 func (s *APIV1Service) CreateProduct(ctx context.Context, req *apiv1.CreateProductRequest) (*apiv1.Product, error) {
+	organizationId, err := organizationIdFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	prod := &store.Product{
-		Name:        req.GetName(),
-		Description: req.GetDescription(),
-		Price:       req.GetPrice(),
-		Cover:       req.GetCover(),
+		OrganizationID: organizationId,
+		Name:           req.GetName(),
+		Description:    req.GetDescription(),
+		Price:          req.GetPrice(),
+		Cover:          req.GetCover(),
 	}
 	created, err := s.store.CreateProduct(ctx, prod)
 	if err != nil {
@@ -25,51 +39,317 @@ func (s *APIV1Service) CreateProduct(ctx context.Context, req *apiv1.CreateProdu
 }
 
 func (s *APIV1Service) UpdateProduct(ctx context.Context, req *apiv1.UpdateProductRequest) (*apiv1.Product, error) {
+	organizationId, err := organizationIdFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	prod := &store.Product{
-		ID:          req.GetId(),
-		Name:        req.GetName(),
-		Description: req.GetDescription(),
-		Price:       req.GetPrice(),
-		Cover:       req.GetCover(),
+		OrganizationID: organizationId,
+		ID:             req.GetId(),
+		Name:           req.GetName(),
+		Description:    req.GetDescription(),
+		Price:          req.GetPrice(),
+		Cover:          req.GetCover(),
 	}
 	updated, err := s.store.UpdateProduct(ctx, prod)
 	if err != nil {
 		return nil, err
 	}
+	if updated == nil {
+		return nil, status.Errorf(codes.NotFound, "product %d not found", req.GetId())
+	}
 	return toProtoProduct(updated), nil
 }
 
+func (s *APIV1Service) GetProduct(ctx context.Context, req *apiv1.GetProductRequest) (*apiv1.Product, error) {
+	organizationId, err := organizationIdFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	prod, err := s.store.GetProduct(ctx, organizationId, req.GetId())
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get product: %v", err)
+	}
+	if prod == nil {
+		return nil, status.Errorf(codes.NotFound, "product %d not found", req.GetId())
+	}
+	return toProtoProduct(prod), nil
+}
+
 func (s *APIV1Service) ListProducts(ctx context.Context, req *apiv1.ListProductsRequest) (*apiv1.ListProductsResponse, error) {
-	prods, err := s.store.ListProducts(ctx)
+	organizationId, err := organizationIdFromContext(ctx)
 	if err != nil {
 		return nil, err
 	}
-	resp := &apiv1.ListProductsResponse{Products: make([]*apiv1.Product, 0, len(prods))}
+
+	find, err := buildFindProduct(req)
+	if err != nil {
+		return nil, err
+	}
+	find.OrganizationID = &organizationId
+
+	pageSize := int(req.GetPageSize())
+	if pageSize <= 0 {
+		pageSize = defaultProductPageSize
+	} else if pageSize > maxProductPageSize {
+		pageSize = maxProductPageSize
+	}
+
+	if req.GetPageToken() != "" {
+		after, err := decodeProductPageToken(req.GetPageToken(), req.GetOrderBy())
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid page token: %v", err)
+		}
+		find.After = after
+	}
+
+	// Fetch one extra row to find out whether there is a next page.
+	limit := pageSize + 1
+	find.Limit = &limit
+
+	prods, err := s.store.ListProducts(ctx, find)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list products: %v", err)
+	}
+
+	total, err := s.store.CountProducts(ctx, find)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to count products: %v", err)
+	}
+
+	resp := &apiv1.ListProductsResponse{TotalSize: int32(total)}
+	if len(prods) > pageSize {
+		prods = prods[:pageSize]
+		token, err := encodeProductPageToken(prods[len(prods)-1], req.GetOrderBy())
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to create page token: %v", err)
+		}
+		resp.NextPageToken = token
+	}
+
+	resp.Products = make([]*apiv1.Product, 0, len(prods))
 	for _, p := range prods {
-		cp := p // capture range variable
-		resp.Products = append(resp.Products, toProtoProduct(cp))
+		resp.Products = append(resp.Products, toProtoProduct(p))
+	}
+	return resp, nil
+}
+
+func (s *APIV1Service) SearchProducts(ctx context.Context, req *apiv1.SearchProductsRequest) (*apiv1.SearchProductsResponse, error) {
+	organizationId, err := organizationIdFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	terms := strings.Fields(req.GetQ())
+	if len(terms) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "q must not be empty")
+	}
+
+	pageSize := int(req.GetPageSize())
+	if pageSize <= 0 {
+		pageSize = defaultSearchPageSize
+	} else if pageSize > maxSearchPageSize {
+		pageSize = maxSearchPageSize
+	}
+
+	results, err := s.store.SearchProducts(ctx, &store.SearchProduct{
+		OrganizationID: &organizationId,
+		Terms:          terms,
+		Limit:          &pageSize,
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to search products: %v", err)
+	}
+
+	resp := &apiv1.SearchProductsResponse{Results: make([]*apiv1.ProductSearchResult, 0, len(results))}
+	for _, r := range results {
+		resp.Results = append(resp.Results, &apiv1.ProductSearchResult{
+			Product:            toProtoProduct(r.Product),
+			NameHighlight:      r.NameHighlight,
+			DescriptionSnippet: r.DescriptionSnippet,
+			Score:              r.Score,
+		})
 	}
 	return resp, nil
 }
 
 func (s *APIV1Service) DeleteProduct(ctx context.Context, req *apiv1.DeleteProductRequest) (*emptypb.Empty, error) {
-	if err := s.store.DeleteProduct(ctx, req.GetId()); err != nil {
+	organizationId, err := organizationIdFromContext(ctx)
+	if err != nil {
 		return nil, err
 	}
+
+	deleted, err := s.store.DeleteProduct(ctx, organizationId, req.GetId())
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to delete product: %v", err)
+	}
+	if !deleted {
+		return nil, status.Errorf(codes.NotFound, "product %d not found", req.GetId())
+	}
 	return &emptypb.Empty{}, nil
 }
 
+// organizationIdFromContext returns the organization the auth interceptor
+// resolved for the call.
+func organizationIdFromContext(ctx context.Context) (int64, error) {
+	organizationId, ok := ctx.Value(organizationIdContextKey).(int64)
+	if !ok {
+		return 0, status.Error(codes.FailedPrecondition, "no organization selected")
+	}
+
+	return organizationId, nil
+}
+
 func toProtoProduct(p *store.Product) *apiv1.Product {
 	if p == nil {
 		return nil
 	}
 	return &apiv1.Product{
-		Id:          p.ID,
-		Name:        p.Name,
-		Description: p.Description,
-		Price:       p.Price,
-		Cover:       p.Cover,
-		CreatedAt:   timestamppb.New(p.CreatedAt),
-		UpdatedAt:   timestamppb.New(p.UpdatedAt),
+		Id:             p.ID,
+		OrganizationId: p.OrganizationID,
+		Name:           p.Name,
+		Description:    p.Description,
+		Price:          p.Price,
+		Cover:          p.Cover,
+		CreatedAt:      timestamppb.New(p.CreatedAt),
+		UpdatedAt:      timestamppb.New(p.UpdatedAt),
+	}
+}
+
+const (
+	defaultProductPageSize = 50
+	maxProductPageSize     = 1000
+
+	defaultSearchPageSize = 20
+	maxSearchPageSize     = 100
+)
+
+func buildFindProduct(req *apiv1.ListProductsRequest) (*store.FindProduct, error) {
+	find := &store.FindProduct{}
+
+	orderBy, descending, err := parseProductOrderBy(req.GetOrderBy())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid order_by: %v", err)
+	}
+	find.OrderBy = orderBy
+	find.Descending = descending
+
+	filter := req.GetFilter()
+	if filter == nil {
+		return find, nil
+	}
+
+	if name := filter.GetName(); name != "" {
+		find.Name = &name
+	}
+	if filter.MinPrice != nil {
+		minPrice := filter.GetMinPrice()
+		find.MinPrice = &minPrice
+	}
+	if filter.MaxPrice != nil {
+		maxPrice := filter.GetMaxPrice()
+		find.MaxPrice = &maxPrice
+	}
+	if find.MinPrice != nil && find.MaxPrice != nil && *find.MinPrice > *find.MaxPrice {
+		return nil, status.Error(codes.InvalidArgument, "min_price must not be greater than max_price")
+	}
+
+	find.CreatedAfter = timestampToTime(filter.GetCreatedAfter())
+	find.CreatedBefore = timestampToTime(filter.GetCreatedBefore())
+	find.UpdatedAfter = timestampToTime(filter.GetUpdatedAfter())
+	find.UpdatedBefore = timestampToTime(filter.GetUpdatedBefore())
+
+	return find, nil
+}
+
+// parseProductOrderBy parses an order_by value such as "price desc".
+func parseProductOrderBy(orderBy string) (store.ProductOrderField, bool, error) {
+	fields := strings.Fields(orderBy)
+	if len(fields) == 0 {
+		return store.ProductOrderByID, false, nil
+	}
+	if len(fields) > 2 {
+		return "", false, fmt.Errorf("expected \"<field> [asc|desc]\", got %q", orderBy)
+	}
+
+	var field store.ProductOrderField
+	switch f := store.ProductOrderField(fields[0]); f {
+	case store.ProductOrderByName, store.ProductOrderByPrice, store.ProductOrderByCreatedAt, store.ProductOrderByUpdatedAt:
+		field = f
+	default:
+		return "", false, fmt.Errorf("unsupported field %q", fields[0])
+	}
+
+	descending := false
+	if len(fields) == 2 {
+		switch strings.ToLower(fields[1]) {
+		case "asc":
+		case "desc":
+			descending = true
+		default:
+			return "", false, fmt.Errorf("unsupported direction %q", fields[1])
+		}
+	}
+
+	return field, descending, nil
+}
+
+// productPageToken is the cursor handed out as next_page_token. It records
+// the sort key of the last product on the page together with the order_by
+// it was issued for, so a token cannot be replayed against another ordering.
+type productPageToken struct {
+	OrderBy   string    `json:"order_by,omitempty"`
+	ID        int64     `json:"id"`
+	Name      string    `json:"name,omitempty"`
+	Price     float64   `json:"price,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func encodeProductPageToken(last *store.Product, orderBy string) (string, error) {
+	data, err := json.Marshal(productPageToken{
+		OrderBy:   orderBy,
+		ID:        last.ID,
+		Name:      last.Name,
+		Price:     last.Price,
+		CreatedAt: last.CreatedAt,
+		UpdatedAt: last.UpdatedAt,
+	})
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+func decodeProductPageToken(token, orderBy string) (*store.Product, error) {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, err
+	}
+
+	var t productPageToken
+	if err := json.Unmarshal(data, &t); err != nil {
+		return nil, err
+	}
+	if t.OrderBy != orderBy {
+		return nil, errors.New("token was issued for a different order_by")
+	}
+
+	return &store.Product{
+		ID:        t.ID,
+		Name:      t.Name,
+		Price:     t.Price,
+		CreatedAt: t.CreatedAt,
+		UpdatedAt: t.UpdatedAt,
+	}, nil
+}
+
+func timestampToTime(ts *timestamppb.Timestamp) *time.Time {
+	if ts == nil {
+		return nil
 	}
+	t := ts.AsTime()
+	return &t
 }
@@ -0,0 +1,232 @@
+package v1
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	apiv1 "github.com/thetnaingtn/dirty-hand/proto/gen/api/v1"
+	"github.com/thetnaingtn/dirty-hand/store"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestGetProduct(t *testing.T) {
+	s := newTestService(t, nil)
+	acme := createTestOrganization(t, s, "acme")
+	globex := createTestOrganization(t, s, "globex")
+
+	product, err := s.store.CreateProduct(context.Background(), &store.Product{OrganizationID: acme.ID, Name: "Red kettle", Price: 25})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name           string
+		organizationId int64
+		id             int64
+		want           codes.Code
+	}{
+		{"product", acme.ID, product.ID, codes.OK},
+		{"unknown product", acme.ID, product.ID + 1, codes.NotFound},
+		{"product of another organization", globex.ID, product.ID, codes.NotFound},
+	}
+
+	for _, test := range tests {
+		ctx := withOrganization(context.Background(), test.organizationId)
+		got, err := s.GetProduct(ctx, &apiv1.GetProductRequest{Id: test.id})
+		if status.Code(err) != test.want {
+			t.Errorf("GetProduct of %s = %v, want %s", test.name, err, test.want)
+			continue
+		}
+		if err == nil && (got.Id != product.ID || got.Name != product.Name) {
+			t.Errorf("GetProduct of %s = %+v, want %+v", test.name, got, product)
+		}
+	}
+}
+
+func TestListProducts(t *testing.T) {
+	s := newTestService(t, nil)
+	acme := createTestOrganization(t, s, "acme")
+	globex := createTestOrganization(t, s, "globex")
+	ctx := withOrganization(context.Background(), acme.ID)
+
+	// Products of other organizations are never listed.
+	if _, err := s.store.CreateProduct(ctx, &store.Product{OrganizationID: globex.ID, Name: "Red teapot", Price: 20}); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, product := range []*store.Product{
+		{Name: "Red kettle", Price: 25},
+		{Name: "Blue kettle", Price: 30},
+		{Name: "Teapot", Price: 15},
+		{Name: "Red mug", Price: 5},
+		{Name: "Saucer", Price: 5},
+	} {
+		product.OrganizationID = acme.ID
+		if _, err := s.store.CreateProduct(ctx, product); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	price := func(price float64) *float64 { return &price }
+	tests := []struct {
+		name     string
+		req      *apiv1.ListProductsRequest
+		want     []string
+		wantSize int32
+	}{
+		{"default order", &apiv1.ListProductsRequest{}, []string{"Red kettle", "Blue kettle", "Teapot", "Red mug", "Saucer"}, 5},
+		{"pages", &apiv1.ListProductsRequest{PageSize: 2}, []string{"Red kettle", "Blue kettle", "Teapot", "Red mug", "Saucer"}, 5},
+		{"name order", &apiv1.ListProductsRequest{PageSize: 2, OrderBy: "name"}, []string{"Blue kettle", "Red kettle", "Red mug", "Saucer", "Teapot"}, 5},
+		// Equal prices are ordered by ID in the same direction.
+		{"price order descending", &apiv1.ListProductsRequest{PageSize: 2, OrderBy: "price DESC"}, []string{"Blue kettle", "Red kettle", "Teapot", "Saucer", "Red mug"}, 5},
+		{"name filter", &apiv1.ListProductsRequest{PageSize: 1, Filter: &apiv1.ProductFilter{Name: "red"}}, []string{"Red kettle", "Red mug"}, 2},
+		{"price filter", &apiv1.ListProductsRequest{PageSize: 1, OrderBy: "price", Filter: &apiv1.ProductFilter{MinPrice: price(5), MaxPrice: price(15)}}, []string{"Red mug", "Saucer", "Teapot"}, 3},
+		{"no match", &apiv1.ListProductsRequest{Filter: &apiv1.ProductFilter{Name: "spoon"}}, nil, 0},
+	}
+
+	for _, test := range tests {
+		req := test.req
+		var got []string
+		for {
+			resp, err := s.ListProducts(ctx, req)
+			if err != nil {
+				t.Fatalf("ListProducts with %s = %v", test.name, err)
+			}
+			if resp.TotalSize != test.wantSize {
+				t.Errorf("ListProducts with %s has total_size %d, want %d", test.name, resp.TotalSize, test.wantSize)
+			}
+			for _, product := range resp.Products {
+				got = append(got, product.Name)
+			}
+			if resp.NextPageToken == "" {
+				break
+			}
+			req = proto.Clone(req).(*apiv1.ListProductsRequest)
+			req.PageToken = resp.NextPageToken
+		}
+		if !slices.Equal(got, test.want) {
+			t.Errorf("ListProducts with %s = %v, want %v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestListProductsInvalidArgument(t *testing.T) {
+	s := newTestService(t, nil)
+	acme := createTestOrganization(t, s, "acme")
+	ctx := withOrganization(context.Background(), acme.ID)
+
+	for i := 0; i < 2; i++ {
+		if _, err := s.store.CreateProduct(ctx, &store.Product{OrganizationID: acme.ID, Name: "Red kettle", Price: 25}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	resp, err := s.ListProducts(ctx, &apiv1.ListProductsRequest{PageSize: 1, OrderBy: "price"})
+	if err != nil || resp.NextPageToken == "" {
+		t.Fatalf("ListProducts = %v, %v; want a next page", resp, err)
+	}
+
+	price := func(price float64) *float64 { return &price }
+	tests := []struct {
+		name string
+		req  *apiv1.ListProductsRequest
+	}{
+		{"unknown order field", &apiv1.ListProductsRequest{OrderBy: "cover"}},
+		{"unknown order direction", &apiv1.ListProductsRequest{OrderBy: "price up"}},
+		{"malformed page token", &apiv1.ListProductsRequest{PageToken: "not a token"}},
+		{"page token of another order", &apiv1.ListProductsRequest{OrderBy: "name", PageToken: resp.NextPageToken}},
+		{"inverted price range", &apiv1.ListProductsRequest{Filter: &apiv1.ProductFilter{MinPrice: price(10), MaxPrice: price(5)}}},
+	}
+
+	for _, test := range tests {
+		if _, err := s.ListProducts(ctx, test.req); status.Code(err) != codes.InvalidArgument {
+			t.Errorf("ListProducts with %s = %v, want InvalidArgument", test.name, err)
+		}
+	}
+}
+
+func TestSearchProducts(t *testing.T) {
+	s := newTestService(t, nil)
+	acme := createTestOrganization(t, s, "acme")
+	globex := createTestOrganization(t, s, "globex")
+	ctx := withOrganization(context.Background(), acme.ID)
+
+	if _, err := s.SearchProducts(ctx, &apiv1.SearchProductsRequest{Q: "kettle"}); status.Code(err) == codes.Unimplemented {
+		t.Skip(err)
+	}
+
+	for _, product := range []*store.Product{
+		{Name: "Red kettle", Description: "A kettle for the stove", Price: 25},
+		{Name: "Teapot", Description: "Goes with the red kettle", Price: 15},
+		{Name: "Saucer", Description: "Plain white", Price: 5},
+		// Products of other organizations are never found.
+		{OrganizationID: globex.ID, Name: "Blue kettle", Price: 30},
+	} {
+		if product.OrganizationID == 0 {
+			product.OrganizationID = acme.ID
+		}
+		if _, err := s.store.CreateProduct(ctx, product); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		q    string
+		want []string
+	}{
+		{"kettle", []string{"Red kettle", "Teapot"}},
+		{"red kett", []string{"Red kettle", "Teapot"}},
+		{"white", []string{"Saucer"}},
+		{`"kettle" OR saucer`, nil},
+		{"spoon", nil},
+	}
+
+	for _, test := range tests {
+		resp, err := s.SearchProducts(ctx, &apiv1.SearchProductsRequest{Q: test.q})
+		if err != nil {
+			t.Fatalf("SearchProducts(%q) = %v", test.q, err)
+		}
+		var got []string
+		for _, result := range resp.Results {
+			got = append(got, result.Product.Name)
+		}
+		if !slices.Equal(got, test.want) {
+			t.Errorf("SearchProducts(%q) = %v, want %v", test.q, got, test.want)
+		}
+	}
+
+	if _, err := s.SearchProducts(ctx, &apiv1.SearchProductsRequest{Q: "  "}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("SearchProducts with a blank query = %v, want InvalidArgument", err)
+	}
+}
+
+func TestDeleteProduct(t *testing.T) {
+	s := newTestService(t, nil)
+	acme := createTestOrganization(t, s, "acme")
+	globex := createTestOrganization(t, s, "globex")
+
+	product, err := s.store.CreateProduct(context.Background(), &store.Product{OrganizationID: acme.ID, Name: "Red kettle", Price: 25})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Another organization can neither delete the product nor learn that it
+	// exists.
+	ctx := withOrganization(context.Background(), globex.ID)
+	if _, err := s.DeleteProduct(ctx, &apiv1.DeleteProductRequest{Id: product.ID}); status.Code(err) != codes.NotFound {
+		t.Fatalf("DeleteProduct from another organization = %v, want NotFound", err)
+	}
+
+	ctx = withOrganization(context.Background(), acme.ID)
+	if _, err := s.DeleteProduct(ctx, &apiv1.DeleteProductRequest{Id: product.ID}); err != nil {
+		t.Fatalf("DeleteProduct = %v", err)
+	}
+	if _, err := s.GetProduct(ctx, &apiv1.GetProductRequest{Id: product.ID}); status.Code(err) != codes.NotFound {
+		t.Errorf("GetProduct after DeleteProduct = %v, want NotFound", err)
+	}
+	if _, err := s.DeleteProduct(ctx, &apiv1.DeleteProductRequest{Id: product.ID}); status.Code(err) != codes.NotFound {
+		t.Errorf("DeleteProduct twice = %v, want NotFound", err)
+	}
+}
@@ -0,0 +1,185 @@
+package v1
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/thetnaingtn/dirty-hand/internal/config"
+)
+
+const (
+	signedCookiePrefix    = "s1."
+	encryptedCookiePrefix = "e1."
+)
+
+var errInvalidSessionCookie = errors.New("invalid session cookie")
+
+// sessionCookieKey holds the keys derived from one configured cookie key.
+type sessionCookieKey struct {
+	mac  []byte
+	aead cipher.AEAD
+}
+
+// sessionCookieCodec encodes the user_session cookie value. Cookies are
+// either signed with HMAC-SHA256 ("s1.<payload>.<mac>") or encrypted with
+// AES-GCM ("e1.<nonce+ciphertext>"), so a tampered cookie is rejected
+// before the database is consulted. New cookies use the first configured key
+// and cookies made with any configured key are accepted.
+type sessionCookieCodec struct {
+	keys    []sessionCookieKey
+	encrypt bool
+}
+
+func newSessionCookieCodec(cfg config.SessionConfig) (*sessionCookieCodec, error) {
+	if len(cfg.CookieKeys) == 0 {
+		return nil, errors.New("no session cookie key configured")
+	}
+
+	codec := &sessionCookieCodec{encrypt: cfg.EncryptCookie}
+	for _, key := range cfg.CookieKeys {
+		aead, err := newCookieAEAD(deriveCookieKey(key, "encryption"))
+		if err != nil {
+			return nil, err
+		}
+
+		codec.keys = append(codec.keys, sessionCookieKey{
+			mac:  deriveCookieKey(key, "signing"),
+			aead: aead,
+		})
+	}
+
+	return codec, nil
+}
+
+// deriveCookieKey derives separate signing and encryption keys from one
+// configured key.
+func deriveCookieKey(key, purpose string) []byte {
+	mac := hmac.New(sha256.New, []byte(key))
+	mac.Write([]byte("user_session " + purpose))
+	return mac.Sum(nil)
+}
+
+func newCookieAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
+
+func (c *sessionCookieCodec) encode(userId int64, sessionId string) (string, error) {
+	payload := []byte(fmt.Sprintf("%d-%s", userId, sessionId))
+	key := c.keys[0]
+
+	if c.encrypt {
+		nonce := make([]byte, key.aead.NonceSize())
+		if _, err := rand.Read(nonce); err != nil {
+			return "", err
+		}
+
+		sealed := key.aead.Seal(nonce, nonce, payload, []byte(encryptedCookiePrefix))
+		return encryptedCookiePrefix + base64.RawURLEncoding.EncodeToString(sealed), nil
+	}
+
+	signed := signedCookiePrefix + base64.RawURLEncoding.EncodeToString(payload)
+	return signed + "." + base64.RawURLEncoding.EncodeToString(signCookie(key.mac, signed)), nil
+}
+
+// decode verifies a cookie value and returns the user ID and session ID it
+// carries.
+func (c *sessionCookieCodec) decode(value string) (int64, string, error) {
+	var payload []byte
+
+	switch {
+	case strings.HasPrefix(value, signedCookiePrefix):
+		dot := strings.LastIndexByte(value, '.')
+		if dot <= len(signedCookiePrefix) {
+			return 0, "", errInvalidSessionCookie
+		}
+
+		signed := value[:dot]
+		mac, err := base64.RawURLEncoding.DecodeString(value[dot+1:])
+		if err != nil {
+			return 0, "", errInvalidSessionCookie
+		}
+
+		if !c.verify(signed, mac) {
+			return 0, "", errInvalidSessionCookie
+		}
+
+		payload, err = base64.RawURLEncoding.DecodeString(signed[len(signedCookiePrefix):])
+		if err != nil {
+			return 0, "", errInvalidSessionCookie
+		}
+	case strings.HasPrefix(value, encryptedCookiePrefix):
+		sealed, err := base64.RawURLEncoding.DecodeString(value[len(encryptedCookiePrefix):])
+		if err != nil {
+			return 0, "", errInvalidSessionCookie
+		}
+
+		payload, err = c.open(sealed)
+		if err != nil {
+			return 0, "", errInvalidSessionCookie
+		}
+	default:
+		return 0, "", errInvalidSessionCookie
+	}
+
+	return parseSessionPayload(string(payload))
+}
+
+func (c *sessionCookieCodec) verify(signed string, mac []byte) bool {
+	for _, key := range c.keys {
+		if hmac.Equal(mac, signCookie(key.mac, signed)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (c *sessionCookieCodec) open(sealed []byte) ([]byte, error) {
+	for _, key := range c.keys {
+		nonceSize := key.aead.NonceSize()
+		if len(sealed) < nonceSize {
+			return nil, errInvalidSessionCookie
+		}
+
+		payload, err := key.aead.Open(nil, sealed[:nonceSize], sealed[nonceSize:], []byte(encryptedCookiePrefix))
+		if err == nil {
+			return payload, nil
+		}
+	}
+
+	return nil, errInvalidSessionCookie
+}
+
+func signCookie(key []byte, signed string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(signed))
+	return mac.Sum(nil)
+}
+
+// parseSessionPayload splits a "<userId>-<sessionId>" payload.
+func parseSessionPayload(payload string) (int64, string, error) {
+	userIdStr, sessionId, ok := strings.Cut(payload, "-")
+	if !ok || sessionId == "" {
+		return 0, "", errInvalidSessionCookie
+	}
+
+	userId, err := strconv.ParseInt(userIdStr, 10, 64)
+	if err != nil {
+		return 0, "", errInvalidSessionCookie
+	}
+
+	return userId, sessionId, nil
+}
@@ -0,0 +1,135 @@
+package v1
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/thetnaingtn/dirty-hand/internal/config"
+)
+
+func newTestCookieCodec(t *testing.T, encrypt bool, keys ...string) *sessionCookieCodec {
+	t.Helper()
+
+	codec, err := newSessionCookieCodec(config.SessionConfig{CookieKeys: keys, EncryptCookie: encrypt})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return codec
+}
+
+func TestSessionCookieCodec(t *testing.T) {
+	for _, encrypt := range []bool{false, true} {
+		codec := newTestCookieCodec(t, encrypt, "current-key")
+
+		value, err := codec.encode(42, "3f1c-session")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		userId, sessionId, err := codec.decode(value)
+		if err != nil || userId != 42 || sessionId != "3f1c-session" {
+			t.Errorf("decode(encode(42, %q)) with encrypt %t = %d, %q, %v", "3f1c-session", encrypt, userId, sessionId, err)
+		}
+
+		if encrypt && strings.Contains(value, base64.RawURLEncoding.EncodeToString([]byte("42-"))) {
+			t.Errorf("encrypted cookie %q shows its payload", value)
+		}
+	}
+}
+
+func TestSessionCookieCodecTampering(t *testing.T) {
+	signer := newTestCookieCodec(t, false, "current-key")
+	signed, err := signer.encode(42, "session")
+	if err != nil {
+		t.Fatal(err)
+	}
+	encrypter := newTestCookieCodec(t, true, "current-key")
+	encrypted, err := encrypter.encode(42, "session")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	payload, mac, _ := strings.Cut(strings.TrimPrefix(signed, signedCookiePrefix), ".")
+	forged := signedCookiePrefix + base64.RawURLEncoding.EncodeToString([]byte("1-session"))
+
+	tests := []struct {
+		name  string
+		codec *sessionCookieCodec
+		value string
+	}{
+		{"empty", signer, ""},
+		{"unknown format", signer, "42-session"},
+		{"other user", signer, forged + "." + mac},
+		{"missing mac", signer, signedCookiePrefix + payload},
+		{"empty mac", signer, signedCookiePrefix + payload + "."},
+		{"truncated mac", signer, signed[:len(signed)-2]},
+		{"invalid mac encoding", signer, signedCookiePrefix + payload + ".!!"},
+		{"other key", newTestCookieCodec(t, false, "other-key"), signed},
+		{"flipped ciphertext", encrypter, flipCookieByte(t, encrypted)},
+		{"truncated ciphertext", encrypter, encryptedCookiePrefix + "AAAA"},
+		{"encrypted with other key", newTestCookieCodec(t, true, "other-key"), encrypted},
+		{"signed cookie as encrypted", encrypter, encryptedCookiePrefix + strings.TrimPrefix(signed, signedCookiePrefix)},
+	}
+
+	for _, test := range tests {
+		if userId, sessionId, err := test.codec.decode(test.value); err == nil {
+			t.Errorf("decode of %s = %d, %q; want an error", test.name, userId, sessionId)
+		}
+	}
+}
+
+func TestSessionCookieCodecKeyRotation(t *testing.T) {
+	old := newTestCookieCodec(t, false, "old-key")
+	oldEncrypted := newTestCookieCodec(t, true, "old-key")
+	rotated := newTestCookieCodec(t, false, "new-key", "old-key")
+	retired := newTestCookieCodec(t, false, "new-key")
+
+	for _, codec := range []*sessionCookieCodec{old, oldEncrypted} {
+		value, err := codec.encode(42, "session")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Cookies made with a previous key stay valid while it is configured,
+		// whichever format the server now uses.
+		if _, _, err := rotated.decode(value); err != nil {
+			t.Errorf("decode of a cookie made with a previous key = %v", err)
+		}
+		if _, _, err := retired.decode(value); err == nil {
+			t.Error("decode of a cookie made with a removed key succeeded")
+		}
+	}
+
+	// New cookies are made with the first key.
+	value, err := rotated.encode(42, "session")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := retired.decode(value); err != nil {
+		t.Errorf("decode of a new cookie with the first key = %v", err)
+	}
+	if _, _, err := old.decode(value); err == nil {
+		t.Error("a new cookie was made with a previous key")
+	}
+}
+
+func TestNewSessionCookieCodecWithoutKey(t *testing.T) {
+	if _, err := newSessionCookieCodec(config.SessionConfig{}); err == nil {
+		t.Error("newSessionCookieCodec without a key succeeded")
+	}
+}
+
+// flipCookieByte flips a bit of the last byte of an encrypted cookie.
+func flipCookieByte(t *testing.T, value string) string {
+	t.Helper()
+
+	sealed, err := base64.RawURLEncoding.DecodeString(strings.TrimPrefix(value, encryptedCookiePrefix))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sealed[len(sealed)-1] ^= 1
+
+	return encryptedCookiePrefix + base64.RawURLEncoding.EncodeToString(sealed)
+}
@@ -0,0 +1,155 @@
+package v1
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/thetnaingtn/dirty-hand/store"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// errInvalidCredentials is returned for unknown usernames and wrong passwords
+// alike, so that sign-in does not reveal which usernames exist.
+var errInvalidCredentials = status.Error(codes.Unauthenticated, "invalid username or password")
+
+func userThrottleKey(username string) string {
+	return "user:" + strings.ToLower(username)
+}
+
+func ipThrottleKey(clientIP string) string {
+	return "ip:" + clientIP
+}
+
+// signInThrottleKey is a key that failed sign-ins are counted against.
+type signInThrottleKey struct {
+	key         string
+	maxFailures int
+	// backoff makes every failure delay the next attempt. It is off for
+	// client IPs, which many users may share.
+	backoff bool
+}
+
+// signInThrottleKeys returns the throttle keys of a sign-in attempt.
+func (s *APIV1Service) signInThrottleKeys(ctx context.Context, username string) []signInThrottleKey {
+	keys := []signInThrottleKey{{
+		key:         userThrottleKey(username),
+		maxFailures: s.config.SignIn.MaxUserFailures,
+		backoff:     true,
+	}}
+
+	if clientIP := s.trustedProxies.clientIP(ctx); clientIP != "" {
+		keys = append(keys, signInThrottleKey{
+			key:         ipThrottleKey(clientIP),
+			maxFailures: s.config.SignIn.MaxIPFailures,
+		})
+	}
+
+	return keys
+}
+
+// checkSignInThrottle refuses a sign-in attempt while one of its keys is
+// locked out or still backing off from earlier failures.
+func (s *APIV1Service) checkSignInThrottle(ctx context.Context, keys []signInThrottleKey) error {
+	now := time.Now()
+
+	for _, key := range keys {
+		throttle, err := s.store.GetSignInThrottle(ctx, key.key)
+		if err != nil {
+			return status.Errorf(codes.Internal, "failed to get sign-in throttle: %v", err)
+		}
+
+		if throttle == nil {
+			continue
+		}
+
+		if wait := s.signInWait(throttle, key.backoff, now); wait > 0 {
+			return status.Errorf(codes.ResourceExhausted, "too many failed sign-in attempts, try again in %s", wait.Round(time.Second))
+		}
+	}
+
+	return nil
+}
+
+// signInWait returns how long a key has to wait before its next attempt.
+func (s *APIV1Service) signInWait(throttle *store.SignInThrottle, backoff bool, now time.Time) time.Duration {
+	if throttle.LockedUntil.After(now) {
+		return throttle.LockedUntil.Sub(now)
+	}
+
+	if !backoff || !throttle.LockedUntil.IsZero() || now.Sub(throttle.LastFailureTime) > s.config.SignIn.FailureWindow {
+		return 0
+	}
+
+	return max(throttle.LastFailureTime.Add(s.signInBackoff(throttle.Failures)).Sub(now), 0)
+}
+
+// signInBackoff doubles the configured base wait for every failure after
+// the first, up to the configured maximum.
+func (s *APIV1Service) signInBackoff(failures int) time.Duration {
+	cfg := s.config.SignIn
+	if cfg.BackoffBase <= 0 || failures <= 0 {
+		return 0
+	}
+
+	backoff := cfg.BackoffBase
+	for i := 1; i < failures && (cfg.BackoffMax <= 0 || backoff < cfg.BackoffMax); i++ {
+		backoff *= 2
+	}
+
+	if cfg.BackoffMax > 0 {
+		backoff = min(backoff, cfg.BackoffMax)
+	}
+
+	return backoff
+}
+
+// recordSignInFailure counts a failed attempt against every key and locks
+// out the keys that reach their limit. The counts are read and written in a
+// transaction, so that concurrent failures are all counted.
+func (s *APIV1Service) recordSignInFailure(ctx context.Context, keys []signInThrottleKey) error {
+	now := time.Now()
+
+	err := s.store.WithTx(ctx, func(ctx context.Context) error {
+		for _, key := range keys {
+			throttle, err := s.store.GetSignInThrottle(ctx, key.key)
+			if err != nil {
+				return err
+			}
+
+			// Start over once earlier failures are forgotten or a lockout ended.
+			if throttle == nil || !throttle.LockedUntil.IsZero() || now.Sub(throttle.LastFailureTime) > s.config.SignIn.FailureWindow {
+				throttle = &store.SignInThrottle{Key: key.key}
+			}
+
+			throttle.Failures++
+			throttle.LastFailureTime = now
+			if key.maxFailures > 0 && throttle.Failures >= key.maxFailures {
+				throttle.LockedUntil = now.Add(s.config.SignIn.LockoutDuration)
+			}
+
+			if _, err := s.store.UpsertSignInThrottle(ctx, throttle); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to record failed sign-in: %v", err)
+	}
+
+	return nil
+}
+
+// resetUserSignInThrottle forgets the failed attempts of a username after a
+// successful sign-in. Failures of the client IP are kept, so that signing in
+// to one account does not allow guessing the passwords of others.
+func (s *APIV1Service) resetUserSignInThrottle(ctx context.Context, username string) error {
+	if err := s.store.DeleteSignInThrottle(ctx, userThrottleKey(username)); err != nil {
+		return status.Errorf(codes.Internal, "failed to reset sign-in throttle: %v", err)
+	}
+
+	return nil
+}
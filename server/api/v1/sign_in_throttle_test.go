@@ -0,0 +1,85 @@
+package v1
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/thetnaingtn/dirty-hand/internal/config"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestSignInBackoff(t *testing.T) {
+	s := &APIV1Service{config: &config.Config{SignIn: config.SignInConfig{BackoffBase: time.Second, BackoffMax: 10 * time.Second}}}
+
+	tests := []struct {
+		failures int
+		want     time.Duration
+	}{
+		{0, 0},
+		{1, time.Second},
+		{2, 2 * time.Second},
+		{4, 8 * time.Second},
+		{5, 10 * time.Second},
+		{100, 10 * time.Second},
+	}
+
+	for _, test := range tests {
+		if got := s.signInBackoff(test.failures); got != test.want {
+			t.Errorf("signInBackoff(%d) = %s, want %s", test.failures, got, test.want)
+		}
+	}
+}
+
+func TestRecordSignInFailure(t *testing.T) {
+	s := newTestService(t, func(cfg *config.Config) {
+		cfg.SignIn.MaxUserFailures = 3
+	})
+	ctx := context.Background()
+	keys := s.signInThrottleKeys(ctx, "Alice")
+
+	for i := 0; i < 2; i++ {
+		if err := s.recordSignInFailure(ctx, keys); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := s.checkSignInThrottle(ctx, keys); err != nil {
+		t.Fatalf("checkSignInThrottle after 2 failures = %v, want nil", err)
+	}
+
+	if err := s.recordSignInFailure(ctx, keys); err != nil {
+		t.Fatal(err)
+	}
+	// Usernames are throttled whatever their case.
+	if err := s.checkSignInThrottle(ctx, s.signInThrottleKeys(ctx, "alice")); status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("checkSignInThrottle after 3 failures = %v, want ResourceExhausted", err)
+	}
+}
+
+func TestRecordSignInFailureConcurrently(t *testing.T) {
+	s := newTestService(t, func(cfg *config.Config) {
+		cfg.SignIn.MaxUserFailures = 0
+	})
+	ctx := context.Background()
+	keys := s.signInThrottleKeys(ctx, "alice")
+
+	const attempts = 20
+	var wg sync.WaitGroup
+	for i := 0; i < attempts; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := s.recordSignInFailure(ctx, keys); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	throttle, err := s.store.GetSignInThrottle(ctx, userThrottleKey("alice"))
+	if err != nil || throttle == nil || throttle.Failures != attempts {
+		t.Fatalf("GetSignInThrottle = %+v, %v; want %d failures", throttle, err, attempts)
+	}
+}
@@ -0,0 +1,352 @@
+package v1
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	apiv1 "github.com/thetnaingtn/dirty-hand/proto/gen/api/v1"
+	"github.com/thetnaingtn/dirty-hand/store"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	totpIssuer = "dirty-hand"
+	totpDigits = 6
+	totpPeriod = 30 * time.Second
+	// totpSkew is how many time steps a code may be off, to allow for clock
+	// drift between the server and the authenticator.
+	totpSkew = 1
+
+	recoveryCodeCount = 10
+
+	secondFactorChallengeTTL         = 5 * time.Minute
+	secondFactorChallengeMaxAttempts = 5
+)
+
+var totpEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+func (s *APIV1Service) EnrollTOTP(ctx context.Context, req *apiv1.EnrollTOTPRequest) (*apiv1.TOTPEnrollment, error) {
+	userId, ok := ctx.Value(userIdContextKey).(int64)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "authentication required")
+	}
+
+	user, err := s.getUserByID(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	totp, err := s.store.GetUserTOTP(ctx, userId)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get totp: %v", err)
+	}
+
+	if totp != nil && totp.Enabled {
+		return nil, status.Error(codes.FailedPrecondition, "two-factor authentication is already enabled")
+	}
+
+	secret := make([]byte, 20)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to generate secret: %v", err)
+	}
+
+	recoveryCodes := make([]string, 0, recoveryCodeCount)
+	recoveryCodeHashes := make([]string, 0, recoveryCodeCount)
+	for range recoveryCodeCount {
+		code, err := generateRecoveryCode()
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to generate recovery code: %v", err)
+		}
+		recoveryCodes = append(recoveryCodes, code)
+		recoveryCodeHashes = append(recoveryCodeHashes, hashToken(code))
+	}
+
+	totp, err = s.store.EnrollUserTOTP(ctx, &store.UserTOTP{
+		UserID:      userId,
+		Secret:      totpEncoding.EncodeToString(secret),
+		CreatedTime: time.Now(),
+	}, recoveryCodeHashes)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to enroll totp: %v", err)
+	}
+
+	return &apiv1.TOTPEnrollment{
+		Secret:        totp.Secret,
+		Uri:           totpURI(totp.Secret, user.Username),
+		RecoveryCodes: recoveryCodes,
+	}, nil
+}
+
+func (s *APIV1Service) VerifyTOTP(ctx context.Context, req *apiv1.VerifyTOTPRequest) (*emptypb.Empty, error) {
+	userId, ok := ctx.Value(userIdContextKey).(int64)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "authentication required")
+	}
+
+	totp, err := s.store.GetUserTOTP(ctx, userId)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get totp: %v", err)
+	}
+
+	if totp == nil {
+		return nil, status.Error(codes.FailedPrecondition, "no two-factor enrollment in progress")
+	}
+
+	if totp.Enabled {
+		return nil, status.Error(codes.FailedPrecondition, "two-factor authentication is already enabled")
+	}
+
+	ok, err = s.verifyTOTPCode(ctx, totp, req.GetCode())
+	if err != nil {
+		return nil, err
+	}
+
+	if !ok {
+		return nil, status.Error(codes.InvalidArgument, "invalid code")
+	}
+
+	if err := s.store.EnableUserTOTP(ctx, userId); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to enable totp: %v", err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (s *APIV1Service) DisableTOTP(ctx context.Context, req *apiv1.DisableTOTPRequest) (*emptypb.Empty, error) {
+	userId, ok := ctx.Value(userIdContextKey).(int64)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "authentication required")
+	}
+
+	totp, err := s.store.GetUserTOTP(ctx, userId)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get totp: %v", err)
+	}
+
+	if totp == nil {
+		return nil, status.Error(codes.FailedPrecondition, "two-factor authentication is not enabled")
+	}
+
+	// A pending enrollment can be dropped without a code.
+	if totp.Enabled {
+		ok, err := s.verifySecondFactor(ctx, totp, req.GetCode())
+		if err != nil {
+			return nil, err
+		}
+
+		if !ok {
+			return nil, status.Error(codes.InvalidArgument, "invalid code")
+		}
+	}
+
+	if err := s.store.DeleteUserTOTP(ctx, userId); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to disable totp: %v", err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (s *APIV1Service) VerifySecondFactor(ctx context.Context, req *apiv1.VerifySecondFactorRequest) (*apiv1.CreateSessionResponse, error) {
+	challengeHash := hashToken(req.GetChallenge())
+	challenge, err := s.store.GetSecondFactorChallenge(ctx, challengeHash)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get challenge: %v", err)
+	}
+
+	if challenge == nil || challenge.Attempts >= secondFactorChallengeMaxAttempts {
+		return nil, status.Error(codes.Unauthenticated, "challenge invalid or expired")
+	}
+
+	user, err := s.getUserByID(ctx, challenge.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Failed codes count against the same limits as failed passwords, so
+	// that codes cannot be guessed by requesting new challenges.
+	throttleKeys := s.signInThrottleKeys(ctx, user.Username)
+	if err := s.checkSignInThrottle(ctx, throttleKeys); err != nil {
+		return nil, err
+	}
+
+	if user.Disabled {
+		return nil, status.Error(codes.PermissionDenied, "user is disabled")
+	}
+
+	totp, err := s.store.GetUserTOTP(ctx, user.ID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get totp: %v", err)
+	}
+
+	ok := false
+	if totp != nil && totp.Enabled {
+		if ok, err = s.verifySecondFactor(ctx, totp, req.GetCode()); err != nil {
+			return nil, err
+		}
+	}
+
+	if !ok {
+		if err := s.store.IncrementSecondFactorChallengeAttempts(ctx, challengeHash); err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to record attempt: %v", err)
+		}
+		if err := s.recordSignInFailure(ctx, throttleKeys); err != nil {
+			return nil, err
+		}
+		return nil, status.Error(codes.Unauthenticated, "invalid code")
+	}
+
+	// Consuming the challenge only now lets the user retry a mistyped code,
+	// while a challenge can still be exchanged for one session only.
+	challenge, err = s.store.ConsumeSecondFactorChallenge(ctx, challengeHash)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to consume challenge: %v", err)
+	}
+
+	if challenge == nil {
+		return nil, status.Error(codes.Unauthenticated, "challenge invalid or expired")
+	}
+
+	if err := s.resetUserSignInThrottle(ctx, user.Username); err != nil {
+		return nil, err
+	}
+
+	session, err := s.doSignIn(ctx, user.ID, challenge.RememberMe)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to sign in, error: %v", err)
+	}
+
+	return &apiv1.CreateSessionResponse{
+		User:           convertUserFromStore(user),
+		LastAccessedAt: timestamppb.New(session.LastAccessedTime),
+		ExpiresAt:      timestamppb.New(session.ExpiresAt),
+	}, nil
+}
+
+// createSecondFactorChallenge starts the second step of a sign-in for a user
+// with two-factor authentication.
+func (s *APIV1Service) createSecondFactorChallenge(ctx context.Context, userId int64, rememberMe bool) (*apiv1.CreateSessionResponse, error) {
+	token, err := generateToken()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to generate challenge: %v", err)
+	}
+
+	now := time.Now()
+	challenge, err := s.store.CreateSecondFactorChallenge(ctx, &store.SecondFactorChallenge{
+		TokenHash:   hashToken(token),
+		UserID:      userId,
+		RememberMe:  rememberMe,
+		CreatedTime: now,
+		ExpiresTime: now.Add(secondFactorChallengeTTL),
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to create challenge: %v", err)
+	}
+
+	return &apiv1.CreateSessionResponse{
+		SecondFactorRequired: true,
+		Challenge:            token,
+		ChallengeExpiresAt:   timestamppb.New(challenge.ExpiresTime),
+	}, nil
+}
+
+// verifySecondFactor accepts either a current TOTP code or an unused
+// recovery code, which is used up.
+func (s *APIV1Service) verifySecondFactor(ctx context.Context, totp *store.UserTOTP, code string) (bool, error) {
+	code = strings.TrimSpace(code)
+	if len(code) == totpDigits {
+		return s.verifyTOTPCode(ctx, totp, code)
+	}
+
+	ok, err := s.store.ConsumeRecoveryCode(ctx, totp.UserID, hashToken(strings.ToLower(code)))
+	if err != nil {
+		return false, status.Errorf(codes.Internal, "failed to consume recovery code: %v", err)
+	}
+
+	return ok, nil
+}
+
+// verifyTOTPCode checks a code against the user's secret and records its
+// time step so that it cannot be replayed.
+func (s *APIV1Service) verifyTOTPCode(ctx context.Context, totp *store.UserTOTP, code string) (bool, error) {
+	step, ok := matchTOTPCode(totp.Secret, strings.TrimSpace(code), time.Now())
+	if !ok || step <= totp.LastUsedStep {
+		return false, nil
+	}
+
+	advanced, err := s.store.AdvanceUserTOTPStep(ctx, totp.UserID, step)
+	if err != nil {
+		return false, status.Errorf(codes.Internal, "failed to update totp: %v", err)
+	}
+
+	return advanced, nil
+}
+
+// matchTOTPCode returns the time step that code is valid for, looking
+// totpSkew steps around now.
+func matchTOTPCode(secret, code string, now time.Time) (int64, bool) {
+	key, err := totpEncoding.DecodeString(secret)
+	if err != nil || len(code) != totpDigits {
+		return 0, false
+	}
+
+	current := now.Unix() / int64(totpPeriod.Seconds())
+	for step := current - totpSkew; step <= current+totpSkew; step++ {
+		if subtle.ConstantTimeCompare([]byte(generateTOTPCode(key, step)), []byte(code)) == 1 {
+			return step, true
+		}
+	}
+
+	return 0, false
+}
+
+// generateTOTPCode computes the RFC 6238 code of a time step with
+// HMAC-SHA1.
+func generateTOTPCode(key []byte, step int64) string {
+	var counter [8]byte
+	binary.BigEndian.PutUint64(counter[:], uint64(step))
+
+	mac := hmac.New(sha1.New, key)
+	mac.Write(counter[:])
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	return fmt.Sprintf("%0*d", totpDigits, value%1000000)
+}
+
+func totpURI(secret, username string) string {
+	values := url.Values{}
+	values.Set("secret", secret)
+	values.Set("issuer", totpIssuer)
+	values.Set("algorithm", "SHA1")
+	values.Set("digits", fmt.Sprint(totpDigits))
+	values.Set("period", fmt.Sprint(int(totpPeriod.Seconds())))
+
+	label := url.PathEscape(totpIssuer + ":" + username)
+	return "otpauth://totp/" + label + "?" + values.Encode()
+}
+
+// generateRecoveryCode returns a code like "k3jd9-x8f2m".
+func generateRecoveryCode() (string, error) {
+	b := make([]byte, 7)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	code := strings.ToLower(totpEncoding.EncodeToString(b))[:10]
+	return code[:5] + "-" + code[5:], nil
+}
@@ -0,0 +1,180 @@
+package v1
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/thetnaingtn/dirty-hand/internal/config"
+	apiv1 "github.com/thetnaingtn/dirty-hand/proto/gen/api/v1"
+	"github.com/thetnaingtn/dirty-hand/store"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestGenerateTOTPCode(t *testing.T) {
+	// The SHA-1 test vectors of RFC 6238, cut to six digits.
+	key := []byte("12345678901234567890")
+	tests := []struct {
+		time int64
+		want string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+	}
+
+	for _, test := range tests {
+		if got := generateTOTPCode(key, test.time/30); got != test.want {
+			t.Errorf("generateTOTPCode at %d = %s, want %s", test.time, got, test.want)
+		}
+	}
+}
+
+func TestMatchTOTPCode(t *testing.T) {
+	key := []byte("12345678901234567890")
+	secret := totpEncoding.EncodeToString(key)
+	now := time.Unix(1111111109, 0)
+	current := now.Unix() / int64(totpPeriod.Seconds())
+
+	tests := []struct {
+		name   string
+		secret string
+		code   string
+		want   int64 // 0 when the code must not match
+	}{
+		{"current step", secret, generateTOTPCode(key, current), current},
+		{"previous step", secret, generateTOTPCode(key, current-1), current - 1},
+		{"next step", secret, generateTOTPCode(key, current+1), current + 1},
+		{"beyond the skew before", secret, generateTOTPCode(key, current-2), 0},
+		{"beyond the skew after", secret, generateTOTPCode(key, current+2), 0},
+		{"too short", secret, generateTOTPCode(key, current)[1:], 0},
+		{"invalid secret", "not base32!", generateTOTPCode(key, current), 0},
+	}
+
+	for _, test := range tests {
+		step, ok := matchTOTPCode(test.secret, test.code, now)
+		if ok != (test.want != 0) || step != test.want {
+			t.Errorf("matchTOTPCode with %s = %d, %t; want step %d", test.name, step, ok, test.want)
+		}
+	}
+}
+
+func TestVerifyTOTPCodeReplay(t *testing.T) {
+	s := newTestService(t, nil)
+	ctx := context.Background()
+	user := createTestUser(t, s, "alice", store.RoleProductView)
+	key := enableTestTOTP(t, s, user.ID)
+	current := currentTOTPStep()
+
+	verify := func(step int64) bool {
+		t.Helper()
+
+		totp, err := s.store.GetUserTOTP(ctx, user.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ok, err := s.verifyTOTPCode(ctx, totp, generateTOTPCode(key, step))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return ok
+	}
+
+	if !verify(current) {
+		t.Fatal("the current code was rejected")
+	}
+	if verify(current) {
+		t.Error("the current code was accepted twice")
+	}
+	// Codes of earlier steps are still within the skew, but they are older
+	// than the one used.
+	if verify(current - 1) {
+		t.Error("the previous code was accepted after the current one")
+	}
+	if !verify(current + 1) {
+		t.Error("the next code was rejected")
+	}
+}
+
+func TestVerifySecondFactorRecoveryCode(t *testing.T) {
+	s := newTestService(t, nil)
+	ctx := context.Background()
+	user := createTestUser(t, s, "alice", store.RoleProductView)
+	enableTestTOTP(t, s, user.ID, "abcd-efgh-ijkl")
+
+	verify := func(code string) bool {
+		t.Helper()
+
+		totp, err := s.store.GetUserTOTP(ctx, user.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ok, err := s.verifySecondFactor(ctx, totp, code)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return ok
+	}
+
+	if verify("abcd-efgh-ijkm") {
+		t.Error("an unknown recovery code was accepted")
+	}
+	if !verify(" ABCD-EFGH-IJKL ") {
+		t.Fatal("the recovery code was rejected")
+	}
+	if verify("abcd-efgh-ijkl") {
+		t.Error("the recovery code was accepted twice")
+	}
+}
+
+func TestVerifySecondFactorAttempts(t *testing.T) {
+	s := newTestService(t, func(cfg *config.Config) {
+		// Only the attempts of the challenge are limited here.
+		cfg.SignIn.MaxUserFailures = 0
+		cfg.SignIn.MaxIPFailures = 0
+	})
+	user := createTestUser(t, s, "alice", store.RoleProductView)
+	key := enableTestTOTP(t, s, user.ID)
+	current := currentTOTPStep()
+
+	ctx, _ := withCall(context.Background())
+	challenge, err := s.createSecondFactorChallenge(ctx, user.ID, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < secondFactorChallengeMaxAttempts; i++ {
+		_, err := s.VerifySecondFactor(ctx, &apiv1.VerifySecondFactorRequest{Challenge: challenge.Challenge, Code: generateTOTPCode(key, current+5)})
+		if status.Code(err) != codes.Unauthenticated {
+			t.Fatalf("VerifySecondFactor with a wrong code = %v, want Unauthenticated", err)
+		}
+	}
+
+	// The right code no longer helps once the attempts are used up.
+	ctx, stream := withCall(context.Background())
+	if _, err := s.VerifySecondFactor(ctx, &apiv1.VerifySecondFactorRequest{Challenge: challenge.Challenge, Code: generateTOTPCode(key, current)}); status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("VerifySecondFactor after %d attempts = %v, want Unauthenticated", secondFactorChallengeMaxAttempts, err)
+	}
+	if cookies := stream.header.Get("Set-Cookie"); len(cookies) != 0 {
+		t.Errorf("VerifySecondFactor after %d attempts set cookies %v", secondFactorChallengeMaxAttempts, cookies)
+	}
+
+	// A fresh challenge can still be completed, once.
+	challenge, err = s.createSecondFactorChallenge(ctx, user.ID, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req := &apiv1.VerifySecondFactorRequest{Challenge: challenge.Challenge, Code: generateTOTPCode(key, current)}
+	if _, err := s.VerifySecondFactor(ctx, req); err != nil {
+		t.Fatalf("VerifySecondFactor = %v", err)
+	}
+	if cookies := stream.header.Get("Set-Cookie"); len(cookies) != 1 || !strings.HasPrefix(cookies[0], "user_session=") {
+		t.Errorf("VerifySecondFactor set cookies %v, want a session cookie", cookies)
+	}
+	if _, err := s.VerifySecondFactor(ctx, req); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("VerifySecondFactor with a used challenge = %v, want Unauthenticated", err)
+	}
+}
@@ -2,15 +2,23 @@ package v1
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"errors"
 	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	apiv1 "github.com/thetnaingtn/dirty-hand/proto/gen/api/v1"
 	"github.com/thetnaingtn/dirty-hand/store"
-	"golang.org/x/crypto/bcrypt"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
@@ -20,42 +28,95 @@ import (
 )
 
 func (s *APIV1Service) CreateUser(ctx context.Context, req *apiv1.CreateUserRequest) (*apiv1.User, error) {
-	adminRoleType := store.RoleAdmin
-	existingUsers, err := s.store.ListUsers(ctx, &store.FindUser{Role: &adminRoleType})
-	if err != nil {
-		return nil, status.Errorf(codes.Internal, "failed to check existing admin users: %v", err)
+	// Turn away sign-ups the registration mode does not allow before
+	// hashing the password.
+	if _, _, err := s.signUpRole(ctx, req.GetInvitationCode()); err != nil {
+		return nil, err
 	}
 
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.GetPassword()), bcrypt.DefaultCost)
+	if err := s.validatePassword(req.GetUsername(), req.GetPassword(), "password"); err != nil {
+		return nil, err
+	}
+
+	hashedPassword, err := s.passwordHasher.Hash(req.GetPassword())
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to hash password: %v", err)
 	}
 
-	roleToAssign := store.RoleAdmin
-	if len(existingUsers) > 0 {
-		roleToAssign = store.RoleProductView
-	} else {
-		roleToAssign = store.RoleAdmin
-	}
+	// The role is decided again in the transaction that creates the user,
+	// so that only one of concurrent first sign-ups becomes the admin and
+	// an invitation is only used once.
+	var user *store.User
+	err = s.store.WithTx(ctx, func(ctx context.Context) error {
+		role, invitation, err := s.signUpRole(ctx, req.GetInvitationCode())
+		if err != nil {
+			return err
+		}
+
+		user, err = s.store.CreateUser(ctx, &store.User{
+			Username:     req.GetUsername(),
+			PasswordHash: hashedPassword,
+			Role:         role,
+		})
+		if err != nil {
+			return status.Errorf(codes.Internal, "failed to create user: %v", err)
+		}
+
+		if invitation != nil {
+			consumed, err := s.store.ConsumeInvitation(ctx, invitation.CodeHash, user.ID)
+			if err != nil {
+				return status.Errorf(codes.Internal, "failed to use invitation: %v", err)
+			}
+			if consumed == nil {
+				return errInvalidInvitation
+			}
+		}
 
-	user, err := s.store.CreateUser(ctx, &store.User{
-		Username:     req.GetUsername(),
-		PasswordHash: string(hashedPassword),
-		Role:         roleToAssign,
+		if err := s.joinDefaultOrganization(ctx, user); err != nil {
+			return status.Errorf(codes.Internal, "failed to join the default organization: %v", err)
+		}
+
+		return nil
 	})
+	if err != nil {
+		return nil, err
+	}
+
+	return convertUserFromStore(user), nil
+}
 
+// signUpRole returns the role of a user signing up and the invitation they
+// sign up with, if any. The first user becomes the admin, whatever the
+// registration mode.
+func (s *APIV1Service) signUpRole(ctx context.Context, invitationCode string) (store.Role, *store.Invitation, error) {
+	adminRoleType := store.RoleAdmin
+	existingUsers, err := s.store.ListUsers(ctx, &store.FindUser{Role: &adminRoleType})
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "failed to create user: %v", err)
+		return "", nil, status.Errorf(codes.Internal, "failed to check existing admin users: %v", err)
 	}
 
-	return &apiv1.User{
-		Id:       user.ID,
-		Username: user.Username,
-		Role:     convertUserRoleFromStore(user.Role),
-	}, nil
+	if len(existingUsers) == 0 {
+		return store.RoleAdmin, nil, nil
+	}
+
+	invitation, err := s.findSignUpInvitation(ctx, invitationCode)
+	if err != nil {
+		return "", nil, err
+	}
+
+	if invitation != nil {
+		return invitation.Role, invitation, nil
+	}
+
+	return store.RoleProductView, nil, nil
 }
 
 func (s *APIV1Service) CreateSession(ctx context.Context, req *apiv1.CreateSessionRequest) (*apiv1.CreateSessionResponse, error) {
+	throttleKeys := s.signInThrottleKeys(ctx, req.GetUsername())
+	if err := s.checkSignInThrottle(ctx, throttleKeys); err != nil {
+		return nil, err
+	}
+
 	user, err := s.store.GetUser(ctx, &store.FindUser{
 		Username: &req.Username,
 	})
@@ -64,50 +125,631 @@ func (s *APIV1Service) CreateSession(ctx context.Context, req *apiv1.CreateSessi
 		return nil, status.Errorf(codes.Internal, "failed to get user: %v", err)
 	}
 
-	if user == nil {
-		return nil, status.Errorf(codes.NotFound, "user not found")
+	passwordHash := s.dummyPasswordHash
+	if user != nil && user.PasswordHash != "" {
+		passwordHash = user.PasswordHash
 	}
 
-	err = bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(req.GetPassword()))
+	matched, err := s.passwordHasher.Verify(passwordHash, req.GetPassword())
 	if err != nil {
-		return nil, status.Error(codes.Unauthenticated, "unmatched username and password")
+		return nil, status.Errorf(codes.Internal, "failed to verify password: %v", err)
 	}
 
-	lastAccessedAt := time.Now()
-	expireTime := time.Now().Add(100 * 365 * 24 * time.Hour)
+	if user == nil || user.PasswordHash == "" || !matched {
+		if err := s.recordSignInFailure(ctx, throttleKeys); err != nil {
+			return nil, err
+		}
+		return nil, errInvalidCredentials
+	}
 
-	if err := s.doSignIn(ctx, user.ID, lastAccessedAt, expireTime); err != nil {
+	if user.Disabled {
+		return nil, status.Error(codes.PermissionDenied, "user is disabled")
+	}
+
+	totp, err := s.store.GetUserTOTP(ctx, user.ID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get totp: %v", err)
+	}
+
+	if totp != nil && totp.Enabled {
+		return s.createSecondFactorChallenge(ctx, user.ID, req.GetRememberMe())
+	}
+
+	if err := s.resetUserSignInThrottle(ctx, user.Username); err != nil {
+		return nil, err
+	}
+
+	s.rehashPassword(ctx, user, req.GetPassword())
+
+	session, err := s.doSignIn(ctx, user.ID, req.GetRememberMe())
+	if err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to sign in, error: %v", err)
 	}
 
 	return &apiv1.CreateSessionResponse{
 		User:           convertUserFromStore(user),
-		LastAccessedAt: timestamppb.New(lastAccessedAt),
+		LastAccessedAt: timestamppb.New(session.LastAccessedTime),
+		ExpiresAt:      timestamppb.New(session.ExpiresAt),
 	}, nil
 }
 
-func (s *APIV1Service) doSignIn(ctx context.Context, userId int64, lastAccessedAt, expireTime time.Time) error {
+func (s *APIV1Service) ListUsers(ctx context.Context, req *apiv1.ListUsersRequest) (*apiv1.ListUsersResponse, error) {
+	pageSize := int(req.GetPageSize())
+	if pageSize <= 0 {
+		pageSize = defaultUserPageSize
+	} else if pageSize > maxUserPageSize {
+		pageSize = maxUserPageSize
+	}
+
+	// Fetch one extra row to find out whether there is a next page.
+	limit := pageSize + 1
+	find := &store.FindUser{Limit: &limit}
+
+	if req.GetPageToken() != "" {
+		afterId, err := decodeUserPageToken(req.GetPageToken())
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid page token: %v", err)
+		}
+		find.AfterID = &afterId
+	}
+
+	users, err := s.store.ListUsers(ctx, find)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list users: %v", err)
+	}
+
+	resp := &apiv1.ListUsersResponse{}
+	if len(users) > pageSize {
+		users = users[:pageSize]
+		resp.NextPageToken = encodeUserPageToken(users[len(users)-1].ID)
+	}
+
+	resp.Users = make([]*apiv1.User, 0, len(users))
+	for i := range users {
+		resp.Users = append(resp.Users, convertUserFromStore(&users[i]))
+	}
+
+	return resp, nil
+}
+
+func (s *APIV1Service) GetUser(ctx context.Context, req *apiv1.GetUserRequest) (*apiv1.User, error) {
+	user, err := s.getUserByID(ctx, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	return convertUserFromStore(user), nil
+}
+
+func (s *APIV1Service) UpdateUserRole(ctx context.Context, req *apiv1.UpdateUserRoleRequest) (*apiv1.User, error) {
+	role, ok := convertUserRoleToStore(req.GetRole())
+	if !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid role %s", req.GetRole())
+	}
+
+	return s.updateUser(ctx, &store.UpdateUser{ID: req.GetId(), Role: &role})
+}
+
+func (s *APIV1Service) DisableUser(ctx context.Context, req *apiv1.DisableUserRequest) (*apiv1.User, error) {
+	disabled := true
+
+	return s.updateUser(ctx, &store.UpdateUser{ID: req.GetId(), Disabled: &disabled})
+}
+
+func (s *APIV1Service) UnlockUser(ctx context.Context, req *apiv1.UnlockUserRequest) (*apiv1.User, error) {
+	user, err := s.getUserByID(ctx, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.resetUserSignInThrottle(ctx, user.Username); err != nil {
+		return nil, err
+	}
+
+	return convertUserFromStore(user), nil
+}
+
+func (s *APIV1Service) DeleteUser(ctx context.Context, req *apiv1.DeleteUserRequest) (*emptypb.Empty, error) {
+	if _, err := s.getUserByID(ctx, req.GetId()); err != nil {
+		return nil, err
+	}
+
+	if err := s.store.DeleteUser(ctx, req.GetId()); err != nil {
+		if errors.Is(err, store.ErrLastAdmin) {
+			return nil, status.Error(codes.FailedPrecondition, err.Error())
+		}
+		return nil, status.Errorf(codes.Internal, "failed to delete user: %v", err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (s *APIV1Service) ChangePassword(ctx context.Context, req *apiv1.ChangePasswordRequest) (*emptypb.Empty, error) {
+	userId, ok := ctx.Value(userIdContextKey).(int64)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "authentication required")
+	}
+	currentSessionId, _ := ctx.Value(sessionIdContextKey).(string)
+
+	user, err := s.getUserByID(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	matched, err := s.passwordHasher.Verify(user.PasswordHash, req.GetCurrentPassword())
+	if user.PasswordHash == "" || err != nil || !matched {
+		return nil, status.Error(codes.PermissionDenied, "current password is incorrect")
+	}
+
+	if err := s.setPassword(ctx, user, req.GetNewPassword(), "new_password"); err != nil {
+		return nil, err
+	}
+
+	if err := s.store.DeleteOtherSessions(ctx, userId, currentSessionId); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to revoke other sessions: %v", err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (s *APIV1Service) CreatePasswordResetToken(ctx context.Context, req *apiv1.CreatePasswordResetTokenRequest) (*apiv1.PasswordResetToken, error) {
+	if _, err := s.getUserByID(ctx, req.GetUserId()); err != nil {
+		return nil, err
+	}
+
+	token, err := generateToken()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to generate token: %v", err)
+	}
+
+	now := time.Now()
+	created, err := s.store.CreatePasswordResetToken(ctx, &store.PasswordResetToken{
+		TokenHash:   hashToken(token),
+		UserID:      req.GetUserId(),
+		CreatedTime: now,
+		ExpiresTime: now.Add(passwordResetTokenTTL),
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to create password reset token: %v", err)
+	}
+
+	return &apiv1.PasswordResetToken{
+		Token:     token,
+		ExpiresAt: timestamppb.New(created.ExpiresTime),
+	}, nil
+}
+
+func (s *APIV1Service) ResetPassword(ctx context.Context, req *apiv1.ResetPasswordRequest) (*emptypb.Empty, error) {
+	if req.GetToken() == "" {
+		return nil, status.Error(codes.InvalidArgument, "token is required")
+	}
+
+	tokenHash := hashToken(req.GetToken())
+	token, err := s.store.GetPasswordResetToken(ctx, tokenHash)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get password reset token: %v", err)
+	}
+
+	if token == nil {
+		return nil, status.Error(codes.PermissionDenied, "invalid or expired password reset token")
+	}
+
+	user, err := s.getUserByID(ctx, token.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check the password before using up the token, so that a rejected
+	// password can be corrected with the same token.
+	if err := s.validatePassword(user.Username, req.GetNewPassword(), "new_password"); err != nil {
+		return nil, err
+	}
+
+	token, err = s.store.ConsumePasswordResetToken(ctx, tokenHash)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to consume password reset token: %v", err)
+	}
+
+	if token == nil {
+		return nil, status.Error(codes.PermissionDenied, "invalid or expired password reset token")
+	}
+
+	if err := s.setPassword(ctx, user, req.GetNewPassword(), "new_password"); err != nil {
+		return nil, err
+	}
+
+	if err := s.store.DeleteOtherSessions(ctx, token.UserID, ""); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to revoke sessions: %v", err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+// setPassword checks a new password against the policy and stores its hash.
+// field names the request field holding the password in error details.
+func (s *APIV1Service) setPassword(ctx context.Context, user *store.User, password, field string) error {
+	if err := s.validatePassword(user.Username, password, field); err != nil {
+		return err
+	}
+
+	passwordHash, err := s.passwordHasher.Hash(password)
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to hash password: %v", err)
+	}
+
+	if _, err := s.store.UpdateUser(ctx, &store.UpdateUser{ID: user.ID, PasswordHash: &passwordHash}); err != nil {
+		return status.Errorf(codes.Internal, "failed to update password: %v", err)
+	}
+
+	return nil
+}
+
+// validatePassword returns an InvalidArgument error with a field violation
+// for every policy rule the password breaks.
+func (s *APIV1Service) validatePassword(username, password, field string) error {
+	violations := s.passwordPolicy.Validate(username, password)
+	if len(violations) == 0 {
+		return nil
+	}
+
+	badRequest := &errdetails.BadRequest{}
+	for _, violation := range violations {
+		badRequest.FieldViolations = append(badRequest.FieldViolations, &errdetails.BadRequest_FieldViolation{
+			Field:       field,
+			Description: "password " + violation.Description,
+			Reason:      violation.Reason,
+		})
+	}
+
+	st, err := status.New(codes.InvalidArgument, "password does not meet the password policy").WithDetails(badRequest)
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to build error details: %v", err)
+	}
+
+	return st.Err()
+}
+
+// rehashPassword replaces the hash of a user who just signed in when it was
+// made with another algorithm or other parameters than configured. Failing
+// to do so does not fail the sign-in.
+func (s *APIV1Service) rehashPassword(ctx context.Context, user *store.User, password string) {
+	if !s.passwordHasher.NeedsRehash(user.PasswordHash) {
+		return
+	}
+
+	passwordHash, err := s.passwordHasher.Hash(password)
+	if err != nil {
+		slog.Warn("failed to rehash password", "user_id", user.ID, "error", err)
+		return
+	}
+
+	if _, err := s.store.UpdateUser(ctx, &store.UpdateUser{ID: user.ID, PasswordHash: &passwordHash}); err != nil {
+		slog.Warn("failed to store rehashed password", "user_id", user.ID, "error", err)
+	}
+}
+
+func (s *APIV1Service) CreateAccessToken(ctx context.Context, req *apiv1.CreateAccessTokenRequest) (*apiv1.AccessToken, error) {
+	userId, ok := ctx.Value(userIdContextKey).(int64)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "authentication required")
+	}
+
+	if strings.TrimSpace(req.GetName()) == "" {
+		return nil, status.Error(codes.InvalidArgument, "name is required")
+	}
+
+	user, err := s.getUserByID(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	// A token may not grant more than the caller holds, which for a caller
+	// authenticated by another token is limited to that token's scopes.
+	granted := []store.Role{user.Role}
+	if current, ok := ctx.Value(accessTokenContextKey).(*store.AccessToken); ok {
+		granted = current.Scopes
+	}
+
+	scopes := []store.Role{}
+	for _, scope := range req.GetScopes() {
+		role, ok := convertUserRoleToStore(scope)
+		if !ok {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid scope %s", scope)
+		}
+		if !roleSatisfies(user.Role, role) || !slices.ContainsFunc(granted, func(g store.Role) bool { return roleSatisfies(g, role) }) {
+			return nil, status.Errorf(codes.PermissionDenied, "scope %s exceeds the caller's permissions", scope)
+		}
+		scopes = append(scopes, role)
+	}
+	if len(scopes) == 0 {
+		scopes = granted
+	}
+
+	var expiresAt *time.Time
+	if req.GetExpiresAt() != nil {
+		t := req.GetExpiresAt().AsTime()
+		if !t.After(time.Now()) {
+			return nil, status.Error(codes.InvalidArgument, "expires_at must be in the future")
+		}
+		expiresAt = &t
+	}
+
+	random, err := generateToken()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to generate token: %v", err)
+	}
+	token := accessTokenPrefix + random
+
+	accessToken, err := s.store.CreateAccessToken(ctx, &store.AccessToken{
+		UserID:      userId,
+		Name:        req.GetName(),
+		TokenHash:   hashToken(token),
+		Scopes:      scopes,
+		CreatedTime: time.Now(),
+		ExpiresAt:   expiresAt,
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to create access token: %v", err)
+	}
+
+	resp := convertAccessTokenFromStore(accessToken)
+	resp.Token = token
+
+	return resp, nil
+}
+
+func (s *APIV1Service) ListAccessTokens(ctx context.Context, req *apiv1.ListAccessTokensRequest) (*apiv1.ListAccessTokensResponse, error) {
+	userId, ok := ctx.Value(userIdContextKey).(int64)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "authentication required")
+	}
+
+	accessTokens, err := s.store.ListAccessTokens(ctx, &store.FindAccessToken{UserID: &userId})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list access tokens: %v", err)
+	}
+
+	resp := &apiv1.ListAccessTokensResponse{AccessTokens: make([]*apiv1.AccessToken, 0, len(accessTokens))}
+	for _, accessToken := range accessTokens {
+		resp.AccessTokens = append(resp.AccessTokens, convertAccessTokenFromStore(accessToken))
+	}
+
+	return resp, nil
+}
+
+func (s *APIV1Service) RevokeAccessToken(ctx context.Context, req *apiv1.RevokeAccessTokenRequest) (*emptypb.Empty, error) {
+	userId, ok := ctx.Value(userIdContextKey).(int64)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "authentication required")
+	}
+
+	id := req.GetId()
+	accessToken, err := s.store.GetAccessToken(ctx, &store.FindAccessToken{ID: &id, UserID: &userId})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get access token: %v", err)
+	}
+
+	if accessToken == nil {
+		return nil, status.Errorf(codes.NotFound, "access token %d not found", id)
+	}
+
+	if err := s.store.DeleteAccessToken(ctx, id); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to revoke access token: %v", err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (s *APIV1Service) getUserByID(ctx context.Context, id int64) (*store.User, error) {
+	user, err := s.store.GetUser(ctx, &store.FindUser{ID: &id})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get user: %v", err)
+	}
+
+	if user == nil {
+		return nil, status.Errorf(codes.NotFound, "user %d not found", id)
+	}
+
+	return user, nil
+}
+
+func (s *APIV1Service) updateUser(ctx context.Context, update *store.UpdateUser) (*apiv1.User, error) {
+	if _, err := s.getUserByID(ctx, update.ID); err != nil {
+		return nil, err
+	}
+
+	user, err := s.store.UpdateUser(ctx, update)
+	if err != nil {
+		if errors.Is(err, store.ErrLastAdmin) {
+			return nil, status.Error(codes.FailedPrecondition, err.Error())
+		}
+		return nil, status.Errorf(codes.Internal, "failed to update user: %v", err)
+	}
+
+	if user == nil {
+		return nil, status.Errorf(codes.NotFound, "user %d not found", update.ID)
+	}
+
+	return convertUserFromStore(user), nil
+}
+
+// doSignIn creates a session for the user and sends its cookie. A remembered
+// session gets a persistent cookie that expires together with the session,
+// otherwise the cookie lasts until the browser is closed.
+func (s *APIV1Service) doSignIn(ctx context.Context, userId int64, rememberMe bool) (*store.Session, error) {
 	sessionId := uuid.New()
-	sessionCookieValue := fmt.Sprintf("%d-%s", userId, sessionId)
+	sessionCookieValue, err := s.cookieCodec.encode(userId, sessionId.String())
+	if err != nil {
+		return nil, status.Error(codes.Internal, "failed to encode session cookie")
+	}
+
+	userAgent, clientIP := getUserAgent(ctx), s.trustedProxies.clientIP(ctx)
+
+	now := time.Now()
+	session, err := s.store.CreateSession(ctx, &store.Session{
+		UserID:           userId,
+		SessionID:        sessionId.String(),
+		CreatedTime:      now,
+		LastAccessedTime: now,
+		ExpiresAt:        now.Add(s.config.Session.AbsoluteLifetime),
+		UserAgent:        userAgent,
+		ClientIP:         clientIP,
+	})
+
+	if err != nil {
+		return nil, status.Error(codes.Internal, "failed to create user session")
+	}
+
+	setAuditActor(ctx, userId)
+
+	var cookieExpireTime time.Time
+	if rememberMe {
+		cookieExpireTime = session.ExpiresAt
+	}
+
+	if err := setSessionCookie(ctx, sessionCookieValue, cookieExpireTime); err != nil {
+		return nil, err
+	}
+
+	return session, nil
+}
+
+func (s *APIV1Service) DeleteSession(ctx context.Context, req *apiv1.DeleteSessionRequest) (*emptypb.Empty, error) {
+	userId, ok := ctx.Value(userIdContextKey).(int64)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "authentication required")
+	}
+
+	if sessionId, ok := ctx.Value(sessionIdContextKey).(string); ok {
+		if err := s.store.DeleteSession(ctx, userId, sessionId); err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to delete session: %v", err)
+		}
+	}
+
+	if err := clearSessionCookie(ctx); err != nil {
+		return nil, err
+	}
+
+	return &emptypb.Empty{}, nil
+}
 
+func (s *APIV1Service) ListSessions(ctx context.Context, req *apiv1.ListSessionsRequest) (*apiv1.ListSessionsResponse, error) {
+	userId, ok := ctx.Value(userIdContextKey).(int64)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "authentication required")
+	}
+	currentSessionId, _ := ctx.Value(sessionIdContextKey).(string)
+
+	sessions, err := s.store.GetUserSessions(ctx, userId)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get sessions: %v", err)
+	}
+
+	now := time.Now()
+	resp := &apiv1.ListSessionsResponse{Sessions: make([]*apiv1.Session, 0, len(sessions))}
+	for _, session := range sessions {
+		// Expired sessions stay stored until they are cleaned up, but they
+		// can no longer sign anyone in.
+		if isSessionExpired(session, s.config.Session.IdleTimeout, now) {
+			continue
+		}
+		resp.Sessions = append(resp.Sessions, convertSessionFromStore(session, currentSessionId))
+	}
+
+	return resp, nil
+}
+
+func (s *APIV1Service) RevokeSession(ctx context.Context, req *apiv1.RevokeSessionRequest) (*emptypb.Empty, error) {
+	userId, ok := ctx.Value(userIdContextKey).(int64)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "authentication required")
+	}
+	currentSessionId, _ := ctx.Value(sessionIdContextKey).(string)
+
+	if req.GetAllOthers() {
+		if err := s.store.DeleteOtherSessions(ctx, userId, currentSessionId); err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to revoke sessions: %v", err)
+		}
+		return &emptypb.Empty{}, nil
+	}
+
+	if req.GetId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "id or all_others is required")
+	}
+
+	sessions, err := s.store.GetUserSessions(ctx, userId)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get sessions: %v", err)
+	}
+
+	if !slices.ContainsFunc(sessions, func(session *store.Session) bool {
+		return session.SessionID == req.GetId()
+	}) {
+		return nil, status.Errorf(codes.NotFound, "session %s not found", req.GetId())
+	}
+
+	if err := s.store.DeleteSession(ctx, userId, req.GetId()); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to revoke session: %v", err)
+	}
+
+	if req.GetId() == currentSessionId {
+		if err := clearSessionCookie(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+// getUserAgent returns the user agent of the client that made the request.
+// Requests proxied by the gateway carry the original one in the
+// grpcgateway-user-agent metadata.
+func getUserAgent(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+
+	if v := md.Get("grpcgateway-user-agent"); len(v) > 0 {
+		return v[0]
+	}
+	if v := md.Get("user-agent"); len(v) > 0 {
+		return v[0]
+	}
+
+	return ""
+}
+
+// clearSessionCookie makes the client drop its session cookie.
+func clearSessionCookie(ctx context.Context) error {
+	return setSessionCookie(ctx, "", time.Unix(0, 0))
+}
+
+// setSessionCookie sends the user_session cookie back to the client through
+// the response header metadata. A zero expireTime makes it a browser session
+// cookie.
+func setSessionCookie(ctx context.Context, value string, expireTime time.Time) error {
 	attrs := []string{
-		fmt.Sprintf("%s=%s", "user_session", sessionCookieValue),
+		fmt.Sprintf("%s=%s", "user_session", value),
 		"Path=/",
 		"HttpOnly",
 	}
 
-	if expireTime.IsZero() {
-		attrs = append(attrs, "Expires=Thu, 01 Jan 1970 00:00:00 GMT")
-	} else {
-		attrs = append(attrs, "Expires="+expireTime.Format(time.RFC1123))
+	if !expireTime.IsZero() {
+		attrs = append(attrs, "Expires="+expireTime.UTC().Format(http.TimeFormat))
+		if !expireTime.After(time.Now()) {
+			attrs = append(attrs, "Max-Age=0")
+		}
 	}
 
 	md, ok := metadata.FromIncomingContext(ctx)
 	if !ok {
 		return errors.New("failed to get metadata from context")
 	}
+	// gRPC-Web requests carry the browser's Origin header as is while the
+	// gateway forwards it with its grpcgateway- prefix.
 	var origin string
-	for _, v := range md.Get("origin") {
+	for _, v := range append(md.Get("origin"), md.Get("grpcgateway-origin")...) {
 		origin = v
 	}
 	isHTTPS := strings.HasPrefix(origin, "https://")
@@ -118,16 +760,6 @@ func (s *APIV1Service) doSignIn(ctx context.Context, userId int64, lastAccessedA
 		attrs = append(attrs, "SameSite=Strict")
 	}
 
-	_, err := s.store.CreateSession(ctx, &store.Session{
-		UserID:           userId,
-		SessionID:        sessionId.String(),
-		LastAccessedTime: lastAccessedAt,
-	})
-
-	if err != nil {
-		return status.Error(codes.Internal, "failed to create user session")
-	}
-
 	if err := grpc.SetHeader(ctx, metadata.New(map[string]string{
 		"Set-Cookie": strings.Join(attrs, ";"),
 	})); err != nil {
@@ -137,10 +769,6 @@ func (s *APIV1Service) doSignIn(ctx context.Context, userId int64, lastAccessedA
 	return nil
 }
 
-func (s *APIV1Service) DeleteSession(ctx context.Context, req *apiv1.DeleteSessionRequest) (*emptypb.Empty, error) {
-	return &emptypb.Empty{}, nil
-}
-
 func convertUserRoleFromStore(role store.Role) apiv1.Role {
 	switch role {
 	case store.RoleAdmin:
@@ -154,10 +782,102 @@ func convertUserRoleFromStore(role store.Role) apiv1.Role {
 	}
 }
 
+func convertUserRoleToStore(role apiv1.Role) (store.Role, bool) {
+	switch role {
+	case apiv1.Role_ADMIN:
+		return store.RoleAdmin, true
+	case apiv1.Role_PRODUCT_EDITOR:
+		return store.RoleProductEdit, true
+	case apiv1.Role_PRODUCT_VIEWER:
+		return store.RoleProductView, true
+	default:
+		return "", false
+	}
+}
+
 func convertUserFromStore(user *store.User) *apiv1.User {
 	return &apiv1.User{
 		Id:       user.ID,
 		Username: user.Username,
 		Role:     convertUserRoleFromStore(user.Role),
+		Disabled: user.Disabled,
+	}
+}
+
+func convertAccessTokenFromStore(accessToken *store.AccessToken) *apiv1.AccessToken {
+	scopes := make([]apiv1.Role, 0, len(accessToken.Scopes))
+	for _, scope := range accessToken.Scopes {
+		scopes = append(scopes, convertUserRoleFromStore(scope))
+	}
+
+	resp := &apiv1.AccessToken{
+		Id:        accessToken.ID,
+		Name:      accessToken.Name,
+		Scopes:    scopes,
+		CreatedAt: timestamppb.New(accessToken.CreatedTime),
+	}
+	if accessToken.ExpiresAt != nil {
+		resp.ExpiresAt = timestamppb.New(*accessToken.ExpiresAt)
+	}
+	if accessToken.LastUsedTime != nil {
+		resp.LastUsedAt = timestamppb.New(*accessToken.LastUsedTime)
 	}
+
+	return resp
+}
+
+const (
+	defaultUserPageSize = 50
+	maxUserPageSize     = 1000
+)
+
+// encodeUserPageToken returns the cursor handed out as next_page_token for a
+// page ending with the given user.
+func encodeUserPageToken(lastId int64) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatInt(lastId, 10)))
+}
+
+func decodeUserPageToken(token string) (int64, error) {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return 0, err
+	}
+
+	return strconv.ParseInt(string(data), 10, 64)
+}
+
+func convertSessionFromStore(session *store.Session, currentSessionId string) *apiv1.Session {
+	return &apiv1.Session{
+		Id:             session.SessionID,
+		CreatedAt:      timestamppb.New(session.CreatedTime),
+		LastAccessedAt: timestamppb.New(session.LastAccessedTime),
+		UserAgent:      session.UserAgent,
+		ClientIp:       session.ClientIP,
+		Current:        session.SessionID == currentSessionId,
+		ExpiresAt:      timestamppb.New(session.ExpiresAt),
+	}
+}
+
+const passwordResetTokenTTL = time.Hour
+
+// accessTokenPrefix marks personal access tokens so they are easy to
+// recognise, e.g. by secret scanners.
+const accessTokenPrefix = "dh_pat_"
+
+// generateToken returns a random URL-safe token.
+func generateToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// hashToken returns the hex encoded SHA-256 of a token. Tokens are random
+// and long, so a fast unsalted hash is enough to keep them out of the
+// database in plain text.
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
 }
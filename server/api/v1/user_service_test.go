@@ -0,0 +1,547 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/thetnaingtn/dirty-hand/internal/config"
+	"github.com/thetnaingtn/dirty-hand/internal/password"
+	apiv1 "github.com/thetnaingtn/dirty-hand/proto/gen/api/v1"
+	"github.com/thetnaingtn/dirty-hand/store"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestDeleteSession(t *testing.T) {
+	s := newTestService(t, nil)
+	user := createTestUser(t, s, "alice", store.RoleProductView)
+
+	ctx, stream := withCall(context.Background())
+	if _, err := s.CreateSession(ctx, &apiv1.CreateSessionRequest{Username: "alice", Password: testPassword}); err != nil {
+		t.Fatal(err)
+	}
+	cookies := stream.header.Get("Set-Cookie")
+	if len(cookies) != 1 || !strings.HasPrefix(cookies[0], "user_session=") {
+		t.Fatalf("CreateSession set cookies %v, want a session cookie", cookies)
+	}
+	in, err := NewGRPCAuthInterceptor(&s.store, s.config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cookieValue := strings.TrimPrefix(strings.Split(cookies[0], ";")[0], "user_session=")
+	_, sessionId, err := in.authenticateBySession(context.Background(), cookieValue)
+	if err != nil {
+		t.Fatalf("authenticateBySession before DeleteSession = %v", err)
+	}
+
+	ctx, stream = withCall(context.WithValue(withUser(context.Background(), user.ID), sessionIdContextKey, sessionId))
+	if _, err := s.DeleteSession(ctx, &apiv1.DeleteSessionRequest{}); err != nil {
+		t.Fatalf("DeleteSession = %v", err)
+	}
+
+	cookies = stream.header.Get("Set-Cookie")
+	if len(cookies) != 1 || !strings.HasPrefix(cookies[0], "user_session=;") || !strings.Contains(cookies[0], "Max-Age=0") {
+		t.Errorf("DeleteSession set cookies %v, want an expired session cookie", cookies)
+	}
+	if _, _, err := in.authenticateBySession(context.Background(), cookieValue); err == nil {
+		t.Error("authenticateBySession after DeleteSession accepted the revoked session")
+	}
+}
+
+func TestListSessions(t *testing.T) {
+	s := newTestService(t, func(cfg *config.Config) {
+		cfg.Session.IdleTimeout = time.Hour
+	})
+	ctx := context.Background()
+	user := createTestUser(t, s, "alice", store.RoleProductView)
+
+	now := time.Now()
+	sessions := []*store.Session{
+		{SessionID: "current", LastAccessedTime: now, ExpiresAt: now.Add(time.Hour)},
+		{SessionID: "other", LastAccessedTime: now.Add(-time.Minute), ExpiresAt: now.Add(time.Hour)},
+		{SessionID: "idle", LastAccessedTime: now.Add(-2 * time.Hour), ExpiresAt: now.Add(time.Hour)},
+		{SessionID: "expired", LastAccessedTime: now, ExpiresAt: now.Add(-time.Minute)},
+	}
+	for _, session := range sessions {
+		session.UserID = user.ID
+		session.CreatedTime = now.Add(-3 * time.Hour)
+		if _, err := s.store.CreateSession(ctx, session); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ctx = context.WithValue(withUser(ctx, user.ID), sessionIdContextKey, "current")
+	resp, err := s.ListSessions(ctx, &apiv1.ListSessionsRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var ids []string
+	for _, session := range resp.Sessions {
+		ids = append(ids, session.Id)
+		if session.Current != (session.Id == "current") {
+			t.Errorf("session %s current = %t", session.Id, session.Current)
+		}
+	}
+	slices.Sort(ids)
+	if want := []string{"current", "other"}; !slices.Equal(ids, want) {
+		t.Errorf("ListSessions = %v, want %v", ids, want)
+	}
+}
+
+func TestIsSessionExpired(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name         string
+		lastAccessed time.Time
+		expiresAt    time.Time
+		idleTimeout  time.Duration
+		want         bool
+	}{
+		{"active session", now.Add(-time.Minute), now.Add(time.Hour), time.Hour, false},
+		{"idle session", now.Add(-2 * time.Hour), now.Add(time.Hour), time.Hour, true},
+		{"idle session without idle timeout", now.Add(-2 * time.Hour), now.Add(time.Hour), 0, false},
+		{"session past its lifetime", now.Add(-time.Minute), now.Add(-time.Second), time.Hour, true},
+		{"session past its lifetime without idle timeout", now.Add(-time.Minute), now.Add(-time.Second), 0, true},
+	}
+
+	for _, test := range tests {
+		session := &store.Session{LastAccessedTime: test.lastAccessed, ExpiresAt: test.expiresAt}
+		if got := isSessionExpired(session, test.idleTimeout, now); got != test.want {
+			t.Errorf("isSessionExpired of an %s = %t, want %t", test.name, got, test.want)
+		}
+	}
+}
+
+func TestCreateSessionExpiry(t *testing.T) {
+	s := newTestService(t, func(cfg *config.Config) {
+		cfg.Session.AbsoluteLifetime = 24 * time.Hour
+	})
+	createTestUser(t, s, "alice", store.RoleProductView)
+
+	tests := []struct {
+		rememberMe     bool
+		wantPersistent bool
+	}{
+		{false, false},
+		{true, true},
+	}
+
+	for _, test := range tests {
+		ctx, stream := withCall(context.Background())
+		before := time.Now()
+		resp, err := s.CreateSession(ctx, &apiv1.CreateSessionRequest{Username: "alice", Password: testPassword, RememberMe: test.rememberMe})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if expiresAt := resp.ExpiresAt.AsTime(); expiresAt.Before(before.Add(24*time.Hour).Truncate(time.Second)) || expiresAt.After(time.Now().Add(24*time.Hour)) {
+			t.Errorf("CreateSession with remember me %t expires at %s, want a day after sign-in", test.rememberMe, expiresAt)
+		}
+
+		cookies := stream.header.Get("Set-Cookie")
+		if len(cookies) != 1 {
+			t.Fatalf("CreateSession set cookies %v, want a session cookie", cookies)
+		}
+		if persistent := strings.Contains(cookies[0], "Expires="); persistent != test.wantPersistent {
+			t.Errorf("CreateSession with remember me %t set cookie %q, want persistent %t", test.rememberMe, cookies[0], test.wantPersistent)
+		}
+	}
+}
+
+func TestLastAdmin(t *testing.T) {
+	viewRole := store.RoleProductView
+	disabled := true
+
+	tests := []struct {
+		name   string
+		change func(s *APIV1Service, ctx context.Context, id int64) error
+		call   func(s *APIV1Service, ctx context.Context, id int64) error
+	}{
+		{
+			"demote",
+			func(s *APIV1Service, ctx context.Context, id int64) error {
+				_, err := s.store.UpdateUser(ctx, &store.UpdateUser{ID: id, Role: &viewRole})
+				return err
+			},
+			func(s *APIV1Service, ctx context.Context, id int64) error {
+				_, err := s.UpdateUserRole(ctx, &apiv1.UpdateUserRoleRequest{Id: id, Role: apiv1.Role_PRODUCT_VIEWER})
+				return err
+			},
+		},
+		{
+			"disable",
+			func(s *APIV1Service, ctx context.Context, id int64) error {
+				_, err := s.store.UpdateUser(ctx, &store.UpdateUser{ID: id, Disabled: &disabled})
+				return err
+			},
+			func(s *APIV1Service, ctx context.Context, id int64) error {
+				_, err := s.DisableUser(ctx, &apiv1.DisableUserRequest{Id: id})
+				return err
+			},
+		},
+		{
+			"delete",
+			func(s *APIV1Service, ctx context.Context, id int64) error {
+				return s.store.DeleteUser(ctx, id)
+			},
+			func(s *APIV1Service, ctx context.Context, id int64) error {
+				_, err := s.DeleteUser(ctx, &apiv1.DeleteUserRequest{Id: id})
+				return err
+			},
+		},
+	}
+
+	for _, test := range tests {
+		s := newTestService(t, nil)
+		ctx := context.Background()
+		admin := createTestUser(t, s, "admin", store.RoleAdmin)
+		other := createTestUser(t, s, "other", store.RoleAdmin)
+
+		// Another admin that is disabled does not count.
+		if _, err := s.store.UpdateUser(ctx, &store.UpdateUser{ID: other.ID, Disabled: &disabled}); err != nil {
+			t.Fatal(err)
+		}
+		if err := test.change(s, ctx, admin.ID); !errors.Is(err, store.ErrLastAdmin) {
+			t.Errorf("%s of the last admin in the store = %v, want ErrLastAdmin", test.name, err)
+		}
+		if err := test.call(s, ctx, admin.ID); status.Code(err) != codes.FailedPrecondition {
+			t.Errorf("%s of the last admin = %v, want FailedPrecondition", test.name, err)
+		}
+		if user, err := s.store.GetUser(ctx, &store.FindUser{ID: &admin.ID}); err != nil || user == nil || user.Role != store.RoleAdmin || user.Disabled {
+			t.Errorf("GetUser after the refused %s = %+v, %v; want an enabled admin", test.name, user, err)
+		}
+
+		// With a second enabled admin the change goes through.
+		createTestUser(t, s, "second", store.RoleAdmin)
+		if err := test.call(s, ctx, admin.ID); err != nil {
+			t.Errorf("%s of an admin with another admin = %v", test.name, err)
+		}
+	}
+}
+
+// createTestSessions stores sessions of the user with the given IDs.
+func createTestSessions(t *testing.T, s *APIV1Service, userId int64, sessionIds ...string) {
+	t.Helper()
+
+	now := time.Now()
+	for _, sessionId := range sessionIds {
+		if _, err := s.store.CreateSession(context.Background(), &store.Session{UserID: userId, SessionID: sessionId, CreatedTime: now, LastAccessedTime: now}); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// testSessionIds returns the sorted IDs of the user's stored sessions.
+func testSessionIds(t *testing.T, s *APIV1Service, userId int64) []string {
+	t.Helper()
+
+	sessions, err := s.store.GetUserSessions(context.Background(), userId)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var ids []string
+	for _, session := range sessions {
+		ids = append(ids, session.SessionID)
+	}
+	slices.Sort(ids)
+	return ids
+}
+
+func TestChangePassword(t *testing.T) {
+	s := newTestService(t, nil)
+	user := createTestUser(t, s, "alice", store.RoleProductView)
+	createTestSessions(t, s, user.ID, "current", "other")
+	ctx := context.WithValue(withUser(context.Background(), user.ID), sessionIdContextKey, "current")
+
+	const newPassword = "clementine-otter-4"
+	tests := []struct {
+		name        string
+		current     string
+		newPassword string
+		want        codes.Code
+	}{
+		{"wrong current password", "wrong-password-1", newPassword, codes.PermissionDenied},
+		{"empty new password", testPassword, "", codes.InvalidArgument},
+		{"current password", testPassword, newPassword, codes.OK},
+		// The old password no longer works.
+		{"old password", testPassword, newPassword, codes.PermissionDenied},
+	}
+
+	for _, test := range tests {
+		req := &apiv1.ChangePasswordRequest{CurrentPassword: test.current, NewPassword: test.newPassword}
+		if _, err := s.ChangePassword(ctx, req); status.Code(err) != test.want {
+			t.Errorf("ChangePassword with %s = %v, want %s", test.name, err, test.want)
+		}
+	}
+
+	// Changing the password signs out every other session.
+	if ids := testSessionIds(t, s, user.ID); !slices.Equal(ids, []string{"current"}) {
+		t.Errorf("sessions after ChangePassword = %v, want only the current one", ids)
+	}
+	signInCtx, _ := withCall(context.Background())
+	if _, err := s.CreateSession(signInCtx, &apiv1.CreateSessionRequest{Username: "alice", Password: newPassword}); err != nil {
+		t.Errorf("CreateSession with the new password = %v", err)
+	}
+}
+
+func TestResetPassword(t *testing.T) {
+	s := newTestService(t, nil)
+	ctx := context.Background()
+	alice := createTestUser(t, s, "alice", store.RoleProductView)
+	bob := createTestUser(t, s, "bob", store.RoleProductView)
+	createTestSessions(t, s, alice.ID, "laptop", "phone")
+
+	token, err := s.CreatePasswordResetToken(ctx, &apiv1.CreatePasswordResetTokenRequest{UserId: alice.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	if _, err := s.store.CreatePasswordResetToken(ctx, &store.PasswordResetToken{
+		TokenHash:   hashToken("expired-token"),
+		UserID:      bob.ID,
+		CreatedTime: now.Add(-2 * passwordResetTokenTTL),
+		ExpiresTime: now.Add(-passwordResetTokenTTL),
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		token string
+		want  codes.Code
+	}{
+		{"no token", "", codes.InvalidArgument},
+		{"unknown token", "unknown-token", codes.PermissionDenied},
+		{"expired token", "expired-token", codes.PermissionDenied},
+		{"token", token.Token, codes.OK},
+		{"used token", token.Token, codes.PermissionDenied},
+	}
+
+	const newPassword = "clementine-otter-4"
+	for _, test := range tests {
+		if _, err := s.ResetPassword(ctx, &apiv1.ResetPasswordRequest{Token: test.token, NewPassword: newPassword}); status.Code(err) != test.want {
+			t.Errorf("ResetPassword with %s = %v, want %s", test.name, err, test.want)
+		}
+	}
+
+	// The reset signs out every session of the user.
+	if ids := testSessionIds(t, s, alice.ID); len(ids) != 0 {
+		t.Errorf("sessions after ResetPassword = %v, want none", ids)
+	}
+	for _, user := range []struct {
+		username string
+		password string
+	}{
+		{"alice", newPassword},
+		{"bob", testPassword},
+	} {
+		signInCtx, _ := withCall(ctx)
+		if _, err := s.CreateSession(signInCtx, &apiv1.CreateSessionRequest{Username: user.username, Password: user.password}); err != nil {
+			t.Errorf("CreateSession of %s after ResetPassword = %v", user.username, err)
+		}
+	}
+}
+
+func TestCreateAccessTokenScopes(t *testing.T) {
+	s := newTestService(t, nil)
+	ctx := context.Background()
+	admin := createTestUser(t, s, "admin", store.RoleAdmin)
+	editor := createTestUser(t, s, "editor", store.RoleProductEdit)
+	viewer := createTestUser(t, s, "viewer", store.RoleProductView)
+
+	viewToken := &store.AccessToken{Scopes: []store.Role{store.RoleProductView}}
+
+	tests := []struct {
+		name   string
+		ctx    context.Context
+		scopes []apiv1.Role
+		want   []store.Role // nil when the token must be refused
+	}{
+		{"own role by default", withUser(ctx, editor.ID), nil, []store.Role{store.RoleProductEdit}},
+		{"lower scope", withUser(ctx, editor.ID), []apiv1.Role{apiv1.Role_PRODUCT_VIEWER}, []store.Role{store.RoleProductView}},
+		{"admin scopes", withUser(ctx, admin.ID), []apiv1.Role{apiv1.Role_PRODUCT_EDITOR, apiv1.Role_ADMIN}, []store.Role{store.RoleProductEdit, store.RoleAdmin}},
+		{"higher scope", withUser(ctx, viewer.ID), []apiv1.Role{apiv1.Role_PRODUCT_EDITOR}, nil},
+		{"admin scope for an editor", withUser(ctx, editor.ID), []apiv1.Role{apiv1.Role_PRODUCT_VIEWER, apiv1.Role_ADMIN}, nil},
+		{"unspecified scope", withUser(ctx, editor.ID), []apiv1.Role{apiv1.Role_UNSPECIFIED}, nil},
+		// A token can only make tokens within its own scopes.
+		{"token's scopes by default", context.WithValue(withUser(ctx, admin.ID), accessTokenContextKey, viewToken), nil, []store.Role{store.RoleProductView}},
+		{"scope beyond the token's", context.WithValue(withUser(ctx, admin.ID), accessTokenContextKey, viewToken), []apiv1.Role{apiv1.Role_PRODUCT_EDITOR}, nil},
+	}
+
+	for _, test := range tests {
+		token, err := s.CreateAccessToken(test.ctx, &apiv1.CreateAccessTokenRequest{Name: test.name, Scopes: test.scopes})
+		if test.want == nil {
+			if status.Code(err) != codes.PermissionDenied && status.Code(err) != codes.InvalidArgument {
+				t.Errorf("CreateAccessToken with %s = %v, want an error", test.name, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("CreateAccessToken with %s = %v", test.name, err)
+			continue
+		}
+
+		var got []store.Role
+		for _, scope := range token.Scopes {
+			role, _ := convertUserRoleToStore(scope)
+			got = append(got, role)
+		}
+		if !slices.Equal(got, test.want) {
+			t.Errorf("CreateAccessToken with %s has scopes %v, want %v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestValidatePassword(t *testing.T) {
+	s := newTestService(t, func(cfg *config.Config) {
+		cfg.Password.MaxLength = 20
+		cfg.Password.RequireUpper = true
+		cfg.Password.RequireDigit = true
+		cfg.Password.RequireSymbol = true
+	})
+
+	tests := []struct {
+		password string
+		want     []string
+	}{
+		{"Tangerine-walrus-9", nil},
+		{"T-w9", []string{"TOO_SHORT"}},
+		{"Tangerine-walrus-9-tangerine", []string{"TOO_LONG"}},
+		{"tangerine-walrus-9", []string{"MISSING_UPPERCASE"}},
+		{"Tangerine-walrus", []string{"MISSING_DIGIT"}},
+		{"Tangerine walrus 9", nil},
+		{"TangerineWalrus9", []string{"MISSING_SYMBOL"}},
+		{"Password123", []string{"MISSING_SYMBOL", "TOO_COMMON"}},
+		{"Alice-walrus-9", []string{"CONTAINS_USERNAME"}},
+		{"password", []string{"MISSING_UPPERCASE", "MISSING_DIGIT", "MISSING_SYMBOL", "TOO_COMMON"}},
+	}
+
+	for _, test := range tests {
+		err := s.validatePassword("alice", test.password, "new_password")
+		if test.want == nil {
+			if err != nil {
+				t.Errorf("validatePassword(%q) = %v, want nil", test.password, err)
+			}
+			continue
+		}
+
+		st := status.Convert(err)
+		if st.Code() != codes.InvalidArgument {
+			t.Errorf("validatePassword(%q) = %v, want InvalidArgument", test.password, err)
+			continue
+		}
+
+		var got []string
+		for _, detail := range st.Details() {
+			badRequest, ok := detail.(*errdetails.BadRequest)
+			if !ok {
+				continue
+			}
+			for _, violation := range badRequest.FieldViolations {
+				if violation.Field != "new_password" {
+					t.Errorf("validatePassword(%q) violation of field %q, want new_password", test.password, violation.Field)
+				}
+				got = append(got, violation.Reason)
+			}
+		}
+		if !slices.Equal(got, test.want) {
+			t.Errorf("validatePassword(%q) violations = %v, want %v", test.password, got, test.want)
+		}
+	}
+}
+
+func TestCreateSessionRehashesPassword(t *testing.T) {
+	otherCost := config.PasswordConfig{Algorithm: password.AlgorithmBcrypt, BcryptCost: 5}
+	tests := []struct {
+		name   string
+		config config.PasswordConfig
+		// disabled and totp users are not signed in by their password, so
+		// their hash is left alone.
+		disabled bool
+		totp     bool
+	}{
+		{"other bcrypt cost", otherCost, false, false},
+		{"argon2id", config.PasswordConfig{Algorithm: password.AlgorithmArgon2id, Argon2id: config.Argon2idConfig{Memory: 64, Iterations: 1, Parallelism: 1}}, false, false},
+		{"a disabled user", otherCost, true, false},
+		{"a TOTP user", otherCost, false, true},
+	}
+
+	for _, test := range tests {
+		s := newTestService(t, nil)
+		oldHasher, err := password.NewHasher(test.config)
+		if err != nil {
+			t.Fatal(err)
+		}
+		oldHash, err := oldHasher.Hash(testPassword)
+		if err != nil {
+			t.Fatal(err)
+		}
+		user, err := s.store.CreateUser(context.Background(), &store.User{Username: "alice", PasswordHash: oldHash, Role: store.RoleProductView})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if test.disabled {
+			if _, err := s.store.UpdateUser(context.Background(), &store.UpdateUser{ID: user.ID, Disabled: &test.disabled}); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if test.totp {
+			enableTestTOTP(t, s, user.ID)
+		}
+
+		signIn := func(pw string) (*apiv1.CreateSessionResponse, error) {
+			ctx, _ := withCall(context.Background())
+			return s.CreateSession(ctx, &apiv1.CreateSessionRequest{Username: "alice", Password: pw})
+		}
+		passwordHash := func() string {
+			user, err := s.store.GetUser(context.Background(), &store.FindUser{ID: &user.ID})
+			if err != nil || user == nil {
+				t.Fatalf("GetUser = %+v, %v", user, err)
+			}
+			return user.PasswordHash
+		}
+
+		// A failed sign-in leaves the hash alone.
+		if _, err := signIn("wrong-password-1"); status.Code(err) != codes.Unauthenticated {
+			t.Fatalf("CreateSession with %s and a wrong password = %v, want Unauthenticated", test.name, err)
+		}
+		if passwordHash() != oldHash {
+			t.Errorf("CreateSession with %s and a wrong password changed the hash", test.name)
+		}
+
+		resp, err := signIn(testPassword)
+		if test.disabled || test.totp {
+			if test.disabled && status.Code(err) != codes.PermissionDenied {
+				t.Errorf("CreateSession with %s = %v, want PermissionDenied", test.name, err)
+			}
+			if test.totp && (err != nil || resp.GetChallenge() == "") {
+				t.Errorf("CreateSession with %s = %+v, %v; want a challenge", test.name, resp, err)
+			}
+			if passwordHash() != oldHash {
+				t.Errorf("CreateSession with %s changed the hash", test.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("CreateSession with %s = %v", test.name, err)
+		}
+		newHash := passwordHash()
+		if newHash == oldHash || s.passwordHasher.NeedsRehash(newHash) {
+			t.Errorf("CreateSession with %s left hash %q, want one of the configured algorithm", test.name, newHash)
+		}
+
+		// The new hash works, and is not replaced again.
+		if _, err := signIn(testPassword); err != nil {
+			t.Fatalf("CreateSession with %s after the rehash = %v", test.name, err)
+		}
+		if passwordHash() != newHash {
+			t.Errorf("CreateSession with %s rehashed the password twice", test.name)
+		}
+	}
+}
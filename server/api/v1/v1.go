@@ -5,8 +5,10 @@ import (
 	"fmt"
 	"math"
 	"net/http"
+	"strings"
 
 	"github.com/thetnaingtn/dirty-hand/internal/config"
+	"github.com/thetnaingtn/dirty-hand/internal/password"
 	apiv1 "github.com/thetnaingtn/dirty-hand/proto/gen/api/v1"
 	"github.com/thetnaingtn/dirty-hand/server/frontend"
 	"github.com/thetnaingtn/dirty-hand/store"
@@ -15,28 +17,86 @@ import (
 	"github.com/improbable-eng/grpc-web/go/grpcweb"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/proto"
 )
 
 type APIV1Service struct {
 	apiv1.UnimplementedProductServiceServer
 	apiv1.UnimplementedUserServiceServer
-	store      store.Store
-	grpcServer *grpc.Server
-	config     *config.Config
+	apiv1.UnimplementedOrganizationServiceServer
+	apiv1.UnimplementedAuditServiceServer
+	store       store.Store
+	grpcServer  *grpc.Server
+	config      *config.Config
+	cookieCodec *sessionCookieCodec
+	oidc        *oidcAuthenticator
+
+	trustedProxies trustedProxies
+
+	passwordPolicy *password.Policy
+	passwordHasher *password.Hasher
+	// dummyPasswordHash is verified against when the user does not exist, so
+	// that such sign-ins take as long as those with a wrong password.
+	dummyPasswordHash string
 }
 
 // NewAPIV1Service creates a new instance of APIV1Service
-func NewAPIV1Service(grpcServer *grpc.Server, storeInstance store.Store, cfg *config.Config) *APIV1Service {
+func NewAPIV1Service(grpcServer *grpc.Server, storeInstance store.Store, cfg *config.Config) (*APIV1Service, error) {
+	if cfg.Session.AbsoluteLifetime <= 0 {
+		return nil, fmt.Errorf("session absolute lifetime must be positive, got %s", cfg.Session.AbsoluteLifetime)
+	}
+
+	cookieCodec, err := newSessionCookieCodec(cfg.Session)
+	if err != nil {
+		return nil, err
+	}
+
+	trustedProxies, err := newTrustedProxies(cfg.Server)
+	if err != nil {
+		return nil, err
+	}
+
+	passwordHasher, err := password.NewHasher(cfg.Password)
+	if err != nil {
+		return nil, err
+	}
+
+	switch cfg.Registration.Mode {
+	case config.RegistrationOpen, config.RegistrationInviteOnly, config.RegistrationClosed:
+	default:
+		return nil, fmt.Errorf("invalid registration mode %q", cfg.Registration.Mode)
+	}
+
+	dummyPasswordHash, err := passwordHasher.Hash("dummy password")
+	if err != nil {
+		return nil, err
+	}
+
 	apiService := &APIV1Service{
-		store:      storeInstance,
-		grpcServer: grpcServer,
-		config:     cfg,
+		store:             storeInstance,
+		grpcServer:        grpcServer,
+		config:            cfg,
+		cookieCodec:       cookieCodec,
+		trustedProxies:    trustedProxies,
+		passwordPolicy:    password.NewPolicy(cfg.Password),
+		passwordHasher:    passwordHasher,
+		dummyPasswordHash: dummyPasswordHash,
+	}
+
+	if cfg.OIDC.Enabled {
+		oidc, err := newOIDCAuthenticator(apiService, cfg)
+		if err != nil {
+			return nil, err
+		}
+		apiService.oidc = oidc
 	}
 
 	apiv1.RegisterProductServiceServer(grpcServer, apiService)
 	apiv1.RegisterUserServiceServer(grpcServer, apiService)
+	apiv1.RegisterOrganizationServiceServer(grpcServer, apiService)
+	apiv1.RegisterAuditServiceServer(grpcServer, apiService)
 
-	return apiService
+	return apiService, nil
 }
 
 func (s *APIV1Service) RegisterGateway(ctx context.Context, mux *http.ServeMux) error {
@@ -50,12 +110,28 @@ func (s *APIV1Service) RegisterGateway(ctx context.Context, mux *http.ServeMux)
 		return err
 	}
 
-	gwmux := runtime.NewServeMux()
+	gwmux := runtime.NewServeMux(
+		runtime.WithForwardResponseOption(forwardSetCookie),
+		runtime.WithIncomingHeaderMatcher(incomingHeaderMatcher),
+		runtime.WithOutgoingHeaderMatcher(outgoingHeaderMatcher),
+	)
 
 	if err := apiv1.RegisterProductServiceHandler(ctx, gwmux, conn); err != nil {
 		return err
 	}
 
+	if err := apiv1.RegisterUserServiceHandler(ctx, gwmux, conn); err != nil {
+		return err
+	}
+
+	if err := apiv1.RegisterOrganizationServiceHandler(ctx, gwmux, conn); err != nil {
+		return err
+	}
+
+	if err := apiv1.RegisterAuditServiceHandler(ctx, gwmux, conn); err != nil {
+		return err
+	}
+
 	grpcWebOptions := []grpcweb.Option{
 		grpcweb.WithOriginFunc(func(origin string) bool {
 			return true
@@ -66,13 +142,11 @@ func (s *APIV1Service) RegisterGateway(ctx context.Context, mux *http.ServeMux)
 
 	handler := func(w http.ResponseWriter, r *http.Request) {
 		if len(r.URL.Path) >= 8 && r.URL.Path[:8] == "/api.v1." {
-			fmt.Println("Handling gRPC-Web request")
 			grpcWebProxy.ServeHTTP(w, r)
 			return
 		}
 
 		if len(r.URL.Path) >= 4 && r.URL.Path[:4] == "/v1/" {
-			fmt.Println("Handling gRPC-Gateway request")
 			gwmux.ServeHTTP(w, r)
 			return
 		}
@@ -80,7 +154,48 @@ func (s *APIV1Service) RegisterGateway(ctx context.Context, mux *http.ServeMux)
 		frontendService.ServeHTTP(w, r)
 	}
 
+	if s.oidc != nil {
+		mux.HandleFunc(oidcLoginPath, s.oidc.handleLogin)
+		mux.HandleFunc(oidcCallbackPath, s.oidc.handleCallback)
+		mux.HandleFunc(oidcSecondFactorPath, s.oidc.handleSecondFactor)
+	}
+
 	mux.Handle("/", http.HandlerFunc(handler))
 
 	return nil
 }
+
+// forwardSetCookie turns the Set-Cookie header metadata sent by the services
+// into a real Set-Cookie header for gateway clients.
+func forwardSetCookie(ctx context.Context, w http.ResponseWriter, _ proto.Message) error {
+	md, ok := runtime.ServerMetadataFromContext(ctx)
+	if !ok {
+		return nil
+	}
+
+	for _, cookie := range md.HeaderMD.Get("set-cookie") {
+		w.Header().Add("Set-Cookie", cookie)
+	}
+
+	return nil
+}
+
+// incomingHeaderMatcher passes the organization header on to the services
+// on top of the headers the gateway forwards by default.
+func incomingHeaderMatcher(key string) (string, bool) {
+	if strings.EqualFold(key, organizationIdMetadataKey) {
+		return organizationIdMetadataKey, true
+	}
+
+	return runtime.DefaultHeaderMatcher(key)
+}
+
+// outgoingHeaderMatcher keeps the gateway's default Grpc-Metadata- prefixing,
+// except for Set-Cookie which forwardSetCookie already sends as is.
+func outgoingHeaderMatcher(key string) (string, bool) {
+	if strings.EqualFold(key, "set-cookie") {
+		return "", false
+	}
+
+	return runtime.MetadataHeaderPrefix + key, true
+}
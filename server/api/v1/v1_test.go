@@ -0,0 +1,238 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/thetnaingtn/dirty-hand/internal/config"
+	"github.com/thetnaingtn/dirty-hand/store"
+	"github.com/thetnaingtn/dirty-hand/store/db/sqlite"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// testPassword passes the default password policy.
+const testPassword = "tangerine-walrus-9"
+
+// newTestConfig returns the default configuration on a database of its own,
+// with cheap password hashing so that tests can sign in often.
+func newTestConfig(t *testing.T) *config.Config {
+	t.Helper()
+
+	return &config.Config{
+		Server:   config.ServerConfig{Addr: "localhost", Port: "8080"},
+		Database: config.DatabaseConfig{DSN: filepath.Join(t.TempDir(), "atlas.db")},
+		Session: config.SessionConfig{
+			IdleTimeout:      336 * time.Hour,
+			AbsoluteLifetime: 720 * time.Hour,
+			CookieKeys:       []string{"0123456789abcdef0123456789abcdef"},
+		},
+		Password: config.PasswordConfig{
+			MinLength:      8,
+			MaxLength:      128,
+			RejectCommon:   true,
+			RejectUsername: true,
+			Algorithm:      "bcrypt",
+			BcryptCost:     4,
+		},
+		SignIn: config.SignInConfig{
+			MaxUserFailures: 5,
+			MaxIPFailures:   20,
+			LockoutDuration: 15 * time.Minute,
+			FailureWindow:   time.Hour,
+		},
+		Registration: config.RegistrationConfig{
+			Mode:          config.RegistrationOpen,
+			InvitationTTL: 168 * time.Hour,
+		},
+	}
+}
+
+// newTestStore returns a store on the database of cfg.
+func newTestStore(t *testing.T, cfg *config.Config) *store.Store {
+	t.Helper()
+
+	driver, err := sqlite.NewDB(cfg)
+	if errors.Is(err, sqlite.ErrNoFullTextSearch) {
+		t.Skip(err)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { driver.Close() })
+
+	if _, err := driver.Migrator().Up(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	return store.NewStore(driver, cfg)
+}
+
+// newTestService returns a service on a database of its own, configured by
+// the defaults of newTestConfig changed by configure.
+func newTestService(t *testing.T, configure func(cfg *config.Config)) *APIV1Service {
+	t.Helper()
+
+	cfg := newTestConfig(t)
+	if configure != nil {
+		configure(cfg)
+	}
+
+	service, err := NewAPIV1Service(grpc.NewServer(), *newTestStore(t, cfg), cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return service
+}
+
+// createTestUser stores a user with testPassword.
+func createTestUser(t *testing.T, s *APIV1Service, username string, role store.Role) *store.User {
+	t.Helper()
+
+	passwordHash, err := s.passwordHasher.Hash(testPassword)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	user, err := s.store.CreateUser(context.Background(), &store.User{Username: username, PasswordHash: passwordHash, Role: role})
+	if err != nil {
+		t.Fatalf("CreateUser(%q): %v", username, err)
+	}
+
+	return user
+}
+
+// withUser returns a context authenticated as the user, as the auth
+// interceptor would leave it.
+func withUser(ctx context.Context, userId int64) context.Context {
+	return context.WithValue(ctx, userIdContextKey, userId)
+}
+
+// withCall returns a context for calling a service method directly, as the
+// gRPC server would. The header metadata the method sends, such as its
+// cookies, is captured by the returned stream.
+func withCall(ctx context.Context) (context.Context, *headerCaptureStream) {
+	stream := &headerCaptureStream{}
+	ctx = metadata.NewIncomingContext(ctx, metadata.MD{})
+	return grpc.NewContextWithServerTransportStream(ctx, stream), stream
+}
+
+// enableTestTOTP turns on two-factor authentication for the user and returns
+// the TOTP key.
+func enableTestTOTP(t *testing.T, s *APIV1Service, userId int64, recoveryCodes ...string) []byte {
+	t.Helper()
+
+	recoveryCodeHashes := make([]string, 0, len(recoveryCodes))
+	for _, code := range recoveryCodes {
+		recoveryCodeHashes = append(recoveryCodeHashes, hashToken(code))
+	}
+
+	ctx := context.Background()
+	key := []byte("12345678901234567890")
+	if _, err := s.store.EnrollUserTOTP(ctx, &store.UserTOTP{UserID: userId, Secret: totpEncoding.EncodeToString(key), CreatedTime: time.Now()}, recoveryCodeHashes); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.store.EnableUserTOTP(ctx, userId); err != nil {
+		t.Fatal(err)
+	}
+
+	return key
+}
+
+// currentTOTPStep returns the TOTP time step of now.
+func currentTOTPStep() int64 {
+	return time.Now().Unix() / int64(totpPeriod.Seconds())
+}
+
+// createTestOrganization stores an organization.
+func createTestOrganization(t *testing.T, s *APIV1Service, name string) *store.Organization {
+	t.Helper()
+
+	organization, err := s.store.CreateOrganization(context.Background(), &store.Organization{Name: name, CreatedTime: time.Now()})
+	if err != nil {
+		t.Fatalf("CreateOrganization(%q): %v", name, err)
+	}
+
+	return organization
+}
+
+// withOrganization returns a context acting in the organization, as the auth
+// interceptor would leave it.
+func withOrganization(ctx context.Context, organizationId int64) context.Context {
+	return context.WithValue(ctx, organizationIdContextKey, organizationId)
+}
+
+func TestNewAPIV1Service(t *testing.T) {
+	tests := []struct {
+		absoluteLifetime time.Duration
+		wantErr          bool
+	}{
+		{720 * time.Hour, false},
+		{time.Minute, false},
+		// Sessions would be expired as soon as they are created.
+		{0, true},
+		{-time.Hour, true},
+	}
+
+	for _, test := range tests {
+		cfg := newTestConfig(t)
+		cfg.Session.AbsoluteLifetime = test.absoluteLifetime
+		_, err := NewAPIV1Service(grpc.NewServer(), store.Store{}, cfg)
+		if (err != nil) != test.wantErr {
+			t.Errorf("NewAPIV1Service with an absolute lifetime of %s = %v, want error %t", test.absoluteLifetime, err, test.wantErr)
+		}
+	}
+}
+
+func TestForwardSetCookie(t *testing.T) {
+	cookies := []string{"user_session=1-abc;Path=/;HttpOnly", "theme=dark"}
+	ctx := runtime.NewServerMetadataContext(context.Background(), runtime.ServerMetadata{
+		HeaderMD: metadata.Pairs("set-cookie", cookies[0], "set-cookie", cookies[1], "x-request-id", "42"),
+	})
+
+	w := httptest.NewRecorder()
+	if err := forwardSetCookie(ctx, w, nil); err != nil {
+		t.Fatal(err)
+	}
+	if got := w.Header().Values("Set-Cookie"); !slices.Equal(got, cookies) {
+		t.Errorf("forwardSetCookie set cookies %v, want %v", got, cookies)
+	}
+	if got := w.Header().Get("X-Request-Id"); got != "" {
+		t.Errorf("forwardSetCookie set X-Request-Id %q, want only cookies", got)
+	}
+
+	// Calls that never reached a service have no metadata to forward.
+	w = httptest.NewRecorder()
+	if err := forwardSetCookie(context.Background(), w, nil); err != nil || len(w.Header()) != 0 {
+		t.Errorf("forwardSetCookie without metadata = %v and headers %v, want none", err, w.Header())
+	}
+}
+
+func TestOutgoingHeaderMatcher(t *testing.T) {
+	tests := []struct {
+		key    string
+		want   string
+		wantOK bool
+	}{
+		{"set-cookie", "", false},
+		{"Set-Cookie", "", false},
+		{"x-request-id", "Grpc-Metadata-x-request-id", true},
+		{"content-type", "Grpc-Metadata-content-type", true},
+	}
+
+	for _, test := range tests {
+		got, ok := outgoingHeaderMatcher(test.key)
+		if got != test.want || ok != test.wantOK {
+			t.Errorf("outgoingHeaderMatcher(%q) = %q, %t; want %q, %t", test.key, got, ok, test.want, test.wantOK)
+		}
+	}
+}
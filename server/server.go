@@ -2,6 +2,8 @@ package server
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/base64"
 	"fmt"
 	"log/slog"
 	"net"
@@ -24,21 +26,40 @@ type Server struct {
 
 	grpcServer *grpc.Server
 	server     http.Server
+
+	stopSessionReaper context.CancelFunc
+	sessionReaperDone chan struct{}
 }
 
 func NewServer(ctx context.Context, store *store.Store, config *config.Config) (*Server, error) {
 	mux := http.NewServeMux()
 
-	authInterceptor := v1.NewGRPCAuthInterceptor(store)
+	if err := prepareSessionCookieKeys(config); err != nil {
+		return nil, err
+	}
+
+	authInterceptor, err := v1.NewGRPCAuthInterceptor(store, config)
+	if err != nil {
+		return nil, err
+	}
+
+	auditInterceptor, err := v1.NewGRPCAuditInterceptor(store, config)
+	if err != nil {
+		return nil, err
+	}
 
 	grpcServer := grpc.NewServer(
 		grpc.ChainUnaryInterceptor(
 			grpcrecovery.UnaryServerInterceptor(),
+			auditInterceptor.AuditInterceptor,
 			authInterceptor.AuthenticateInterceptor,
 		),
 	)
 
-	service := v1.NewAPIV1Service(grpcServer, *store, config)
+	service, err := v1.NewAPIV1Service(grpcServer, *store, config)
+	if err != nil {
+		return nil, err
+	}
 
 	if err := service.RegisterGateway(ctx, mux); err != nil {
 		return nil, err
@@ -52,6 +73,32 @@ func NewServer(ctx context.Context, store *store.Store, config *config.Config) (
 	}, nil
 }
 
+// prepareSessionCookieKeys checks the configured session cookie keys and
+// generates a random one when none is configured.
+func prepareSessionCookieKeys(config *config.Config) error {
+	for _, key := range config.Session.CookieKeys {
+		if len(key) < minSessionCookieKeyLength {
+			return fmt.Errorf("session cookie keys must be at least %d characters long", minSessionCookieKeyLength)
+		}
+	}
+
+	if len(config.Session.CookieKeys) > 0 {
+		return nil
+	}
+
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return err
+	}
+
+	slog.Warn("no session cookie key configured, using a random key; sessions will not survive a restart")
+	config.Session.CookieKeys = []string{base64.RawStdEncoding.EncodeToString(key)}
+
+	return nil
+}
+
+const minSessionCookieKeyLength = 32
+
 func (s *Server) Start() error {
 	address := fmt.Sprintf("%s:%s", s.Config.Server.Addr, s.Config.Server.Port)
 
@@ -62,18 +109,22 @@ func (s *Server) Start() error {
 
 	muxServer := cmux.New(listener)
 
-	go func() {
-		grpcListener := muxServer.MatchWithWriters(
-			cmux.HTTP2MatchHeaderFieldSendSettings("content-type", "application/grpc"),
-		)
+	// The matchers are registered before serving, cmux does not allow adding
+	// them concurrently.
+	grpcListener := muxServer.MatchWithWriters(
+		cmux.HTTP2MatchHeaderFieldSendSettings("content-type", "application/grpc"),
+	)
+	// HTTP1Fast only knows the methods of RFC 2616, so PATCH used by the
+	// gateway routes has to be added explicitly.
+	httpListener := muxServer.Match(cmux.HTTP1Fast(http.MethodPatch))
 
+	go func() {
 		if err := s.grpcServer.Serve(grpcListener); err != nil {
 			slog.Error("Failed to start gRPC server", "error", err)
 		}
 	}()
 
 	go func() {
-		httpListener := muxServer.Match(cmux.HTTP1Fast())
 		if err := s.server.Serve(httpListener); err != nil && err != http.ErrServerClosed {
 			slog.Error("Failed to start HTTP server", "error", err)
 		}
@@ -85,6 +136,17 @@ func (s *Server) Start() error {
 		}
 	}()
 
+	if interval := s.Config.Session.CleanupInterval; interval > 0 {
+		ctx, cancel := context.WithCancel(context.Background())
+		s.stopSessionReaper = cancel
+		s.sessionReaperDone = make(chan struct{})
+
+		go func() {
+			defer close(s.sessionReaperDone)
+			s.runSessionReaper(ctx, interval)
+		}()
+	}
+
 	return nil
 }
 
@@ -92,6 +154,15 @@ func (s *Server) Shutdown(ctx context.Context) error {
 	ctx, cancel := context.WithTimeout(ctx, time.Second*20)
 	defer cancel()
 	slog.Info("Shutting down server...")
+
+	if s.stopSessionReaper != nil {
+		s.stopSessionReaper()
+		select {
+		case <-s.sessionReaperDone:
+		case <-ctx.Done():
+		}
+	}
+
 	if err := s.server.Shutdown(ctx); err != nil {
 		if err != http.ErrServerClosed {
 			slog.Error("Failed to shutdown HTTP server", "error", err)
@@ -0,0 +1,46 @@
+package server
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// runSessionReaper deletes expired sessions, second factor challenges and
+// sign-in throttles every interval until ctx is cancelled.
+func (s *Server) runSessionReaper(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		s.reapExpiredSessions(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *Server) reapExpiredSessions(ctx context.Context) {
+	deleted, err := s.Store.DeleteExpiredSessions(ctx, s.Config.Session.IdleTimeout)
+	if err != nil {
+		if ctx.Err() == nil {
+			slog.Error("failed to delete expired sessions", "error", err)
+		}
+		return
+	}
+
+	if deleted > 0 {
+		slog.Info("deleted expired sessions", "count", deleted)
+	}
+
+	if _, err := s.Store.DeleteExpiredSecondFactorChallenges(ctx); err != nil && ctx.Err() == nil {
+		slog.Error("failed to delete expired second factor challenges", "error", err)
+	}
+
+	if _, err := s.Store.DeleteStaleSignInThrottles(ctx, time.Now().Add(-s.Config.SignIn.FailureWindow)); err != nil && ctx.Err() == nil {
+		slog.Error("failed to delete stale sign-in throttles", "error", err)
+	}
+}
@@ -0,0 +1,137 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/thetnaingtn/dirty-hand/internal/config"
+	"github.com/thetnaingtn/dirty-hand/store"
+	"github.com/thetnaingtn/dirty-hand/store/db/sqlite"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func TestSessionReaper(t *testing.T) {
+	cfg := &config.Config{
+		Server:   config.ServerConfig{Addr: "127.0.0.1", Port: "0"},
+		Database: config.DatabaseConfig{DSN: filepath.Join(t.TempDir(), "atlas.db")},
+		Session: config.SessionConfig{
+			IdleTimeout:      time.Hour,
+			AbsoluteLifetime: 24 * time.Hour,
+			CleanupInterval:  10 * time.Millisecond,
+		},
+		Password:     config.PasswordConfig{Algorithm: "bcrypt", BcryptCost: 4},
+		SignIn:       config.SignInConfig{FailureWindow: time.Hour},
+		Registration: config.RegistrationConfig{Mode: config.RegistrationOpen},
+	}
+	driver, err := sqlite.NewDB(cfg)
+	if errors.Is(err, sqlite.ErrNoFullTextSearch) {
+		t.Skip(err)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := driver.Migrator().Up(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	s, err := NewServer(context.Background(), store.NewStore(driver, cfg), cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	user, err := s.Store.CreateUser(ctx, &store.User{Username: "alice", PasswordHash: "-", Role: store.RoleProductView})
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	for _, session := range []*store.Session{
+		{SessionID: "active", LastAccessedTime: now, ExpiresAt: now.Add(time.Hour)},
+		{SessionID: "idle", LastAccessedTime: now.Add(-2 * time.Hour), ExpiresAt: now.Add(time.Hour)},
+		{SessionID: "expired", LastAccessedTime: now, ExpiresAt: now.Add(-time.Minute)},
+	} {
+		session.UserID = user.ID
+		session.CreatedTime = now.Add(-3 * time.Hour)
+		if _, err := s.Store.CreateSession(ctx, session); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, challenge := range []*store.SecondFactorChallenge{
+		{TokenHash: "active", ExpiresTime: now.Add(time.Minute)},
+		{TokenHash: "expired", ExpiresTime: now.Add(-time.Minute)},
+	} {
+		challenge.UserID = user.ID
+		challenge.CreatedTime = now.Add(-10 * time.Minute)
+		if _, err := s.Store.CreateSecondFactorChallenge(ctx, challenge); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, throttle := range []*store.SignInThrottle{
+		{Key: "recent", LastFailureTime: now},
+		{Key: "stale", LastFailureTime: now.Add(-2 * time.Hour)},
+		{Key: "locked", LastFailureTime: now.Add(-2 * time.Hour), LockedUntil: now.Add(time.Hour)},
+	} {
+		throttle.Failures = 1
+		if _, err := s.Store.UpsertSignInThrottle(ctx, throttle); err != nil {
+			t.Fatal(err)
+		}
+	}
+	throttleExists := func(key string) bool {
+		throttle, err := s.Store.GetSignInThrottle(ctx, key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return throttle != nil
+	}
+	// challengeExists looks the challenge up as of the zero time, before
+	// any challenge expires.
+	challengeExists := func(tokenHash string) bool {
+		challenge, err := driver.GetSecondFactorChallenge(ctx, tokenHash, time.Time{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return challenge != nil
+	}
+
+	if err := s.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	var ids []string
+	for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+		sessions, err := s.Store.GetUserSessions(ctx, user.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = ids[:0]
+		for _, session := range sessions {
+			ids = append(ids, session.SessionID)
+		}
+		if len(ids) == 1 && !challengeExists("expired") && !throttleExists("stale") {
+			break
+		}
+	}
+	if !slices.Equal(ids, []string{"active"}) {
+		t.Errorf("sessions left by the reaper = %v, want [active]", ids)
+	}
+	if challengeExists("expired") || !challengeExists("active") {
+		t.Error("the reaper did not delete exactly the expired second factor challenge")
+	}
+	if throttleExists("stale") || !throttleExists("recent") || !throttleExists("locked") {
+		t.Error("the reaper did not delete exactly the stale sign-in throttle")
+	}
+
+	if err := s.Shutdown(ctx); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-s.sessionReaperDone:
+	default:
+		t.Error("the session reaper is still running after Shutdown")
+	}
+}
@@ -0,0 +1,80 @@
+package store
+
+import (
+	"context"
+	"time"
+)
+
+// AccessToken is a personal access token used by API and CLI clients in
+// place of a session cookie. Only a hash of the token is stored.
+type AccessToken struct {
+	ID        int64
+	UserID    int64
+	Name      string
+	TokenHash string
+	// Scopes limit the token to these roles, each one held by the owner.
+	Scopes       []Role
+	CreatedTime  time.Time
+	ExpiresAt    *time.Time
+	LastUsedTime *time.Time
+}
+
+type FindAccessToken struct {
+	ID        *int64
+	UserID    *int64
+	TokenHash *string
+}
+
+func (s *Store) CreateAccessToken(ctx context.Context, token *AccessToken) (*AccessToken, error) {
+	token, err := s.driver.CreateAccessToken(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	recordChange(ctx, "access_token", token.ID, nil, token.auditSnapshot())
+
+	return token, nil
+}
+
+func (s *Store) ListAccessTokens(ctx context.Context, find *FindAccessToken) ([]*AccessToken, error) {
+	return s.driver.ListAccessTokens(ctx, find)
+}
+
+// GetAccessToken returns the first access token matching find, or nil when
+// there is none.
+func (s *Store) GetAccessToken(ctx context.Context, find *FindAccessToken) (*AccessToken, error) {
+	tokens, err := s.driver.ListAccessTokens(ctx, find)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(tokens) == 0 {
+		return nil, nil
+	}
+
+	return tokens[0], nil
+}
+
+func (s *Store) UpdateAccessTokenLastUsedTime(ctx context.Context, id int64, lastUsedTime time.Time) error {
+	return s.driver.UpdateAccessTokenLastUsedTime(ctx, id, lastUsedTime)
+}
+
+func (s *Store) DeleteAccessToken(ctx context.Context, id int64) error {
+	var before *AccessToken
+	if auditing(ctx) {
+		var err error
+		if before, err = s.GetAccessToken(ctx, &FindAccessToken{ID: &id}); err != nil {
+			return err
+		}
+	}
+
+	if err := s.driver.DeleteAccessToken(ctx, id); err != nil {
+		return err
+	}
+
+	if before != nil {
+		recordChange(ctx, "access_token", id, before.auditSnapshot(), nil)
+	}
+
+	return nil
+}
@@ -0,0 +1,267 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Audit change actions
+const (
+	AuditActionCreate = "create"
+	AuditActionUpdate = "update"
+	AuditActionDelete = "delete"
+)
+
+// AuditEvent records one mutating API call: who made it, what it changed and
+// how it ended.
+type AuditEvent struct {
+	ID          int64
+	CreatedTime time.Time
+	// ActorID is nil for calls made without signing in.
+	ActorID        *int64
+	OrganizationID *int64
+	Method         string
+	// ResourceType and ResourceID name the resource the call targeted.
+	ResourceType string
+	ResourceID   string
+	Changes      []AuditChange
+	ClientIP     string
+	// Outcome is the gRPC status code the call ended with, e.g. "OK".
+	Outcome      string
+	ErrorMessage string
+}
+
+// AuditChange is the change the store made to one resource. Before is nil
+// for created resources and After for deleted ones. For updated resources
+// both only hold the fields that changed.
+type AuditChange struct {
+	ResourceType string         `json:"resource_type"`
+	ResourceID   string         `json:"resource_id"`
+	Action       string         `json:"action"`
+	Before       map[string]any `json:"before,omitempty"`
+	After        map[string]any `json:"after,omitempty"`
+}
+
+type FindAuditEvent struct {
+	ActorID        *int64
+	OrganizationID *int64
+	Method         *string
+	ResourceType   *string
+	ResourceID     *string
+	Outcome        *string
+	CreatedAfter   *time.Time
+	CreatedBefore  *time.Time
+
+	// Events are returned newest first. BeforeID and Limit page through
+	// them.
+	BeforeID *int64
+	Limit    *int
+}
+
+func (s *Store) CreateAuditEvent(ctx context.Context, event *AuditEvent) (*AuditEvent, error) {
+	return s.driver.CreateAuditEvent(ctx, event)
+}
+
+func (s *Store) ListAuditEvents(ctx context.Context, find *FindAuditEvent) ([]*AuditEvent, error) {
+	return s.driver.ListAuditEvents(ctx, find)
+}
+
+type auditRecorderContextKey struct{}
+
+// AuditRecorder collects the changes the store makes on behalf of one API
+// call.
+type AuditRecorder struct {
+	mu      sync.Mutex
+	changes []AuditChange
+}
+
+// WithAuditRecorder returns a context in which the store reports its changes
+// to the returned recorder.
+func WithAuditRecorder(ctx context.Context) (context.Context, *AuditRecorder) {
+	recorder := &AuditRecorder{}
+	return context.WithValue(ctx, auditRecorderContextKey{}, recorder), recorder
+}
+
+// Changes returns the changes recorded so far.
+func (r *AuditRecorder) Changes() []AuditChange {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]AuditChange(nil), r.changes...)
+}
+
+// auditing reports whether the changes made with ctx are recorded, so that
+// snapshots are only loaded when needed.
+func auditing(ctx context.Context) bool {
+	_, ok := ctx.Value(auditRecorderContextKey{}).(*AuditRecorder)
+	return ok
+}
+
+// sensitiveAuditFields are compared like other fields but their values are
+// never recorded.
+var sensitiveAuditFields = map[string]bool{
+	"password_hash": true,
+}
+
+// recordChange reports the change of a resource from the before to the after
+// snapshot to the recorder of ctx, if there is one. Updates that change
+// nothing are not recorded, and neither are changes a transaction rolls back.
+func recordChange(ctx context.Context, resourceType string, resourceID any, before, after map[string]any) {
+	recorder, ok := ctx.Value(auditRecorderContextKey{}).(*AuditRecorder)
+	if !ok {
+		return
+	}
+
+	change := AuditChange{
+		ResourceType: resourceType,
+		ResourceID:   formatResourceID(resourceID),
+	}
+
+	switch {
+	case before == nil:
+		change.Action = AuditActionCreate
+		change.After = redactAuditSnapshot(after)
+	case after == nil:
+		change.Action = AuditActionDelete
+		change.Before = redactAuditSnapshot(before)
+	default:
+		change.Action = AuditActionUpdate
+		change.Before, change.After = map[string]any{}, map[string]any{}
+		for key, value := range after {
+			if !reflect.DeepEqual(before[key], value) {
+				change.Before[key] = before[key]
+				change.After[key] = value
+			}
+		}
+		if len(change.After) == 0 {
+			return
+		}
+		change.Before = redactAuditSnapshot(change.Before)
+		change.After = redactAuditSnapshot(change.After)
+	}
+
+	afterCommit(ctx, func() {
+		recorder.mu.Lock()
+		recorder.changes = append(recorder.changes, change)
+		recorder.mu.Unlock()
+	})
+}
+
+func redactAuditSnapshot(snapshot map[string]any) map[string]any {
+	for key := range snapshot {
+		if sensitiveAuditFields[key] {
+			snapshot[key] = "[redacted]"
+		}
+	}
+
+	return snapshot
+}
+
+func formatResourceID(id any) string {
+	switch v := id.(type) {
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case string:
+		return v
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// Audit snapshots hold the fields of a resource worth recording. Times are
+// formatted so that snapshots survive a round trip through JSON unchanged.
+
+func (p *Product) auditSnapshot() map[string]any {
+	if p == nil {
+		return nil
+	}
+
+	return map[string]any{
+		"organization_id": p.OrganizationID,
+		"name":            p.Name,
+		"description":     p.Description,
+		"price":           p.Price,
+		"cover":           p.Cover,
+	}
+}
+
+func (u *User) auditSnapshot() map[string]any {
+	if u == nil {
+		return nil
+	}
+
+	return map[string]any{
+		"username":      u.Username,
+		"role":          string(u.Role),
+		"disabled":      u.Disabled,
+		"password_hash": u.PasswordHash,
+	}
+}
+
+func (o *Organization) auditSnapshot() map[string]any {
+	if o == nil {
+		return nil
+	}
+
+	return map[string]any{
+		"name": o.Name,
+	}
+}
+
+func (m *Membership) auditSnapshot() map[string]any {
+	if m == nil {
+		return nil
+	}
+
+	return map[string]any{
+		"organization_id": m.OrganizationID,
+		"user_id":         m.UserID,
+		"role":            string(m.Role),
+	}
+}
+
+func (m *Membership) auditResourceID() string {
+	return fmt.Sprintf("%d/%d", m.OrganizationID, m.UserID)
+}
+
+func (i *Invitation) auditSnapshot() map[string]any {
+	if i == nil {
+		return nil
+	}
+
+	snapshot := map[string]any{
+		"role":         string(i.Role),
+		"expires_time": i.ExpiresTime.UTC().Format(time.RFC3339),
+	}
+	if i.UsedBy != nil {
+		snapshot["used_by"] = *i.UsedBy
+	}
+
+	return snapshot
+}
+
+func (t *AccessToken) auditSnapshot() map[string]any {
+	if t == nil {
+		return nil
+	}
+
+	scopes := make([]string, 0, len(t.Scopes))
+	for _, scope := range t.Scopes {
+		scopes = append(scopes, string(scope))
+	}
+
+	snapshot := map[string]any{
+		"user_id": t.UserID,
+		"name":    t.Name,
+		"scopes":  scopes,
+	}
+	if t.ExpiresAt != nil {
+		snapshot["expires_at"] = t.ExpiresAt.UTC().Format(time.RFC3339)
+	}
+
+	return snapshot
+}
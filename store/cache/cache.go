@@ -58,6 +58,12 @@ func (c *Cache) Set(key string, value any) {
 	c.data.Store(key, item)
 }
 
+func (c *Cache) Delete(key string) {
+	if _, loaded := c.data.LoadAndDelete(key); loaded {
+		c.itemCount.Add(-1)
+	}
+}
+
 func (c *Cache) Get(key string) (any, bool) {
 	value, ok := c.data.Load(key)
 	if !ok {
@@ -1,18 +1,43 @@
 package db
 
 import (
+	"fmt"
+
 	"github.com/thetnaingtn/dirty-hand/internal/config"
 	"github.com/thetnaingtn/dirty-hand/store"
+	"github.com/thetnaingtn/dirty-hand/store/db/mysql"
+	"github.com/thetnaingtn/dirty-hand/store/db/postgres"
 	"github.com/thetnaingtn/dirty-hand/store/db/sqlite"
 )
 
-var _ store.Driver = (*sqlite.DB)(nil)
+var (
+	_ store.Driver = (*sqlite.DB)(nil)
+	_ store.Driver = (*postgres.DB)(nil)
+	_ store.Driver = (*mysql.DB)(nil)
+)
 
-func NewDBDriver(config *config.Config) (store.Driver, error) {
-	db, err := sqlite.NewDB(config)
-	if err != nil {
-		return nil, err
+// NewDBDriver opens the database of the configured driver.
+func NewDBDriver(cfg *config.Config) (store.Driver, error) {
+	switch cfg.Database.Driver {
+	case config.DatabaseSQLite, "":
+		db, err := sqlite.NewDB(cfg)
+		if err != nil {
+			return nil, err
+		}
+		return db, nil
+	case config.DatabasePostgres:
+		db, err := postgres.NewDB(cfg)
+		if err != nil {
+			return nil, err
+		}
+		return db, nil
+	case config.DatabaseMySQL:
+		db, err := mysql.NewDB(cfg)
+		if err != nil {
+			return nil, err
+		}
+		return db, nil
+	default:
+		return nil, fmt.Errorf("unsupported database driver %q", cfg.Database.Driver)
 	}
-
-	return db, nil
 }
@@ -0,0 +1,127 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"strings"
+
+	"github.com/thetnaingtn/dirty-hand/store"
+)
+
+func (d *DB) CreateAuditEvent(ctx context.Context, create *store.AuditEvent) (*store.AuditEvent, error) {
+	changes, err := json.Marshal(create.Changes)
+	if err != nil {
+		return nil, err
+	}
+
+	stmt := `INSERT INTO audit_events (created_time, actor_id, organization_id, method, resource_type, resource_id, changes, client_ip, outcome, error_message)
+        VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+	result, err := d.conn(ctx).ExecContext(ctx, stmt, create.CreatedTime, create.ActorID, create.OrganizationID, create.Method, create.ResourceType,
+		create.ResourceID, string(changes), create.ClientIP, create.Outcome, create.ErrorMessage)
+	if err != nil {
+		return nil, err
+	}
+
+	if create.ID, err = result.LastInsertId(); err != nil {
+		return nil, err
+	}
+
+	return create, nil
+}
+
+func (d *DB) ListAuditEvents(ctx context.Context, find *store.FindAuditEvent) ([]*store.AuditEvent, error) {
+	where, args := []string{"1 = 1"}, []any{}
+
+	if find != nil {
+		if v := find.ActorID; v != nil {
+			where = append(where, "actor_id = ?")
+			args = append(args, *v)
+		}
+
+		if v := find.OrganizationID; v != nil {
+			where = append(where, "organization_id = ?")
+			args = append(args, *v)
+		}
+
+		if v := find.Method; v != nil {
+			where = append(where, "method = ?")
+			args = append(args, *v)
+		}
+
+		if v := find.ResourceType; v != nil {
+			where = append(where, "resource_type = ?")
+			args = append(args, *v)
+		}
+
+		if v := find.ResourceID; v != nil {
+			where = append(where, "resource_id = ?")
+			args = append(args, *v)
+		}
+
+		if v := find.Outcome; v != nil {
+			where = append(where, "outcome = ?")
+			args = append(args, *v)
+		}
+
+		if v := find.CreatedAfter; v != nil {
+			where = append(where, "created_time >= ?")
+			args = append(args, *v)
+		}
+
+		if v := find.CreatedBefore; v != nil {
+			where = append(where, "created_time < ?")
+			args = append(args, *v)
+		}
+
+		if v := find.BeforeID; v != nil {
+			where = append(where, "id < ?")
+			args = append(args, *v)
+		}
+	}
+
+	stmt := `SELECT id, created_time, actor_id, organization_id, method, resource_type, resource_id, changes, client_ip, outcome, error_message
+        FROM audit_events WHERE ` + strings.Join(where, " AND ") + " ORDER BY id DESC"
+
+	if find != nil && find.Limit != nil {
+		stmt += " LIMIT ?"
+		args = append(args, *find.Limit)
+	}
+
+	rows, err := d.conn(ctx).QueryContext(ctx, stmt, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []*store.AuditEvent
+	for rows.Next() {
+		var event store.AuditEvent
+		var actorId, organizationId sql.NullInt64
+		var changes string
+		if err := rows.Scan(&event.ID, &event.CreatedTime, &actorId, &organizationId, &event.Method, &event.ResourceType,
+			&event.ResourceID, &changes, &event.ClientIP, &event.Outcome, &event.ErrorMessage); err != nil {
+			return nil, err
+		}
+
+		if actorId.Valid {
+			event.ActorID = &actorId.Int64
+		}
+		if organizationId.Valid {
+			event.OrganizationID = &organizationId.Int64
+		}
+
+		if err := json.Unmarshal([]byte(changes), &event.Changes); err != nil {
+			return nil, err
+		}
+
+		events = append(events, &event)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
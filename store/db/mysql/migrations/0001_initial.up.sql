@@ -0,0 +1,144 @@
+CREATE TABLE organizations (
+        id BIGINT NOT NULL AUTO_INCREMENT PRIMARY KEY,
+        name VARCHAR(255) COLLATE utf8mb4_bin NOT NULL UNIQUE,
+        created_time DATETIME(6) NOT NULL
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4 COLLATE = utf8mb4_unicode_ci;
+
+-- Calls without an organization act on the Default organization.
+INSERT INTO organizations (name, created_time) VALUES ('Default', CURRENT_TIMESTAMP(6));
+
+CREATE TABLE products (
+        id BIGINT NOT NULL AUTO_INCREMENT PRIMARY KEY,
+        organization_id BIGINT NOT NULL,
+        name VARCHAR(255) NOT NULL,
+        description TEXT NOT NULL,
+        price DECIMAL(12, 2) NOT NULL,
+        cover TEXT NOT NULL,
+        created_at DATETIME(6) NOT NULL,
+        updated_at DATETIME(6) NOT NULL,
+        INDEX idx_products_organization_id (organization_id),
+        FULLTEXT INDEX idx_products_search (name, description),
+        FOREIGN KEY (organization_id) REFERENCES organizations (id) ON DELETE CASCADE
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4 COLLATE = utf8mb4_unicode_ci;
+
+CREATE TABLE users (
+        id BIGINT NOT NULL AUTO_INCREMENT PRIMARY KEY,
+        username VARCHAR(255) COLLATE utf8mb4_bin NOT NULL UNIQUE,
+        password_hash VARCHAR(255) NOT NULL,
+        role VARCHAR(32) NOT NULL,
+        disabled BOOLEAN NOT NULL DEFAULT FALSE
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4 COLLATE = utf8mb4_unicode_ci;
+
+CREATE TABLE memberships (
+        organization_id BIGINT NOT NULL,
+        user_id BIGINT NOT NULL,
+        role VARCHAR(32) NOT NULL,
+        created_time DATETIME(6) NOT NULL,
+        PRIMARY KEY (organization_id, user_id),
+        FOREIGN KEY (organization_id) REFERENCES organizations (id) ON DELETE CASCADE,
+        FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4 COLLATE = utf8mb4_unicode_ci;
+
+CREATE TABLE sessions (
+        user_id BIGINT NOT NULL,
+        session_id CHAR(36) NOT NULL PRIMARY KEY,
+        created_time DATETIME(6) NOT NULL,
+        last_accessed_time DATETIME(6) NOT NULL,
+        expires_at DATETIME(6) NOT NULL,
+        user_agent TEXT NOT NULL,
+        client_ip VARCHAR(64) NOT NULL DEFAULT '',
+        FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4 COLLATE = utf8mb4_unicode_ci;
+
+CREATE TABLE access_tokens (
+        id BIGINT NOT NULL AUTO_INCREMENT PRIMARY KEY,
+        user_id BIGINT NOT NULL,
+        name VARCHAR(255) NOT NULL,
+        token_hash CHAR(64) NOT NULL UNIQUE,
+        scopes VARCHAR(255) NOT NULL,
+        created_time DATETIME(6) NOT NULL,
+        expires_at DATETIME(6),
+        last_used_time DATETIME(6),
+        FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4 COLLATE = utf8mb4_unicode_ci;
+
+CREATE TABLE user_identities (
+        user_id BIGINT NOT NULL,
+        issuer VARCHAR(255) COLLATE utf8mb4_bin NOT NULL,
+        subject VARCHAR(255) COLLATE utf8mb4_bin NOT NULL,
+        created_time DATETIME(6) NOT NULL,
+        PRIMARY KEY (issuer, subject),
+        FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4 COLLATE = utf8mb4_unicode_ci;
+
+CREATE TABLE user_totp (
+        user_id BIGINT NOT NULL PRIMARY KEY,
+        secret VARCHAR(255) NOT NULL,
+        enabled BOOLEAN NOT NULL DEFAULT FALSE,
+        last_used_step BIGINT NOT NULL DEFAULT 0,
+        created_time DATETIME(6) NOT NULL,
+        FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4 COLLATE = utf8mb4_unicode_ci;
+
+CREATE TABLE recovery_codes (
+        code_hash CHAR(64) NOT NULL,
+        user_id BIGINT NOT NULL,
+        used_time DATETIME(6),
+        PRIMARY KEY (user_id, code_hash),
+        FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4 COLLATE = utf8mb4_unicode_ci;
+
+CREATE TABLE second_factor_challenges (
+        token_hash CHAR(64) NOT NULL PRIMARY KEY,
+        user_id BIGINT NOT NULL,
+        remember_me BOOLEAN NOT NULL DEFAULT FALSE,
+        attempts INT NOT NULL DEFAULT 0,
+        created_time DATETIME(6) NOT NULL,
+        expires_time DATETIME(6) NOT NULL,
+        FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4 COLLATE = utf8mb4_unicode_ci;
+
+CREATE TABLE sign_in_throttles (
+        throttle_key VARCHAR(255) COLLATE utf8mb4_bin NOT NULL PRIMARY KEY,
+        failures INT NOT NULL,
+        last_failure_time DATETIME(6) NOT NULL,
+        locked_until DATETIME(6)
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4 COLLATE = utf8mb4_unicode_ci;
+
+CREATE TABLE invitations (
+        id BIGINT NOT NULL AUTO_INCREMENT PRIMARY KEY,
+        code_hash CHAR(64) NOT NULL UNIQUE,
+        role VARCHAR(32) NOT NULL,
+        created_by BIGINT,
+        created_time DATETIME(6) NOT NULL,
+        expires_time DATETIME(6) NOT NULL,
+        used_time DATETIME(6),
+        used_by BIGINT,
+        FOREIGN KEY (created_by) REFERENCES users (id) ON DELETE SET NULL,
+        FOREIGN KEY (used_by) REFERENCES users (id) ON DELETE SET NULL
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4 COLLATE = utf8mb4_unicode_ci;
+
+CREATE TABLE password_reset_tokens (
+        token_hash CHAR(64) NOT NULL PRIMARY KEY,
+        user_id BIGINT NOT NULL,
+        created_time DATETIME(6) NOT NULL,
+        expires_time DATETIME(6) NOT NULL,
+        used_time DATETIME(6),
+        FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4 COLLATE = utf8mb4_unicode_ci;
+
+CREATE TABLE audit_events (
+        id BIGINT NOT NULL AUTO_INCREMENT PRIMARY KEY,
+        created_time DATETIME(6) NOT NULL,
+        actor_id BIGINT,
+        organization_id BIGINT,
+        method VARCHAR(255) NOT NULL,
+        resource_type VARCHAR(64) NOT NULL DEFAULT '',
+        resource_id VARCHAR(255) NOT NULL DEFAULT '',
+        changes LONGTEXT NOT NULL,
+        client_ip VARCHAR(64) NOT NULL DEFAULT '',
+        outcome VARCHAR(32) NOT NULL,
+        error_message TEXT NOT NULL,
+        INDEX idx_audit_events_actor_id (actor_id),
+        INDEX idx_audit_events_resource (resource_type, resource_id)
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4 COLLATE = utf8mb4_unicode_ci;
@@ -0,0 +1,88 @@
+package mysql
+
+import (
+	"database/sql"
+	"embed"
+	"errors"
+	"io/fs"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+
+	"github.com/thetnaingtn/dirty-hand/internal/config"
+	"github.com/thetnaingtn/dirty-hand/store/migrate"
+)
+
+var ErrNoDatabaseURL = errors.New("no database URL provided")
+
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+type DB struct {
+	db     *sql.DB
+	config *config.Config
+
+	migrator *migrate.Migrator
+}
+
+func NewDB(cfg *config.Config) (*DB, error) {
+	if cfg.Database.DSN == "" {
+		return nil, ErrNoDatabaseURL
+	}
+
+	mysqlConfig, err := mysql.ParseDSN(cfg.Database.DSN)
+	if err != nil {
+		return nil, err
+	}
+
+	// Timestamps are stored in UTC and scanned into time.Time, updates
+	// report the rows they matched like the other databases do, and the
+	// migrations run several statements at once.
+	mysqlConfig.ParseTime = true
+	mysqlConfig.Loc = time.UTC
+	mysqlConfig.ClientFoundRows = true
+	mysqlConfig.MultiStatements = true
+
+	connector, err := mysql.NewConnector(mysqlConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	db := sql.OpenDB(connector)
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	migrations, err := loadMigrations()
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	// MySQL commits implicitly after DDL statements, so unlike the other
+	// drivers a failed migration may leave its earlier statements applied.
+	return &DB{
+		db:       db,
+		config:   cfg,
+		migrator: migrate.New(db, migrate.QuestionMarkDialect, migrations),
+	}, nil
+}
+
+func loadMigrations() ([]*migrate.Migration, error) {
+	dir, err := fs.Sub(migrationFiles, "migrations")
+	if err != nil {
+		return nil, err
+	}
+
+	return migrate.Load(dir)
+}
+
+// Migrator returns the migrator of the MySQL schema.
+func (d *DB) Migrator() *migrate.Migrator {
+	return d.migrator
+}
+
+func (d *DB) Close() error {
+	return d.db.Close()
+}
@@ -0,0 +1,66 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+
+	"github.com/thetnaingtn/dirty-hand/internal/config"
+	"github.com/thetnaingtn/dirty-hand/store"
+	"github.com/thetnaingtn/dirty-hand/store/storetest"
+)
+
+// The tests run against the server of MYSQL_TEST_DSN and are skipped when it
+// is not set. The user of the DSN needs to be able to create databases, as
+// every test gets its own.
+
+// newTestDB returns a migrated database of its own.
+func newTestDB(t *testing.T) *DB {
+	t.Helper()
+
+	dsn := os.Getenv("MYSQL_TEST_DSN")
+	if dsn == "" {
+		t.Skip("MYSQL_TEST_DSN is not set")
+	}
+
+	mysqlConfig, err := mysql.ParseDSN(dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	admin, err := sql.Open("mysql", dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { admin.Close() })
+
+	mysqlConfig.DBName = fmt.Sprintf("test_%d", time.Now().UnixNano())
+	if _, err := admin.Exec("CREATE DATABASE " + mysqlConfig.DBName + " CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci"); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { admin.Exec("DROP DATABASE " + mysqlConfig.DBName) })
+
+	db, err := NewDB(&config.Config{Database: config.DatabaseConfig{
+		Driver: config.DatabaseMySQL,
+		DSN:    mysqlConfig.FormatDSN(),
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	if _, err := db.Migrator().Up(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	return db
+}
+
+func TestDriver(t *testing.T) {
+	storetest.Run(t, func(t *testing.T) store.Driver { return newTestDB(t) })
+}
@@ -0,0 +1,328 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/thetnaingtn/dirty-hand/store"
+)
+
+func (d *DB) CreateProduct(ctx context.Context, p *store.Product) (*store.Product, error) {
+	res, err := d.conn(ctx).ExecContext(ctx, `INSERT INTO products (organization_id, name, description, price, cover, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		p.OrganizationID, p.Name, p.Description, p.Price, p.Cover, p.CreatedAt, p.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return nil, err
+	}
+	p.ID = id
+	return p, nil
+}
+
+func (d *DB) UpdateProduct(ctx context.Context, p *store.Product) (*store.Product, error) {
+	res, err := d.conn(ctx).ExecContext(ctx, `UPDATE products SET name=?, description=?, price=?, cover=?, updated_at=? WHERE id=? AND organization_id=?`,
+		p.Name, p.Description, p.Price, p.Cover, p.UpdatedAt, p.ID, p.OrganizationID)
+	if err != nil {
+		return nil, err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+	if affected == 0 {
+		return nil, nil
+	}
+	return d.GetProduct(ctx, p.OrganizationID, p.ID)
+}
+
+func (d *DB) GetProduct(ctx context.Context, organizationId, id int64) (*store.Product, error) {
+	var p store.Product
+	err := d.conn(ctx).QueryRowContext(ctx, `SELECT id, organization_id, name, description, price, cover, created_at, updated_at FROM products WHERE id = ? AND organization_id = ?`, id, organizationId).
+		Scan(&p.ID, &p.OrganizationID, &p.Name, &p.Description, &p.Price, &p.Cover, &p.CreatedAt, &p.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &p, nil
+}
+
+func (d *DB) ListProducts(ctx context.Context, find *store.FindProduct) ([]*store.Product, error) {
+	where, args := productFilterClause(find)
+
+	orderBy := store.ProductOrderByID
+	descending := false
+	if find != nil {
+		if find.OrderBy != "" {
+			orderBy = find.OrderBy
+		}
+		descending = find.Descending
+	}
+
+	column, ok := productOrderColumns[orderBy]
+	if !ok {
+		return nil, fmt.Errorf("unsupported product order field %q", orderBy)
+	}
+
+	direction, cmp := "ASC", ">"
+	if descending {
+		direction, cmp = "DESC", "<"
+	}
+
+	if find != nil && find.After != nil {
+		if column == "id" {
+			where = append(where, "id "+cmp+" ?")
+			args = append(args, find.After.ID)
+		} else {
+			value := productOrderValue(find.After, orderBy)
+			where = append(where, fmt.Sprintf("(%s %s ? OR (%s = ? AND id %s ?))", column, cmp, column, cmp))
+			args = append(args, value, value, find.After.ID)
+		}
+	}
+
+	stmt := "SELECT id, organization_id, name, description, price, cover, created_at, updated_at FROM products WHERE " + strings.Join(where, " AND ")
+	if column == "id" {
+		stmt += " ORDER BY id " + direction
+	} else {
+		stmt += fmt.Sprintf(" ORDER BY %s %s, id %s", column, direction, direction)
+	}
+
+	if find != nil && find.Limit != nil {
+		stmt += " LIMIT ?"
+		args = append(args, *find.Limit)
+	}
+
+	rows, err := d.conn(ctx).QueryContext(ctx, stmt, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var products []*store.Product
+	for rows.Next() {
+		var p store.Product
+		if err := rows.Scan(&p.ID, &p.OrganizationID, &p.Name, &p.Description, &p.Price, &p.Cover, &p.CreatedAt, &p.UpdatedAt); err != nil {
+			return nil, err
+		}
+		products = append(products, &p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return products, nil
+}
+
+func (d *DB) CountProducts(ctx context.Context, find *store.FindProduct) (int64, error) {
+	where, args := productFilterClause(find)
+
+	var count int64
+	stmt := "SELECT COUNT(*) FROM products WHERE " + strings.Join(where, " AND ")
+	if err := d.conn(ctx).QueryRowContext(ctx, stmt, args...).Scan(&count); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+var productOrderColumns = map[store.ProductOrderField]string{
+	store.ProductOrderByID:        "id",
+	store.ProductOrderByName:      "name",
+	store.ProductOrderByPrice:     "price",
+	store.ProductOrderByCreatedAt: "created_at",
+	store.ProductOrderByUpdatedAt: "updated_at",
+}
+
+func productOrderValue(p *store.Product, field store.ProductOrderField) any {
+	switch field {
+	case store.ProductOrderByName:
+		return p.Name
+	case store.ProductOrderByPrice:
+		return p.Price
+	case store.ProductOrderByCreatedAt:
+		return p.CreatedAt
+	case store.ProductOrderByUpdatedAt:
+		return p.UpdatedAt
+	default:
+		return p.ID
+	}
+}
+
+// productFilterClause builds the WHERE conditions shared by ListProducts and
+// CountProducts. The cursor and limit of find are not taken into account.
+func productFilterClause(find *store.FindProduct) ([]string, []any) {
+	where, args := []string{"1 = 1"}, []any{}
+	if find == nil {
+		return where, args
+	}
+
+	if v := find.OrganizationID; v != nil {
+		where = append(where, "organization_id = ?")
+		args = append(args, *v)
+	}
+	if v := find.Name; v != nil && *v != "" {
+		where = append(where, "name LIKE ?")
+		args = append(args, "%"+escapeLike(*v)+"%")
+	}
+	if v := find.MinPrice; v != nil {
+		where = append(where, "price >= ?")
+		args = append(args, *v)
+	}
+	if v := find.MaxPrice; v != nil {
+		where = append(where, "price <= ?")
+		args = append(args, *v)
+	}
+	if v := find.CreatedAfter; v != nil {
+		where = append(where, "created_at >= ?")
+		args = append(args, *v)
+	}
+	if v := find.CreatedBefore; v != nil {
+		where = append(where, "created_at < ?")
+		args = append(args, *v)
+	}
+	if v := find.UpdatedAfter; v != nil {
+		where = append(where, "updated_at >= ?")
+		args = append(args, *v)
+	}
+	if v := find.UpdatedBefore; v != nil {
+		where = append(where, "updated_at < ?")
+		args = append(args, *v)
+	}
+
+	return where, args
+}
+
+// escapeLike escapes the LIKE wildcards in s so it is matched literally.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
+
+func (d *DB) SearchProducts(ctx context.Context, search *store.SearchProduct) ([]*store.ProductSearchResult, error) {
+	words := searchWords(search.Terms)
+	if len(words) == 0 {
+		return nil, nil
+	}
+
+	query := "+" + strings.Join(words, "* +") + "*"
+
+	stmt := `SELECT id, organization_id, name, description, price, cover, created_at, updated_at,
+                MATCH (name, description) AGAINST (? IN BOOLEAN MODE) AS score
+        FROM products
+        WHERE MATCH (name, description) AGAINST (? IN BOOLEAN MODE)`
+	args := []any{query, query}
+
+	if search.OrganizationID != nil {
+		stmt += " AND organization_id = ?"
+		args = append(args, *search.OrganizationID)
+	}
+
+	stmt += " ORDER BY score DESC, id"
+
+	if search.Limit != nil {
+		stmt += " LIMIT ?"
+		args = append(args, *search.Limit)
+	}
+
+	rows, err := d.conn(ctx).QueryContext(ctx, stmt, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []*store.ProductSearchResult
+	for rows.Next() {
+		var p store.Product
+		var result store.ProductSearchResult
+		if err := rows.Scan(&p.ID, &p.OrganizationID, &p.Name, &p.Description, &p.Price, &p.Cover, &p.CreatedAt, &p.UpdatedAt, &result.Score); err != nil {
+			return nil, err
+		}
+		result.Product = &p
+		// MySQL has no highlighting functions, so matches are marked here.
+		result.NameHighlight = markWords(strings.Fields(p.Name), words)
+		result.DescriptionSnippet = snippet(p.Description, words, snippetWords)
+		results = append(results, &result)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// searchWords splits search terms into the lowercase words of a full-text
+// query. Everything else is dropped so boolean mode operators in user input
+// have no effect.
+func searchWords(terms []string) []string {
+	var words []string
+	for _, term := range terms {
+		words = append(words, strings.FieldsFunc(strings.ToLower(term), isNotWordRune)...)
+	}
+	return words
+}
+
+func isNotWordRune(r rune) bool {
+	return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+}
+
+// matchesWord reports whether a word of a text starts with one of the
+// search words.
+func matchesWord(word string, words []string) bool {
+	word = strings.ToLower(strings.TrimFunc(word, isNotWordRune))
+	for _, prefix := range words {
+		if strings.HasPrefix(word, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// markWords joins the words of a text back together, wrapping the ones that
+// match in <mark></mark>.
+func markWords(fields []string, words []string) string {
+	marked := make([]string, len(fields))
+	for i, field := range fields {
+		if matchesWord(field, words) {
+			field = "<mark>" + field + "</mark>"
+		}
+		marked[i] = field
+	}
+	return strings.Join(marked, " ")
+}
+
+// snippetWords is the length of a description snippet in words.
+const snippetWords = 16
+
+// snippet returns up to size words of text around its first match, with the
+// matches marked and an ellipsis where text was cut off.
+func snippet(text string, words []string, size int) string {
+	fields := strings.Fields(text)
+
+	first := 0
+	for i, field := range fields {
+		if matchesWord(field, words) {
+			first = i
+			break
+		}
+	}
+
+	start := max(0, min(first-size/4, len(fields)-size))
+	end := min(len(fields), start+size)
+
+	result := markWords(fields[start:end], words)
+	if start > 0 {
+		result = "…" + result
+	}
+	if end < len(fields) {
+		result += "…"
+	}
+	return result
+}
+
+func (d *DB) DeleteProduct(ctx context.Context, organizationId, id int64) error {
+	_, err := d.conn(ctx).ExecContext(ctx, `DELETE FROM products WHERE id = ? AND organization_id = ?`, id, organizationId)
+	return err
+}
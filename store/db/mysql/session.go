@@ -0,0 +1,99 @@
+package mysql
+
+import (
+	"context"
+	"time"
+
+	"github.com/thetnaingtn/dirty-hand/store"
+)
+
+func (d *DB) CreateSession(ctx context.Context, session *store.Session) (*store.Session, error) {
+	query := `INSERT INTO sessions (user_id, session_id, created_time, last_accessed_time, expires_at, user_agent, client_ip) VALUES (?, ?, ?, ?, ?, ?, ?)`
+
+	if _, err := d.conn(ctx).ExecContext(ctx, query, session.UserID, session.SessionID, session.CreatedTime, session.LastAccessedTime, session.ExpiresAt, session.UserAgent, session.ClientIP); err != nil {
+		return nil, err
+	}
+
+	return session, nil
+}
+
+func (d *DB) GetUserSessions(ctx context.Context, userId int64) ([]*store.Session, error) {
+	query := `SELECT session_id, user_id, created_time, last_accessed_time, expires_at, user_agent, client_ip FROM sessions WHERE user_id = ? ORDER BY created_time`
+
+	rows, err := d.conn(ctx).QueryContext(ctx, query, userId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var sessions []*store.Session
+	for rows.Next() {
+		session := &store.Session{}
+		err := rows.Scan(&session.SessionID, &session.UserID, &session.CreatedTime, &session.LastAccessedTime, &session.ExpiresAt, &session.UserAgent, &session.ClientIP)
+		if err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, session)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return sessions, nil
+}
+
+func (d *DB) DeleteSession(ctx context.Context, sessionId string) error {
+	query := `DELETE FROM sessions WHERE session_id = ?`
+
+	_, err := d.conn(ctx).ExecContext(ctx, query, sessionId)
+	return err
+}
+
+func (d *DB) DeleteUserSessions(ctx context.Context, userId int64, exceptSessionId string) error {
+	query := `DELETE FROM sessions WHERE user_id = ? AND session_id != ?`
+
+	_, err := d.conn(ctx).ExecContext(ctx, query, userId, exceptSessionId)
+	return err
+}
+
+func (d *DB) DeleteExpiredSessions(ctx context.Context, lastAccessedBefore, expiresBefore time.Time) ([]int64, error) {
+	var userIds []int64
+	err := d.WithTx(ctx, func(ctx context.Context) error {
+		userIds = nil
+		query := `SELECT user_id FROM sessions WHERE last_accessed_time < ? OR expires_at < ? FOR UPDATE`
+
+		rows, err := d.conn(ctx).QueryContext(ctx, query, lastAccessedBefore, expiresBefore)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var userId int64
+			if err := rows.Scan(&userId); err != nil {
+				return err
+			}
+			userIds = append(userIds, userId)
+		}
+
+		if err := rows.Err(); err != nil {
+			return err
+		}
+
+		_, err = d.conn(ctx).ExecContext(ctx, `DELETE FROM sessions WHERE last_accessed_time < ? OR expires_at < ?`, lastAccessedBefore, expiresBefore)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return userIds, nil
+}
+
+func (d *DB) UpdateLastAccessedTime(ctx context.Context, sessionId string, lastAccessTime time.Time) error {
+	query := `UPDATE sessions SET last_accessed_time = ? WHERE session_id = ?`
+
+	_, err := d.conn(ctx).ExecContext(ctx, query, lastAccessTime, sessionId)
+	return err
+}
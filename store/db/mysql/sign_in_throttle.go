@@ -0,0 +1,61 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/thetnaingtn/dirty-hand/store"
+)
+
+func (d *DB) GetSignInThrottle(ctx context.Context, key string) (*store.SignInThrottle, error) {
+	query := `SELECT throttle_key, failures, last_failure_time, locked_until FROM sign_in_throttles WHERE throttle_key = ?`
+
+	var throttle store.SignInThrottle
+	var lockedUntil sql.NullTime
+	if err := d.conn(ctx).QueryRowContext(ctx, query, key).Scan(&throttle.Key, &throttle.Failures, &throttle.LastFailureTime, &lockedUntil); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	if lockedUntil.Valid {
+		throttle.LockedUntil = lockedUntil.Time
+	}
+
+	return &throttle, nil
+}
+
+func (d *DB) UpsertSignInThrottle(ctx context.Context, throttle *store.SignInThrottle) (*store.SignInThrottle, error) {
+	query := `INSERT INTO sign_in_throttles (throttle_key, failures, last_failure_time, locked_until) VALUES (?, ?, ?, ?)
+        ON DUPLICATE KEY UPDATE failures = VALUES(failures),
+                last_failure_time = VALUES(last_failure_time), locked_until = VALUES(locked_until)`
+
+	var lockedUntil sql.NullTime
+	if !throttle.LockedUntil.IsZero() {
+		lockedUntil = sql.NullTime{Time: throttle.LockedUntil, Valid: true}
+	}
+
+	if _, err := d.conn(ctx).ExecContext(ctx, query, throttle.Key, throttle.Failures, throttle.LastFailureTime, lockedUntil); err != nil {
+		return nil, err
+	}
+
+	return throttle, nil
+}
+
+func (d *DB) DeleteSignInThrottle(ctx context.Context, key string) error {
+	_, err := d.conn(ctx).ExecContext(ctx, `DELETE FROM sign_in_throttles WHERE throttle_key = ?`, key)
+	return err
+}
+
+func (d *DB) DeleteStaleSignInThrottles(ctx context.Context, lastFailureBefore, now time.Time) (int64, error) {
+	result, err := d.conn(ctx).ExecContext(ctx, `DELETE FROM sign_in_throttles
+        WHERE last_failure_time < ? AND (locked_until IS NULL OR locked_until <= ?)`, lastFailureBefore, now)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
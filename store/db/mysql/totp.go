@@ -0,0 +1,151 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/thetnaingtn/dirty-hand/store"
+)
+
+func (d *DB) GetUserTOTP(ctx context.Context, userId int64) (*store.UserTOTP, error) {
+	query := `SELECT user_id, secret, enabled, last_used_step, created_time FROM user_totp WHERE user_id = ?`
+
+	var totp store.UserTOTP
+	if err := d.conn(ctx).QueryRowContext(ctx, query, userId).Scan(&totp.UserID, &totp.Secret, &totp.Enabled, &totp.LastUsedStep, &totp.CreatedTime); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &totp, nil
+}
+
+func (d *DB) UpsertUserTOTP(ctx context.Context, totp *store.UserTOTP) (*store.UserTOTP, error) {
+	query := `INSERT INTO user_totp (user_id, secret, enabled, last_used_step, created_time) VALUES (?, ?, ?, ?, ?)
+        ON DUPLICATE KEY UPDATE secret = VALUES(secret), enabled = VALUES(enabled),
+                last_used_step = VALUES(last_used_step), created_time = VALUES(created_time)`
+
+	if _, err := d.conn(ctx).ExecContext(ctx, query, totp.UserID, totp.Secret, totp.Enabled, totp.LastUsedStep, totp.CreatedTime); err != nil {
+		return nil, err
+	}
+
+	return totp, nil
+}
+
+func (d *DB) EnableUserTOTP(ctx context.Context, userId int64) error {
+	_, err := d.conn(ctx).ExecContext(ctx, `UPDATE user_totp SET enabled = 1 WHERE user_id = ?`, userId)
+	return err
+}
+
+func (d *DB) AdvanceUserTOTPStep(ctx context.Context, userId int64, step int64) (bool, error) {
+	result, err := d.conn(ctx).ExecContext(ctx, `UPDATE user_totp SET last_used_step = ? WHERE user_id = ? AND last_used_step < ?`, step, userId, step)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected > 0, nil
+}
+
+func (d *DB) DeleteUserTOTP(ctx context.Context, userId int64) error {
+	_, err := d.conn(ctx).ExecContext(ctx, `DELETE FROM user_totp WHERE user_id = ?`, userId)
+	return err
+}
+
+func (d *DB) CreateRecoveryCodes(ctx context.Context, userId int64, codeHashes []string) error {
+	for _, codeHash := range codeHashes {
+		if _, err := d.conn(ctx).ExecContext(ctx, `INSERT INTO recovery_codes (code_hash, user_id) VALUES (?, ?)`, codeHash, userId); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (d *DB) ConsumeRecoveryCode(ctx context.Context, userId int64, codeHash string, now time.Time) (bool, error) {
+	result, err := d.conn(ctx).ExecContext(ctx, `UPDATE recovery_codes SET used_time = ? WHERE user_id = ? AND code_hash = ? AND used_time IS NULL`, now, userId, codeHash)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected > 0, nil
+}
+
+func (d *DB) DeleteRecoveryCodes(ctx context.Context, userId int64) error {
+	_, err := d.conn(ctx).ExecContext(ctx, `DELETE FROM recovery_codes WHERE user_id = ?`, userId)
+	return err
+}
+
+func (d *DB) CreateSecondFactorChallenge(ctx context.Context, challenge *store.SecondFactorChallenge) (*store.SecondFactorChallenge, error) {
+	query := `INSERT INTO second_factor_challenges (token_hash, user_id, remember_me, attempts, created_time, expires_time) VALUES (?, ?, ?, ?, ?, ?)`
+
+	if _, err := d.conn(ctx).ExecContext(ctx, query, challenge.TokenHash, challenge.UserID, challenge.RememberMe, challenge.Attempts, challenge.CreatedTime, challenge.ExpiresTime); err != nil {
+		return nil, err
+	}
+
+	return challenge, nil
+}
+
+func (d *DB) GetSecondFactorChallenge(ctx context.Context, tokenHash string, now time.Time) (*store.SecondFactorChallenge, error) {
+	query := `SELECT token_hash, user_id, remember_me, attempts, created_time, expires_time FROM second_factor_challenges
+        WHERE token_hash = ? AND expires_time > ?`
+
+	var challenge store.SecondFactorChallenge
+	if err := d.conn(ctx).QueryRowContext(ctx, query, tokenHash, now).Scan(&challenge.TokenHash, &challenge.UserID, &challenge.RememberMe, &challenge.Attempts, &challenge.CreatedTime, &challenge.ExpiresTime); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &challenge, nil
+}
+
+func (d *DB) IncrementSecondFactorChallengeAttempts(ctx context.Context, tokenHash string) error {
+	_, err := d.conn(ctx).ExecContext(ctx, `UPDATE second_factor_challenges SET attempts = attempts + 1 WHERE token_hash = ?`, tokenHash)
+	return err
+}
+
+func (d *DB) ConsumeSecondFactorChallenge(ctx context.Context, tokenHash string, now time.Time) (*store.SecondFactorChallenge, error) {
+	var challenge store.SecondFactorChallenge
+	err := d.WithTx(ctx, func(ctx context.Context) error {
+		query := `SELECT token_hash, user_id, remember_me, attempts, created_time, expires_time FROM second_factor_challenges
+        WHERE token_hash = ? AND expires_time > ? FOR UPDATE`
+
+		if err := d.conn(ctx).QueryRowContext(ctx, query, tokenHash, now).Scan(&challenge.TokenHash, &challenge.UserID, &challenge.RememberMe, &challenge.Attempts, &challenge.CreatedTime, &challenge.ExpiresTime); err != nil {
+			return err
+		}
+
+		_, err := d.conn(ctx).ExecContext(ctx, `DELETE FROM second_factor_challenges WHERE token_hash = ?`, tokenHash)
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &challenge, nil
+}
+
+func (d *DB) DeleteExpiredSecondFactorChallenges(ctx context.Context, before time.Time) (int64, error) {
+	result, err := d.conn(ctx).ExecContext(ctx, `DELETE FROM second_factor_challenges WHERE expires_time <= ?`, before)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
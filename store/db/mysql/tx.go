@@ -0,0 +1,104 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"math/rand/v2"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+const (
+	// maxTxAttempts bounds how often WithTx runs a transaction that failed
+	// because it conflicted with another one.
+	maxTxAttempts = 10
+	txRetryDelay  = 20 * time.Millisecond
+)
+
+// MySQL error numbers of transactions that may succeed when run again.
+const (
+	errLockWaitTimeout = 1205
+	errDeadlock        = 1213
+)
+
+// txContextKey is the context key of the transaction WithTx started on db.
+type txContextKey struct {
+	db *DB
+}
+
+// querier runs statements on the database or in a transaction.
+type querier interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// WithTx runs fn in a serializable transaction, which is committed when fn
+// returns nil and rolled back otherwise. Calls made with the context passed
+// to fn run in the transaction, and nested calls join it. fn is run again
+// when InnoDB aborts the transaction because of a deadlock or a lock wait
+// timeout.
+//
+// The driver also uses it, with SELECT ... FOR UPDATE, in place of the
+// RETURNING clauses that MySQL lacks.
+func (d *DB) WithTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txContextKey{d}).(*sql.Tx); ok {
+		return fn(ctx)
+	}
+
+	for attempt := 1; ; attempt++ {
+		err := d.runTx(ctx, fn)
+		if err == nil || attempt == maxTxAttempts || !isRetryable(err) {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(retryDelay(attempt)):
+		}
+	}
+}
+
+// retryDelay returns how long to wait before running a transaction again.
+// The delay grows with the attempts and is jittered so that transactions
+// that conflicted do not conflict again.
+func retryDelay(attempt int) time.Duration {
+	return time.Duration(attempt)*txRetryDelay/2 + rand.N(time.Duration(attempt)*txRetryDelay)
+}
+
+func (d *DB) runTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	tx, err := d.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelSerializable})
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := fn(context.WithValue(ctx, txContextKey{d}, tx)); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// conn returns the transaction WithTx started for ctx, or the database when
+// there is none.
+func (d *DB) conn(ctx context.Context) querier {
+	if tx, ok := ctx.Value(txContextKey{d}).(*sql.Tx); ok {
+		return tx
+	}
+
+	return d.db
+}
+
+// isRetryable reports whether err is a deadlock or a lock wait timeout.
+func isRetryable(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	if !errors.As(err, &mysqlErr) {
+		return false
+	}
+
+	return mysqlErr.Number == errDeadlock || mysqlErr.Number == errLockWaitTimeout
+}
@@ -0,0 +1,152 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+
+	"github.com/thetnaingtn/dirty-hand/store"
+)
+
+func (d *DB) CreateUser(ctx context.Context, create *store.User) (*store.User, error) {
+	fields := []string{"username", "password_hash", "role"}
+	placeholders := []string{"?", "?", "?"}
+	values := []any{create.Username, create.PasswordHash, create.Role}
+
+	stmt := "INSERT INTO users (" + strings.Join(fields, ", ") + ") VALUES (" + strings.Join(placeholders, ", ") + ")"
+
+	result, err := d.conn(ctx).ExecContext(ctx, stmt, values...)
+	if err != nil {
+		return nil, err
+	}
+
+	if create.ID, err = result.LastInsertId(); err != nil {
+		return nil, err
+	}
+
+	return create, nil
+}
+
+func (d *DB) ListUsers(ctx context.Context, filter *store.FindUser) ([]store.User, error) {
+	var users []store.User
+
+	stmt := "SELECT id, username, password_hash, role, disabled FROM users WHERE 1=1"
+	var args []any
+
+	if filter != nil {
+		if filter.Role != nil {
+			stmt += " AND role = ?"
+			args = append(args, *filter.Role)
+		}
+
+		if filter.ID != nil {
+			stmt += " AND id = ?"
+			args = append(args, *filter.ID)
+		}
+
+		if filter.Username != nil {
+			stmt += " AND username = ?"
+			args = append(args, *filter.Username)
+		}
+
+		if filter.AfterID != nil {
+			stmt += " AND id > ?"
+			args = append(args, *filter.AfterID)
+		}
+	}
+
+	stmt += " ORDER BY id"
+
+	if filter != nil && filter.Limit != nil {
+		stmt += " LIMIT ?"
+		args = append(args, *filter.Limit)
+	}
+
+	rows, err := d.conn(ctx).QueryContext(ctx, stmt, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var user store.User
+		if err := rows.Scan(&user.ID, &user.Username, &user.PasswordHash, &user.Role, &user.Disabled); err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return users, nil
+}
+
+func (d *DB) GetUser(ctx context.Context, filter *store.FindUser) (*store.User, error) {
+	var user store.User
+	where, args := []string{"1 = 1"}, []any{}
+
+	if filter != nil {
+		if v := filter.Role; v != nil {
+			where = append(where, "role = ?")
+			args = append(args, *v)
+		}
+
+		if v := filter.Username; v != nil {
+			where = append(where, "username = ?")
+			args = append(args, *v)
+		}
+
+		if v := filter.ID; v != nil {
+			where = append(where, "id = ?")
+			args = append(args, *v)
+		}
+	}
+
+	stmt := "SELECT id, username, password_hash, role, disabled FROM users WHERE " + strings.Join(where, " AND ")
+
+	if err := d.conn(ctx).QueryRowContext(ctx, stmt, args...).Scan(&user.ID, &user.Username, &user.PasswordHash, &user.Role, &user.Disabled); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+func (d *DB) UpdateUser(ctx context.Context, update *store.UpdateUser) (*store.User, error) {
+	set, args := []string{}, []any{}
+
+	if v := update.PasswordHash; v != nil {
+		set = append(set, "password_hash = ?")
+		args = append(args, *v)
+	}
+
+	if v := update.Role; v != nil {
+		set = append(set, "role = ?")
+		args = append(args, *v)
+	}
+
+	if v := update.Disabled; v != nil {
+		set = append(set, "disabled = ?")
+		args = append(args, *v)
+	}
+
+	if len(set) > 0 {
+		args = append(args, update.ID)
+		stmt := "UPDATE users SET " + strings.Join(set, ", ") + " WHERE id = ?"
+		if _, err := d.conn(ctx).ExecContext(ctx, stmt, args...); err != nil {
+			return nil, err
+		}
+	}
+
+	return d.GetUser(ctx, &store.FindUser{ID: &update.ID})
+}
+
+func (d *DB) DeleteUser(ctx context.Context, id int64) error {
+	_, err := d.conn(ctx).ExecContext(ctx, `DELETE FROM users WHERE id = ?`, id)
+	return err
+}
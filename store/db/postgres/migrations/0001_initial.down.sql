@@ -0,0 +1,14 @@
+DROP TABLE audit_events;
+DROP TABLE password_reset_tokens;
+DROP TABLE invitations;
+DROP TABLE sign_in_throttles;
+DROP TABLE second_factor_challenges;
+DROP TABLE recovery_codes;
+DROP TABLE user_totp;
+DROP TABLE user_identities;
+DROP TABLE access_tokens;
+DROP TABLE sessions;
+DROP TABLE memberships;
+DROP TABLE users;
+DROP TABLE products;
+DROP TABLE organizations;
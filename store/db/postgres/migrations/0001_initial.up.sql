@@ -0,0 +1,141 @@
+CREATE TABLE organizations (
+        id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+        name TEXT NOT NULL UNIQUE,
+        created_time TIMESTAMPTZ NOT NULL
+);
+
+-- Calls without an organization act on the Default organization.
+INSERT INTO organizations (name, created_time) VALUES ('Default', CURRENT_TIMESTAMP);
+
+CREATE TABLE products (
+        id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+        organization_id BIGINT NOT NULL REFERENCES organizations (id) ON DELETE CASCADE,
+        name TEXT NOT NULL,
+        description TEXT NOT NULL DEFAULT '',
+        price NUMERIC(12, 2) NOT NULL,
+        cover TEXT NOT NULL DEFAULT '',
+        created_at TIMESTAMPTZ NOT NULL,
+        updated_at TIMESTAMPTZ NOT NULL,
+        search TSVECTOR GENERATED ALWAYS AS (
+                setweight(to_tsvector('simple', name), 'A') || setweight(to_tsvector('simple', description), 'B')
+        ) STORED
+);
+
+CREATE INDEX idx_products_organization_id ON products (organization_id);
+CREATE INDEX idx_products_search ON products USING GIN (search);
+
+CREATE TABLE users (
+        id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+        username TEXT NOT NULL UNIQUE,
+        password_hash TEXT NOT NULL,
+        role TEXT NOT NULL,
+        disabled BOOLEAN NOT NULL DEFAULT FALSE
+);
+
+CREATE TABLE memberships (
+        organization_id BIGINT NOT NULL REFERENCES organizations (id) ON DELETE CASCADE,
+        user_id BIGINT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+        role TEXT NOT NULL,
+        created_time TIMESTAMPTZ NOT NULL,
+        PRIMARY KEY (organization_id, user_id)
+);
+
+CREATE INDEX idx_memberships_user_id ON memberships (user_id);
+
+CREATE TABLE sessions (
+        user_id BIGINT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+        session_id CHAR(36) NOT NULL PRIMARY KEY,
+        created_time TIMESTAMPTZ NOT NULL,
+        last_accessed_time TIMESTAMPTZ NOT NULL,
+        expires_at TIMESTAMPTZ NOT NULL,
+        user_agent TEXT NOT NULL DEFAULT '',
+        client_ip TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX idx_sessions_user_id ON sessions (user_id);
+
+CREATE TABLE access_tokens (
+        id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+        user_id BIGINT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+        name TEXT NOT NULL,
+        token_hash CHAR(64) NOT NULL UNIQUE,
+        scopes TEXT NOT NULL,
+        created_time TIMESTAMPTZ NOT NULL,
+        expires_at TIMESTAMPTZ,
+        last_used_time TIMESTAMPTZ
+);
+
+CREATE TABLE user_identities (
+        user_id BIGINT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+        issuer TEXT NOT NULL,
+        subject TEXT NOT NULL,
+        created_time TIMESTAMPTZ NOT NULL,
+        PRIMARY KEY (issuer, subject)
+);
+
+CREATE TABLE user_totp (
+        user_id BIGINT NOT NULL PRIMARY KEY REFERENCES users (id) ON DELETE CASCADE,
+        secret TEXT NOT NULL,
+        enabled BOOLEAN NOT NULL DEFAULT FALSE,
+        last_used_step BIGINT NOT NULL DEFAULT 0,
+        created_time TIMESTAMPTZ NOT NULL
+);
+
+CREATE TABLE recovery_codes (
+        code_hash CHAR(64) NOT NULL,
+        user_id BIGINT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+        used_time TIMESTAMPTZ,
+        PRIMARY KEY (user_id, code_hash)
+);
+
+CREATE TABLE second_factor_challenges (
+        token_hash CHAR(64) NOT NULL PRIMARY KEY,
+        user_id BIGINT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+        remember_me BOOLEAN NOT NULL DEFAULT FALSE,
+        attempts INTEGER NOT NULL DEFAULT 0,
+        created_time TIMESTAMPTZ NOT NULL,
+        expires_time TIMESTAMPTZ NOT NULL
+);
+
+CREATE TABLE sign_in_throttles (
+        throttle_key TEXT NOT NULL PRIMARY KEY,
+        failures INTEGER NOT NULL,
+        last_failure_time TIMESTAMPTZ NOT NULL,
+        locked_until TIMESTAMPTZ
+);
+
+CREATE TABLE invitations (
+        id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+        code_hash CHAR(64) NOT NULL UNIQUE,
+        role TEXT NOT NULL,
+        created_by BIGINT REFERENCES users (id) ON DELETE SET NULL,
+        created_time TIMESTAMPTZ NOT NULL,
+        expires_time TIMESTAMPTZ NOT NULL,
+        used_time TIMESTAMPTZ,
+        used_by BIGINT REFERENCES users (id) ON DELETE SET NULL
+);
+
+CREATE TABLE password_reset_tokens (
+        token_hash CHAR(64) NOT NULL PRIMARY KEY,
+        user_id BIGINT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+        created_time TIMESTAMPTZ NOT NULL,
+        expires_time TIMESTAMPTZ NOT NULL,
+        used_time TIMESTAMPTZ
+);
+
+CREATE TABLE audit_events (
+        id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+        created_time TIMESTAMPTZ NOT NULL,
+        actor_id BIGINT,
+        organization_id BIGINT,
+        method TEXT NOT NULL,
+        resource_type TEXT NOT NULL DEFAULT '',
+        resource_id TEXT NOT NULL DEFAULT '',
+        changes JSONB NOT NULL DEFAULT '[]',
+        client_ip TEXT NOT NULL DEFAULT '',
+        outcome TEXT NOT NULL,
+        error_message TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX idx_audit_events_actor_id ON audit_events (actor_id);
+CREATE INDEX idx_audit_events_resource ON audit_events (resource_type, resource_id);
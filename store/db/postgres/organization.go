@@ -0,0 +1,126 @@
+package postgres
+
+import (
+	"context"
+	"strings"
+
+	"github.com/thetnaingtn/dirty-hand/store"
+)
+
+func (d *DB) CreateOrganization(ctx context.Context, create *store.Organization) (*store.Organization, error) {
+	stmt := `INSERT INTO organizations (name, created_time) VALUES ($1, $2) RETURNING id`
+
+	if err := d.conn(ctx).QueryRowContext(ctx, stmt, create.Name, create.CreatedTime).Scan(&create.ID); err != nil {
+		return nil, err
+	}
+
+	return create, nil
+}
+
+func (d *DB) ListOrganizations(ctx context.Context, find *store.FindOrganization) ([]*store.Organization, error) {
+	where, args := []string{"1 = 1"}, []any{}
+
+	if find != nil {
+		if v := find.ID; v != nil {
+			args = append(args, *v)
+			where = append(where, "id = "+placeholder(len(args)))
+		}
+
+		if v := find.Name; v != nil {
+			args = append(args, *v)
+			where = append(where, "name = "+placeholder(len(args)))
+		}
+
+		if v := find.MemberID; v != nil {
+			args = append(args, *v)
+			where = append(where, "id IN (SELECT organization_id FROM memberships WHERE user_id = "+placeholder(len(args))+")")
+		}
+	}
+
+	stmt := "SELECT id, name, created_time FROM organizations WHERE " + strings.Join(where, " AND ") + " ORDER BY id"
+
+	rows, err := d.conn(ctx).QueryContext(ctx, stmt, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var organizations []*store.Organization
+	for rows.Next() {
+		var organization store.Organization
+		if err := rows.Scan(&organization.ID, &organization.Name, &organization.CreatedTime); err != nil {
+			return nil, err
+		}
+
+		organizations = append(organizations, &organization)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return organizations, nil
+}
+
+func (d *DB) DeleteOrganization(ctx context.Context, id int64) error {
+	_, err := d.conn(ctx).ExecContext(ctx, `DELETE FROM organizations WHERE id = $1`, id)
+	return err
+}
+
+func (d *DB) UpsertMembership(ctx context.Context, membership *store.Membership) (*store.Membership, error) {
+	stmt := `INSERT INTO memberships (organization_id, user_id, role, created_time) VALUES ($1, $2, $3, $4)
+        ON CONFLICT (organization_id, user_id) DO UPDATE SET role = excluded.role
+        RETURNING created_time`
+
+	if err := d.conn(ctx).QueryRowContext(ctx, stmt, membership.OrganizationID, membership.UserID, membership.Role, membership.CreatedTime).
+		Scan(&membership.CreatedTime); err != nil {
+		return nil, err
+	}
+
+	return membership, nil
+}
+
+func (d *DB) ListMemberships(ctx context.Context, find *store.FindMembership) ([]*store.Membership, error) {
+	where, args := []string{"1 = 1"}, []any{}
+
+	if find != nil {
+		if v := find.OrganizationID; v != nil {
+			args = append(args, *v)
+			where = append(where, "organization_id = "+placeholder(len(args)))
+		}
+
+		if v := find.UserID; v != nil {
+			args = append(args, *v)
+			where = append(where, "user_id = "+placeholder(len(args)))
+		}
+	}
+
+	stmt := "SELECT organization_id, user_id, role, created_time FROM memberships WHERE " + strings.Join(where, " AND ") + " ORDER BY organization_id, user_id"
+
+	rows, err := d.conn(ctx).QueryContext(ctx, stmt, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var memberships []*store.Membership
+	for rows.Next() {
+		var membership store.Membership
+		if err := rows.Scan(&membership.OrganizationID, &membership.UserID, &membership.Role, &membership.CreatedTime); err != nil {
+			return nil, err
+		}
+
+		memberships = append(memberships, &membership)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return memberships, nil
+}
+
+func (d *DB) DeleteMembership(ctx context.Context, organizationId, userId int64) error {
+	_, err := d.conn(ctx).ExecContext(ctx, `DELETE FROM memberships WHERE organization_id = $1 AND user_id = $2`, organizationId, userId)
+	return err
+}
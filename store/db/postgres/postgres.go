@@ -0,0 +1,89 @@
+package postgres
+
+import (
+	"database/sql"
+	"embed"
+	"errors"
+	"io/fs"
+	"strconv"
+	"strings"
+
+	"github.com/thetnaingtn/dirty-hand/internal/config"
+	"github.com/thetnaingtn/dirty-hand/store/migrate"
+
+	_ "github.com/jackc/pgx/v5/stdlib"
+)
+
+var ErrNoDatabaseURL = errors.New("no database URL provided")
+
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+type DB struct {
+	db     *sql.DB
+	config *config.Config
+
+	migrator *migrate.Migrator
+}
+
+func NewDB(cfg *config.Config) (*DB, error) {
+	if cfg.Database.DSN == "" {
+		return nil, ErrNoDatabaseURL
+	}
+
+	db, err := sql.Open("pgx", cfg.Database.DSN)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	migrations, err := loadMigrations()
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return &DB{
+		db:       db,
+		config:   cfg,
+		migrator: migrate.New(db, migrate.Dialect{Placeholder: placeholder}, migrations),
+	}, nil
+}
+
+func loadMigrations() ([]*migrate.Migration, error) {
+	dir, err := fs.Sub(migrationFiles, "migrations")
+	if err != nil {
+		return nil, err
+	}
+
+	return migrate.Load(dir)
+}
+
+// Migrator returns the migrator of the PostgreSQL schema.
+func (d *DB) Migrator() *migrate.Migrator {
+	return d.migrator
+}
+
+func (d *DB) Close() error {
+	return d.db.Close()
+}
+
+// placeholder returns the bind parameter for the nth argument of a
+// statement, counting from 1.
+func placeholder(n int) string {
+	return "$" + strconv.Itoa(n)
+}
+
+// placeholders returns the bind parameters for the first n arguments of a
+// statement, separated by commas.
+func placeholders(n int) string {
+	list := make([]string, 0, n)
+	for i := 1; i <= n; i++ {
+		list = append(list, placeholder(i))
+	}
+	return strings.Join(list, ", ")
+}
@@ -0,0 +1,149 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/thetnaingtn/dirty-hand/internal/config"
+	"github.com/thetnaingtn/dirty-hand/store"
+	"github.com/thetnaingtn/dirty-hand/store/storetest"
+)
+
+// The tests run against the database of POSTGRES_TEST_DSN, or else against a
+// server started with the initdb and pg_ctl binaries found on the PATH. They
+// are skipped when neither is available. Every test gets its own schema.
+
+var (
+	serverOnce sync.Once
+	serverDSN  string
+	serverErr  error
+	stopServer func()
+)
+
+func TestMain(m *testing.M) {
+	code := m.Run()
+	if stopServer != nil {
+		stopServer()
+	}
+	os.Exit(code)
+}
+
+func testDSN(t *testing.T) string {
+	t.Helper()
+
+	if dsn := os.Getenv("POSTGRES_TEST_DSN"); dsn != "" {
+		return dsn
+	}
+
+	serverOnce.Do(func() { serverDSN, serverErr = startServer() })
+	if serverErr != nil {
+		t.Skipf("no PostgreSQL server available: %v", serverErr)
+	}
+
+	return serverDSN
+}
+
+func findPostgresBinary(name string) (string, error) {
+	if path, err := exec.LookPath(name); err == nil {
+		return path, nil
+	}
+
+	matches, _ := filepath.Glob(filepath.Join("/usr/lib/postgresql", "*", "bin", name))
+	if len(matches) == 0 {
+		return "", fmt.Errorf("%s not found, set POSTGRES_TEST_DSN", name)
+	}
+
+	return matches[len(matches)-1], nil
+}
+
+// startServer starts a throwaway server that only listens on a Unix socket
+// in a temporary directory.
+func startServer() (string, error) {
+	initdb, err := findPostgresBinary("initdb")
+	if err != nil {
+		return "", err
+	}
+
+	pgCtl, err := findPostgresBinary("pg_ctl")
+	if err != nil {
+		return "", err
+	}
+
+	dir, err := os.MkdirTemp("", "dirty-hand-postgres")
+	if err != nil {
+		return "", err
+	}
+
+	data := filepath.Join(dir, "data")
+	if out, err := exec.Command(initdb, "-D", data, "-U", "postgres", "--auth=trust", "--encoding=UTF8").CombinedOutput(); err != nil {
+		os.RemoveAll(dir)
+		return "", fmt.Errorf("initdb: %v: %s", err, out)
+	}
+
+	options := fmt.Sprintf("-c listen_addresses='' -k %s", dir)
+	if out, err := exec.Command(pgCtl, "-D", data, "-o", options, "-l", filepath.Join(dir, "server.log"), "-w", "start").CombinedOutput(); err != nil {
+		os.RemoveAll(dir)
+		return "", fmt.Errorf("pg_ctl start: %v: %s", err, out)
+	}
+
+	stopServer = func() {
+		exec.Command(pgCtl, "-D", data, "-m", "immediate", "stop").Run()
+		os.RemoveAll(dir)
+	}
+
+	return fmt.Sprintf("host=%s user=postgres dbname=postgres sslmode=disable", dir), nil
+}
+
+// newTestDB returns a migrated database in a schema of its own.
+func newTestDB(t *testing.T) *DB {
+	t.Helper()
+
+	dsn := testDSN(t)
+
+	admin, err := sql.Open("pgx", dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { admin.Close() })
+
+	schema := fmt.Sprintf("test_%d", time.Now().UnixNano())
+	if _, err := admin.Exec("CREATE SCHEMA " + schema); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { admin.Exec("DROP SCHEMA " + schema + " CASCADE") })
+
+	separator := " "
+	if strings.Contains(dsn, "://") {
+		separator = "&"
+		if !strings.Contains(dsn, "?") {
+			separator = "?"
+		}
+	}
+
+	db, err := NewDB(&config.Config{Database: config.DatabaseConfig{
+		Driver: config.DatabasePostgres,
+		DSN:    dsn + separator + "search_path=" + schema,
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	if _, err := db.Migrator().Up(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	return db
+}
+
+func TestDriver(t *testing.T) {
+	storetest.Run(t, func(t *testing.T) store.Driver { return newTestDB(t) })
+}
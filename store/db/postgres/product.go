@@ -0,0 +1,276 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/thetnaingtn/dirty-hand/store"
+)
+
+func (d *DB) CreateProduct(ctx context.Context, p *store.Product) (*store.Product, error) {
+	stmt := `INSERT INTO products (organization_id, name, description, price, cover, created_at, updated_at) VALUES (` + placeholders(7) + `) RETURNING id`
+
+	if err := d.conn(ctx).QueryRowContext(ctx, stmt, p.OrganizationID, p.Name, p.Description, p.Price, p.Cover, p.CreatedAt, p.UpdatedAt).Scan(&p.ID); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (d *DB) UpdateProduct(ctx context.Context, p *store.Product) (*store.Product, error) {
+	stmt := `UPDATE products SET name = $1, description = $2, price = $3, cover = $4, updated_at = $5 WHERE id = $6 AND organization_id = $7
+        RETURNING ` + productColumns
+
+	product, err := scanProduct(d.conn(ctx).QueryRowContext(ctx, stmt, p.Name, p.Description, p.Price, p.Cover, p.UpdatedAt, p.ID, p.OrganizationID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return product, nil
+}
+
+func (d *DB) GetProduct(ctx context.Context, organizationId, id int64) (*store.Product, error) {
+	stmt := `SELECT ` + productColumns + ` FROM products WHERE id = $1 AND organization_id = $2`
+
+	product, err := scanProduct(d.conn(ctx).QueryRowContext(ctx, stmt, id, organizationId))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return product, nil
+}
+
+func (d *DB) ListProducts(ctx context.Context, find *store.FindProduct) ([]*store.Product, error) {
+	where, args := productFilterClause(find)
+
+	orderBy := store.ProductOrderByID
+	descending := false
+	if find != nil {
+		if find.OrderBy != "" {
+			orderBy = find.OrderBy
+		}
+		descending = find.Descending
+	}
+
+	column, ok := productOrderColumns[orderBy]
+	if !ok {
+		return nil, fmt.Errorf("unsupported product order field %q", orderBy)
+	}
+
+	direction, cmp := "ASC", ">"
+	if descending {
+		direction, cmp = "DESC", "<"
+	}
+
+	if find != nil && find.After != nil {
+		if column == "id" {
+			args = append(args, find.After.ID)
+			where = append(where, "id "+cmp+" "+placeholder(len(args)))
+		} else {
+			args = append(args, productOrderValue(find.After, orderBy), find.After.ID)
+			value, id := placeholder(len(args)-1), placeholder(len(args))
+			where = append(where, fmt.Sprintf("(%s %s %s OR (%s = %s AND id %s %s))", column, cmp, value, column, value, cmp, id))
+		}
+	}
+
+	stmt := "SELECT " + productColumns + " FROM products WHERE " + strings.Join(where, " AND ")
+	if column == "id" {
+		stmt += " ORDER BY id " + direction
+	} else {
+		stmt += fmt.Sprintf(" ORDER BY %s %s, id %s", column, direction, direction)
+	}
+
+	if find != nil && find.Limit != nil {
+		args = append(args, *find.Limit)
+		stmt += " LIMIT " + placeholder(len(args))
+	}
+
+	rows, err := d.conn(ctx).QueryContext(ctx, stmt, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var products []*store.Product
+	for rows.Next() {
+		product, err := scanProduct(rows)
+		if err != nil {
+			return nil, err
+		}
+		products = append(products, product)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return products, nil
+}
+
+func (d *DB) CountProducts(ctx context.Context, find *store.FindProduct) (int64, error) {
+	where, args := productFilterClause(find)
+
+	var count int64
+	stmt := "SELECT COUNT(*) FROM products WHERE " + strings.Join(where, " AND ")
+	if err := d.conn(ctx).QueryRowContext(ctx, stmt, args...).Scan(&count); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+const productColumns = "id, organization_id, name, description, price, cover, created_at, updated_at"
+
+func scanProduct(row interface{ Scan(...any) error }) (*store.Product, error) {
+	var p store.Product
+	if err := row.Scan(&p.ID, &p.OrganizationID, &p.Name, &p.Description, &p.Price, &p.Cover, &p.CreatedAt, &p.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+var productOrderColumns = map[store.ProductOrderField]string{
+	store.ProductOrderByID:        "id",
+	store.ProductOrderByName:      "name",
+	store.ProductOrderByPrice:     "price",
+	store.ProductOrderByCreatedAt: "created_at",
+	store.ProductOrderByUpdatedAt: "updated_at",
+}
+
+func productOrderValue(p *store.Product, field store.ProductOrderField) any {
+	switch field {
+	case store.ProductOrderByName:
+		return p.Name
+	case store.ProductOrderByPrice:
+		return p.Price
+	case store.ProductOrderByCreatedAt:
+		return p.CreatedAt
+	case store.ProductOrderByUpdatedAt:
+		return p.UpdatedAt
+	default:
+		return p.ID
+	}
+}
+
+// productFilterClause builds the WHERE conditions shared by ListProducts and
+// CountProducts. The cursor and limit of find are not taken into account.
+func productFilterClause(find *store.FindProduct) ([]string, []any) {
+	where, args := []string{"1 = 1"}, []any{}
+	if find == nil {
+		return where, args
+	}
+
+	if v := find.OrganizationID; v != nil {
+		args = append(args, *v)
+		where = append(where, "organization_id = "+placeholder(len(args)))
+	}
+	if v := find.Name; v != nil && *v != "" {
+		args = append(args, "%"+escapeLike(*v)+"%")
+		where = append(where, `name ILIKE `+placeholder(len(args))+` ESCAPE '\'`)
+	}
+	if v := find.MinPrice; v != nil {
+		args = append(args, *v)
+		where = append(where, "price >= "+placeholder(len(args)))
+	}
+	if v := find.MaxPrice; v != nil {
+		args = append(args, *v)
+		where = append(where, "price <= "+placeholder(len(args)))
+	}
+	if v := find.CreatedAfter; v != nil {
+		args = append(args, *v)
+		where = append(where, "created_at >= "+placeholder(len(args)))
+	}
+	if v := find.CreatedBefore; v != nil {
+		args = append(args, *v)
+		where = append(where, "created_at < "+placeholder(len(args)))
+	}
+	if v := find.UpdatedAfter; v != nil {
+		args = append(args, *v)
+		where = append(where, "updated_at >= "+placeholder(len(args)))
+	}
+	if v := find.UpdatedBefore; v != nil {
+		args = append(args, *v)
+		where = append(where, "updated_at < "+placeholder(len(args)))
+	}
+
+	return where, args
+}
+
+// escapeLike escapes the LIKE wildcards in s so it is matched literally.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
+
+func (d *DB) SearchProducts(ctx context.Context, search *store.SearchProduct) ([]*store.ProductSearchResult, error) {
+	query := tsPrefixQuery(search.Terms)
+	if query == "" {
+		return nil, nil
+	}
+
+	stmt := `SELECT p.id, p.organization_id, p.name, p.description, p.price, p.cover, p.created_at, p.updated_at,
+                ts_headline('simple', p.name, q, 'StartSel=<mark>, StopSel=</mark>, HighlightAll=true'),
+                ts_headline('simple', p.description, q, 'StartSel=<mark>, StopSel=</mark>, MaxWords=16, MinWords=8, MaxFragments=1, FragmentDelimiter=…'),
+                ts_rank(p.search, q)
+        FROM products p, to_tsquery('simple', $1) q
+        WHERE p.search @@ q`
+	args := []any{query}
+
+	if search.OrganizationID != nil {
+		args = append(args, *search.OrganizationID)
+		stmt += " AND p.organization_id = " + placeholder(len(args))
+	}
+
+	stmt += " ORDER BY ts_rank(p.search, q) DESC, p.id"
+
+	if search.Limit != nil {
+		args = append(args, *search.Limit)
+		stmt += " LIMIT " + placeholder(len(args))
+	}
+
+	rows, err := d.conn(ctx).QueryContext(ctx, stmt, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []*store.ProductSearchResult
+	for rows.Next() {
+		var p store.Product
+		var result store.ProductSearchResult
+		if err := rows.Scan(&p.ID, &p.OrganizationID, &p.Name, &p.Description, &p.Price, &p.Cover, &p.CreatedAt, &p.UpdatedAt,
+			&result.NameHighlight, &result.DescriptionSnippet, &result.Score); err != nil {
+			return nil, err
+		}
+		result.Product = &p
+		results = append(results, &result)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// tsPrefixQuery turns search terms into a tsquery that requires every word
+// as a prefix. Words are quoted so tsquery syntax in user input is matched
+// literally.
+func tsPrefixQuery(terms []string) string {
+	var parts []string
+	for _, term := range terms {
+		words := strings.FieldsFunc(term, func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		})
+		for _, word := range words {
+			parts = append(parts, "'"+strings.ToLower(word)+"':*")
+		}
+	}
+	return strings.Join(parts, " & ")
+}
+
+func (d *DB) DeleteProduct(ctx context.Context, organizationId, id int64) error {
+	_, err := d.conn(ctx).ExecContext(ctx, `DELETE FROM products WHERE id = $1 AND organization_id = $2`, id, organizationId)
+	return err
+}
@@ -0,0 +1,90 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/thetnaingtn/dirty-hand/store"
+)
+
+func (d *DB) CreateSession(ctx context.Context, session *store.Session) (*store.Session, error) {
+	query := `INSERT INTO sessions (user_id, session_id, created_time, last_accessed_time, expires_at, user_agent, client_ip) VALUES (` + placeholders(7) + `)`
+
+	if _, err := d.conn(ctx).ExecContext(ctx, query, session.UserID, session.SessionID, session.CreatedTime, session.LastAccessedTime, session.ExpiresAt, session.UserAgent, session.ClientIP); err != nil {
+		return nil, err
+	}
+
+	return session, nil
+}
+
+func (d *DB) GetUserSessions(ctx context.Context, userId int64) ([]*store.Session, error) {
+	query := `SELECT session_id, user_id, created_time, last_accessed_time, expires_at, user_agent, client_ip FROM sessions WHERE user_id = $1 ORDER BY created_time`
+
+	rows, err := d.conn(ctx).QueryContext(ctx, query, userId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var sessions []*store.Session
+	for rows.Next() {
+		session := &store.Session{}
+		err := rows.Scan(&session.SessionID, &session.UserID, &session.CreatedTime, &session.LastAccessedTime, &session.ExpiresAt, &session.UserAgent, &session.ClientIP)
+		if err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, session)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return sessions, nil
+}
+
+func (d *DB) DeleteSession(ctx context.Context, sessionId string) error {
+	query := `DELETE FROM sessions WHERE session_id = $1`
+
+	_, err := d.conn(ctx).ExecContext(ctx, query, sessionId)
+	return err
+}
+
+func (d *DB) DeleteUserSessions(ctx context.Context, userId int64, exceptSessionId string) error {
+	query := `DELETE FROM sessions WHERE user_id = $1 AND session_id != $2`
+
+	_, err := d.conn(ctx).ExecContext(ctx, query, userId, exceptSessionId)
+	return err
+}
+
+func (d *DB) DeleteExpiredSessions(ctx context.Context, lastAccessedBefore, expiresBefore time.Time) ([]int64, error) {
+	query := `DELETE FROM sessions WHERE last_accessed_time < $1 OR expires_at < $2 RETURNING user_id`
+
+	rows, err := d.conn(ctx).QueryContext(ctx, query, lastAccessedBefore, expiresBefore)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var userIds []int64
+	for rows.Next() {
+		var userId int64
+		if err := rows.Scan(&userId); err != nil {
+			return nil, err
+		}
+		userIds = append(userIds, userId)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return userIds, nil
+}
+
+func (d *DB) UpdateLastAccessedTime(ctx context.Context, sessionId string, lastAccessTime time.Time) error {
+	query := `UPDATE sessions SET last_accessed_time = $1 WHERE session_id = $2`
+
+	_, err := d.conn(ctx).ExecContext(ctx, query, lastAccessTime, sessionId)
+	return err
+}
@@ -0,0 +1,61 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/thetnaingtn/dirty-hand/store"
+)
+
+func (d *DB) GetSignInThrottle(ctx context.Context, key string) (*store.SignInThrottle, error) {
+	query := `SELECT throttle_key, failures, last_failure_time, locked_until FROM sign_in_throttles WHERE throttle_key = $1`
+
+	var throttle store.SignInThrottle
+	var lockedUntil sql.NullTime
+	if err := d.conn(ctx).QueryRowContext(ctx, query, key).Scan(&throttle.Key, &throttle.Failures, &throttle.LastFailureTime, &lockedUntil); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	if lockedUntil.Valid {
+		throttle.LockedUntil = lockedUntil.Time
+	}
+
+	return &throttle, nil
+}
+
+func (d *DB) UpsertSignInThrottle(ctx context.Context, throttle *store.SignInThrottle) (*store.SignInThrottle, error) {
+	query := `INSERT INTO sign_in_throttles (throttle_key, failures, last_failure_time, locked_until) VALUES ($1, $2, $3, $4)
+        ON CONFLICT (throttle_key) DO UPDATE SET failures = excluded.failures,
+                last_failure_time = excluded.last_failure_time, locked_until = excluded.locked_until`
+
+	var lockedUntil sql.NullTime
+	if !throttle.LockedUntil.IsZero() {
+		lockedUntil = sql.NullTime{Time: throttle.LockedUntil, Valid: true}
+	}
+
+	if _, err := d.conn(ctx).ExecContext(ctx, query, throttle.Key, throttle.Failures, throttle.LastFailureTime, lockedUntil); err != nil {
+		return nil, err
+	}
+
+	return throttle, nil
+}
+
+func (d *DB) DeleteSignInThrottle(ctx context.Context, key string) error {
+	_, err := d.conn(ctx).ExecContext(ctx, `DELETE FROM sign_in_throttles WHERE throttle_key = $1`, key)
+	return err
+}
+
+func (d *DB) DeleteStaleSignInThrottles(ctx context.Context, lastFailureBefore, now time.Time) (int64, error) {
+	result, err := d.conn(ctx).ExecContext(ctx, `DELETE FROM sign_in_throttles
+        WHERE last_failure_time < $1 AND (locked_until IS NULL OR locked_until <= $2)`, lastFailureBefore, now)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
@@ -0,0 +1,146 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+
+	"github.com/thetnaingtn/dirty-hand/store"
+)
+
+func (d *DB) CreateUser(ctx context.Context, create *store.User) (*store.User, error) {
+	fields := []string{"username", "password_hash", "role"}
+	values := []any{create.Username, create.PasswordHash, create.Role}
+
+	stmt := "INSERT INTO users (" + strings.Join(fields, ", ") + ") VALUES (" + placeholders(len(values)) + ") RETURNING id"
+
+	if err := d.conn(ctx).QueryRowContext(ctx, stmt, values...).Scan(&create.ID); err != nil {
+		return nil, err
+	}
+
+	return create, nil
+}
+
+func (d *DB) ListUsers(ctx context.Context, filter *store.FindUser) ([]store.User, error) {
+	var users []store.User
+
+	stmt := "SELECT id, username, password_hash, role, disabled FROM users WHERE 1=1"
+	var args []any
+
+	if filter != nil {
+		if filter.Role != nil {
+			args = append(args, *filter.Role)
+			stmt += " AND role = " + placeholder(len(args))
+		}
+
+		if filter.ID != nil {
+			args = append(args, *filter.ID)
+			stmt += " AND id = " + placeholder(len(args))
+		}
+
+		if filter.Username != nil {
+			args = append(args, *filter.Username)
+			stmt += " AND username = " + placeholder(len(args))
+		}
+
+		if filter.AfterID != nil {
+			args = append(args, *filter.AfterID)
+			stmt += " AND id > " + placeholder(len(args))
+		}
+	}
+
+	stmt += " ORDER BY id"
+
+	if filter != nil && filter.Limit != nil {
+		args = append(args, *filter.Limit)
+		stmt += " LIMIT " + placeholder(len(args))
+	}
+
+	rows, err := d.conn(ctx).QueryContext(ctx, stmt, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var user store.User
+		if err := rows.Scan(&user.ID, &user.Username, &user.PasswordHash, &user.Role, &user.Disabled); err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return users, nil
+}
+
+func (d *DB) GetUser(ctx context.Context, filter *store.FindUser) (*store.User, error) {
+	var user store.User
+	where, args := []string{"1 = 1"}, []any{}
+
+	if filter != nil {
+		if v := filter.Role; v != nil {
+			args = append(args, *v)
+			where = append(where, "role = "+placeholder(len(args)))
+		}
+
+		if v := filter.Username; v != nil {
+			args = append(args, *v)
+			where = append(where, "username = "+placeholder(len(args)))
+		}
+
+		if v := filter.ID; v != nil {
+			args = append(args, *v)
+			where = append(where, "id = "+placeholder(len(args)))
+		}
+	}
+
+	stmt := "SELECT id, username, password_hash, role, disabled FROM users WHERE " + strings.Join(where, " AND ") + " ORDER BY id LIMIT 1"
+
+	if err := d.conn(ctx).QueryRowContext(ctx, stmt, args...).Scan(&user.ID, &user.Username, &user.PasswordHash, &user.Role, &user.Disabled); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+func (d *DB) UpdateUser(ctx context.Context, update *store.UpdateUser) (*store.User, error) {
+	set, args := []string{}, []any{}
+
+	if v := update.PasswordHash; v != nil {
+		args = append(args, *v)
+		set = append(set, "password_hash = "+placeholder(len(args)))
+	}
+
+	if v := update.Role; v != nil {
+		args = append(args, *v)
+		set = append(set, "role = "+placeholder(len(args)))
+	}
+
+	if v := update.Disabled; v != nil {
+		args = append(args, *v)
+		set = append(set, "disabled = "+placeholder(len(args)))
+	}
+
+	if len(set) > 0 {
+		args = append(args, update.ID)
+		stmt := "UPDATE users SET " + strings.Join(set, ", ") + " WHERE id = " + placeholder(len(args))
+		if _, err := d.conn(ctx).ExecContext(ctx, stmt, args...); err != nil {
+			return nil, err
+		}
+	}
+
+	return d.GetUser(ctx, &store.FindUser{ID: &update.ID})
+}
+
+func (d *DB) DeleteUser(ctx context.Context, id int64) error {
+	_, err := d.conn(ctx).ExecContext(ctx, `DELETE FROM users WHERE id = $1`, id)
+	return err
+}
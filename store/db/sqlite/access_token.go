@@ -0,0 +1,105 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/thetnaingtn/dirty-hand/store"
+)
+
+func (d *DB) CreateAccessToken(ctx context.Context, create *store.AccessToken) (*store.AccessToken, error) {
+	stmt := `INSERT INTO access_tokens (user_id, name, token_hash, scopes, created_time, expires_at) VALUES (?, ?, ?, ?, ?, ?) RETURNING id`
+
+	if err := d.conn(ctx).QueryRowContext(ctx, stmt, create.UserID, create.Name, create.TokenHash, joinRoles(create.Scopes), create.CreatedTime, create.ExpiresAt).Scan(&create.ID); err != nil {
+		return nil, err
+	}
+
+	return create, nil
+}
+
+func (d *DB) ListAccessTokens(ctx context.Context, find *store.FindAccessToken) ([]*store.AccessToken, error) {
+	where, args := []string{"1 = 1"}, []any{}
+
+	if find != nil {
+		if v := find.ID; v != nil {
+			where = append(where, "id = ?")
+			args = append(args, *v)
+		}
+
+		if v := find.UserID; v != nil {
+			where = append(where, "user_id = ?")
+			args = append(args, *v)
+		}
+
+		if v := find.TokenHash; v != nil {
+			where = append(where, "token_hash = ?")
+			args = append(args, *v)
+		}
+	}
+
+	stmt := "SELECT id, user_id, name, token_hash, scopes, created_time, expires_at, last_used_time FROM access_tokens WHERE " + strings.Join(where, " AND ") + " ORDER BY id"
+
+	rows, err := d.conn(ctx).QueryContext(ctx, stmt, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tokens []*store.AccessToken
+	for rows.Next() {
+		var token store.AccessToken
+		var scopes string
+		var expiresAt, lastUsedTime sql.NullTime
+		if err := rows.Scan(&token.ID, &token.UserID, &token.Name, &token.TokenHash, &scopes, &token.CreatedTime, &expiresAt, &lastUsedTime); err != nil {
+			return nil, err
+		}
+
+		token.Scopes = splitRoles(scopes)
+		if expiresAt.Valid {
+			token.ExpiresAt = &expiresAt.Time
+		}
+		if lastUsedTime.Valid {
+			token.LastUsedTime = &lastUsedTime.Time
+		}
+
+		tokens = append(tokens, &token)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return tokens, nil
+}
+
+func (d *DB) UpdateAccessTokenLastUsedTime(ctx context.Context, id int64, lastUsedTime time.Time) error {
+	_, err := d.conn(ctx).ExecContext(ctx, `UPDATE access_tokens SET last_used_time = ? WHERE id = ?`, lastUsedTime, id)
+	return err
+}
+
+func (d *DB) DeleteAccessToken(ctx context.Context, id int64) error {
+	_, err := d.conn(ctx).ExecContext(ctx, `DELETE FROM access_tokens WHERE id = ?`, id)
+	return err
+}
+
+func joinRoles(roles []store.Role) string {
+	values := make([]string, 0, len(roles))
+	for _, role := range roles {
+		values = append(values, string(role))
+	}
+	return strings.Join(values, ",")
+}
+
+func splitRoles(value string) []store.Role {
+	if value == "" {
+		return nil
+	}
+
+	var roles []store.Role
+	for _, role := range strings.Split(value, ",") {
+		roles = append(roles, store.Role(role))
+	}
+	return roles
+}
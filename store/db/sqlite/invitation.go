@@ -0,0 +1,104 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/thetnaingtn/dirty-hand/store"
+)
+
+func (d *DB) CreateInvitation(ctx context.Context, create *store.Invitation) (*store.Invitation, error) {
+	stmt := `INSERT INTO invitations (code_hash, role, created_by, created_time, expires_time) VALUES (?, ?, ?, ?, ?) RETURNING id`
+
+	if err := d.conn(ctx).QueryRowContext(ctx, stmt, create.CodeHash, create.Role, create.CreatedBy, create.CreatedTime, create.ExpiresTime).Scan(&create.ID); err != nil {
+		return nil, err
+	}
+
+	return create, nil
+}
+
+func (d *DB) ListInvitations(ctx context.Context, find *store.FindInvitation) ([]*store.Invitation, error) {
+	where, args := []string{"1 = 1"}, []any{}
+
+	if find != nil {
+		if v := find.ID; v != nil {
+			where = append(where, "id = ?")
+			args = append(args, *v)
+		}
+
+		if v := find.CodeHash; v != nil {
+			where = append(where, "code_hash = ?")
+			args = append(args, *v)
+		}
+	}
+
+	stmt := "SELECT " + invitationColumns + " FROM invitations WHERE " + strings.Join(where, " AND ") + " ORDER BY id DESC"
+
+	rows, err := d.conn(ctx).QueryContext(ctx, stmt, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var invitations []*store.Invitation
+	for rows.Next() {
+		invitation, err := scanInvitation(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		invitations = append(invitations, invitation)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return invitations, nil
+}
+
+func (d *DB) ConsumeInvitation(ctx context.Context, codeHash string, userId int64, now time.Time) (*store.Invitation, error) {
+	stmt := `UPDATE invitations SET used_time = ?, used_by = ?
+        WHERE code_hash = ? AND used_time IS NULL AND expires_time > ?
+        RETURNING ` + invitationColumns
+
+	invitation, err := scanInvitation(d.conn(ctx).QueryRowContext(ctx, stmt, now, userId, codeHash, now))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return invitation, nil
+}
+
+func (d *DB) DeleteInvitation(ctx context.Context, id int64) error {
+	_, err := d.conn(ctx).ExecContext(ctx, `DELETE FROM invitations WHERE id = ?`, id)
+	return err
+}
+
+const invitationColumns = "id, code_hash, role, created_by, created_time, expires_time, used_time, used_by"
+
+func scanInvitation(row interface{ Scan(...any) error }) (*store.Invitation, error) {
+	var invitation store.Invitation
+	var usedTime sql.NullTime
+	var createdBy, usedBy sql.NullInt64
+	if err := row.Scan(&invitation.ID, &invitation.CodeHash, &invitation.Role, &createdBy, &invitation.CreatedTime, &invitation.ExpiresTime, &usedTime, &usedBy); err != nil {
+		return nil, err
+	}
+
+	invitation.CreatedBy = createdBy.Int64
+
+	if usedTime.Valid {
+		invitation.UsedTime = &usedTime.Time
+	}
+	if usedBy.Valid {
+		invitation.UsedBy = &usedBy.Int64
+	}
+
+	return &invitation, nil
+}
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS products_fts;
+DROP TABLE sessions;
+DROP TABLE users;
+DROP TABLE products;
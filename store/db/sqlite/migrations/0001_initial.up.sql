@@ -0,0 +1,25 @@
+-- The schema from before versioned migrations. IF NOT EXISTS lets databases
+-- created by those versions adopt the migrations.
+CREATE TABLE IF NOT EXISTS products (
+        id INTEGER PRIMARY KEY AUTOINCREMENT,
+        name TEXT NOT NULL,
+        description TEXT,
+        price REAL NOT NULL,
+        cover TEXT,
+        created_at DATETIME NOT NULL,
+        updated_at DATETIME NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS users (
+        id INTEGER PRIMARY KEY AUTOINCREMENT,
+        username TEXT NOT NULL UNIQUE,
+        password_hash TEXT NOT NULL,
+        role TEXT NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS sessions (
+        user_id INTEGER NOT NULL,
+        session_id CHAR(36) NOT NULL PRIMARY KEY,
+        last_accessed_time DATETIME NOT NULL,
+        FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
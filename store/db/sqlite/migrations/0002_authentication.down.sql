@@ -0,0 +1,19 @@
+DROP TABLE password_reset_tokens;
+DROP TABLE invitations;
+DROP TABLE sign_in_throttles;
+DROP TABLE second_factor_challenges;
+DROP TABLE recovery_codes;
+DROP TABLE user_totp;
+DROP TABLE user_identities;
+DROP TABLE access_tokens;
+
+DROP TABLE sessions;
+
+CREATE TABLE sessions (
+        user_id INTEGER NOT NULL,
+        session_id CHAR(36) NOT NULL PRIMARY KEY,
+        last_accessed_time DATETIME NOT NULL,
+        FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+ALTER TABLE users DROP COLUMN disabled;
@@ -0,0 +1,93 @@
+ALTER TABLE users ADD COLUMN disabled INTEGER NOT NULL DEFAULT 0;
+
+-- Sessions gain an expiry and client details. Existing sessions have neither,
+-- so their users sign in again.
+DROP TABLE sessions;
+
+CREATE TABLE sessions (
+        user_id INTEGER NOT NULL,
+        session_id CHAR(36) NOT NULL PRIMARY KEY,
+        created_time DATETIME NOT NULL,
+        last_accessed_time DATETIME NOT NULL,
+        expires_at DATETIME NOT NULL,
+        user_agent TEXT NOT NULL DEFAULT '',
+        client_ip TEXT NOT NULL DEFAULT '',
+        FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE TABLE access_tokens (
+        id INTEGER PRIMARY KEY AUTOINCREMENT,
+        user_id INTEGER NOT NULL,
+        name TEXT NOT NULL,
+        token_hash CHAR(64) NOT NULL UNIQUE,
+        scopes TEXT NOT NULL,
+        created_time DATETIME NOT NULL,
+        expires_at DATETIME,
+        last_used_time DATETIME,
+        FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE TABLE user_identities (
+        user_id INTEGER NOT NULL,
+        issuer TEXT NOT NULL,
+        subject TEXT NOT NULL,
+        created_time DATETIME NOT NULL,
+        PRIMARY KEY (issuer, subject),
+        FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE TABLE user_totp (
+        user_id INTEGER NOT NULL PRIMARY KEY,
+        secret TEXT NOT NULL,
+        enabled INTEGER NOT NULL DEFAULT 0,
+        last_used_step INTEGER NOT NULL DEFAULT 0,
+        created_time DATETIME NOT NULL,
+        FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE TABLE recovery_codes (
+        code_hash CHAR(64) NOT NULL,
+        user_id INTEGER NOT NULL,
+        used_time DATETIME,
+        PRIMARY KEY (user_id, code_hash),
+        FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE TABLE second_factor_challenges (
+        token_hash CHAR(64) NOT NULL PRIMARY KEY,
+        user_id INTEGER NOT NULL,
+        remember_me INTEGER NOT NULL DEFAULT 0,
+        attempts INTEGER NOT NULL DEFAULT 0,
+        created_time DATETIME NOT NULL,
+        expires_time DATETIME NOT NULL,
+        FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE TABLE sign_in_throttles (
+        throttle_key TEXT NOT NULL PRIMARY KEY,
+        failures INTEGER NOT NULL,
+        last_failure_time DATETIME NOT NULL,
+        locked_until DATETIME
+);
+
+CREATE TABLE invitations (
+        id INTEGER PRIMARY KEY AUTOINCREMENT,
+        code_hash CHAR(64) NOT NULL UNIQUE,
+        role TEXT NOT NULL,
+        created_by INTEGER,
+        created_time DATETIME NOT NULL,
+        expires_time DATETIME NOT NULL,
+        used_time DATETIME,
+        used_by INTEGER,
+        FOREIGN KEY (created_by) REFERENCES users(id) ON DELETE SET NULL,
+        FOREIGN KEY (used_by) REFERENCES users(id) ON DELETE SET NULL
+);
+
+CREATE TABLE password_reset_tokens (
+        token_hash CHAR(64) NOT NULL PRIMARY KEY,
+        user_id INTEGER NOT NULL,
+        created_time DATETIME NOT NULL,
+        expires_time DATETIME NOT NULL,
+        used_time DATETIME,
+        FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
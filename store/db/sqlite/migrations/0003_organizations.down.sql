@@ -0,0 +1,19 @@
+CREATE TABLE products_old (
+        id INTEGER PRIMARY KEY AUTOINCREMENT,
+        name TEXT NOT NULL,
+        description TEXT,
+        price REAL NOT NULL,
+        cover TEXT,
+        created_at DATETIME NOT NULL,
+        updated_at DATETIME NOT NULL
+);
+
+INSERT INTO products_old (id, name, description, price, cover, created_at, updated_at)
+SELECT id, name, description, price, cover, created_at, updated_at FROM products;
+
+DROP TABLE products;
+
+ALTER TABLE products_old RENAME TO products;
+
+DROP TABLE memberships;
+DROP TABLE organizations;
@@ -0,0 +1,49 @@
+CREATE TABLE organizations (
+        id INTEGER PRIMARY KEY AUTOINCREMENT,
+        name TEXT NOT NULL UNIQUE,
+        created_time DATETIME NOT NULL
+);
+
+CREATE TABLE memberships (
+        organization_id INTEGER NOT NULL,
+        user_id INTEGER NOT NULL,
+        role TEXT NOT NULL,
+        created_time DATETIME NOT NULL,
+        PRIMARY KEY (organization_id, user_id),
+        FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE,
+        FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+-- Calls without an organization act on the Default organization, which
+-- existing products and users move to, keeping their roles.
+INSERT INTO organizations (name, created_time) VALUES ('Default', CURRENT_TIMESTAMP);
+
+INSERT INTO memberships (organization_id, user_id, role, created_time)
+SELECT organizations.id, users.id, users.role, CURRENT_TIMESTAMP
+FROM users, organizations
+WHERE organizations.name = 'Default';
+
+-- SQLite cannot add a NOT NULL column with a foreign key, so products is
+-- rebuilt. Keeping the ids keeps the full-text index in sync.
+CREATE TABLE products_new (
+        id INTEGER PRIMARY KEY AUTOINCREMENT,
+        organization_id INTEGER NOT NULL,
+        name TEXT NOT NULL,
+        description TEXT,
+        price REAL NOT NULL,
+        cover TEXT,
+        created_at DATETIME NOT NULL,
+        updated_at DATETIME NOT NULL,
+        FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE
+);
+
+INSERT INTO products_new (id, organization_id, name, description, price, cover, created_at, updated_at)
+SELECT products.id, organizations.id, products.name, products.description, products.price, products.cover, products.created_at, products.updated_at
+FROM products, organizations
+WHERE organizations.name = 'Default';
+
+DROP TABLE products;
+
+ALTER TABLE products_new RENAME TO products;
+
+CREATE INDEX idx_products_organization_id ON products (organization_id);
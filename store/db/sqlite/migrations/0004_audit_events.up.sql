@@ -0,0 +1,16 @@
+CREATE TABLE audit_events (
+        id INTEGER PRIMARY KEY AUTOINCREMENT,
+        created_time DATETIME NOT NULL,
+        actor_id INTEGER,
+        organization_id INTEGER,
+        method TEXT NOT NULL,
+        resource_type TEXT NOT NULL DEFAULT '',
+        resource_id TEXT NOT NULL DEFAULT '',
+        changes TEXT NOT NULL DEFAULT '[]',
+        client_ip TEXT NOT NULL DEFAULT '',
+        outcome TEXT NOT NULL,
+        error_message TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX idx_audit_events_actor_id ON audit_events (actor_id);
+CREATE INDEX idx_audit_events_resource ON audit_events (resource_type, resource_id);
@@ -0,0 +1,56 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/thetnaingtn/dirty-hand/store"
+)
+
+func (d *DB) CreatePasswordResetToken(ctx context.Context, token *store.PasswordResetToken) (*store.PasswordResetToken, error) {
+	query := `INSERT INTO password_reset_tokens (token_hash, user_id, created_time, expires_time) VALUES (?, ?, ?, ?)`
+
+	if _, err := d.conn(ctx).ExecContext(ctx, query, token.TokenHash, token.UserID, token.CreatedTime, token.ExpiresTime); err != nil {
+		return nil, err
+	}
+
+	return token, nil
+}
+
+func (d *DB) GetPasswordResetToken(ctx context.Context, tokenHash string, now time.Time) (*store.PasswordResetToken, error) {
+	query := `SELECT token_hash, user_id, created_time, expires_time FROM password_reset_tokens
+        WHERE token_hash = ? AND used_time IS NULL AND expires_time > ?`
+
+	var token store.PasswordResetToken
+	if err := d.conn(ctx).QueryRowContext(ctx, query, tokenHash, now).Scan(&token.TokenHash, &token.UserID, &token.CreatedTime, &token.ExpiresTime); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &token, nil
+}
+
+func (d *DB) ConsumePasswordResetToken(ctx context.Context, tokenHash string, now time.Time) (*store.PasswordResetToken, error) {
+	query := `UPDATE password_reset_tokens SET used_time = ?
+        WHERE token_hash = ? AND used_time IS NULL AND expires_time > ?
+        RETURNING token_hash, user_id, created_time, expires_time`
+
+	var token store.PasswordResetToken
+	if err := d.conn(ctx).QueryRowContext(ctx, query, now, tokenHash, now).Scan(&token.TokenHash, &token.UserID, &token.CreatedTime, &token.ExpiresTime); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &token, nil
+}
+
+func (d *DB) DeletePasswordResetTokens(ctx context.Context, userId int64) error {
+	_, err := d.conn(ctx).ExecContext(ctx, `DELETE FROM password_reset_tokens WHERE user_id = ?`, userId)
+	return err
+}
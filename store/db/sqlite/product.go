@@ -1,13 +1,18 @@
 package sqlite
 
 import (
-	"github.com/thetnaingtn/dirty-hand/store"
 	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/thetnaingtn/dirty-hand/store"
 )
 
 func (d *DB) CreateProduct(ctx context.Context, p *store.Product) (*store.Product, error) {
-	res, err := d.db.ExecContext(ctx, `INSERT INTO products (name, description, price, cover, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?)`,
-		p.Name, p.Description, p.Price, p.Cover, p.CreatedAt, p.UpdatedAt)
+	res, err := d.conn(ctx).ExecContext(ctx, `INSERT INTO products (organization_id, name, description, price, cover, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		p.OrganizationID, p.Name, p.Description, p.Price, p.Cover, p.CreatedAt, p.UpdatedAt)
 	if err != nil {
 		return nil, err
 	}
@@ -20,16 +25,80 @@ func (d *DB) CreateProduct(ctx context.Context, p *store.Product) (*store.Produc
 }
 
 func (d *DB) UpdateProduct(ctx context.Context, p *store.Product) (*store.Product, error) {
-	_, err := d.db.ExecContext(ctx, `UPDATE products SET name=?, description=?, price=?, cover=?, updated_at=? WHERE id=?`,
-		p.Name, p.Description, p.Price, p.Cover, p.UpdatedAt, p.ID)
+	res, err := d.conn(ctx).ExecContext(ctx, `UPDATE products SET name=?, description=?, price=?, cover=?, updated_at=? WHERE id=? AND organization_id=?`,
+		p.Name, p.Description, p.Price, p.Cover, p.UpdatedAt, p.ID, p.OrganizationID)
 	if err != nil {
 		return nil, err
 	}
-	return p, nil
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+	if affected == 0 {
+		return nil, nil
+	}
+	return d.GetProduct(ctx, p.OrganizationID, p.ID)
+}
+
+func (d *DB) GetProduct(ctx context.Context, organizationId, id int64) (*store.Product, error) {
+	var p store.Product
+	err := d.conn(ctx).QueryRowContext(ctx, `SELECT id, organization_id, name, description, price, cover, created_at, updated_at FROM products WHERE id = ? AND organization_id = ?`, id, organizationId).
+		Scan(&p.ID, &p.OrganizationID, &p.Name, &p.Description, &p.Price, &p.Cover, &p.CreatedAt, &p.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &p, nil
 }
 
-func (d *DB) ListProducts(ctx context.Context) ([]*store.Product, error) {
-	rows, err := d.db.QueryContext(ctx, `SELECT id, name, description, price, cover, created_at, updated_at FROM products`)
+func (d *DB) ListProducts(ctx context.Context, find *store.FindProduct) ([]*store.Product, error) {
+	where, args := productFilterClause(find)
+
+	orderBy := store.ProductOrderByID
+	descending := false
+	if find != nil {
+		if find.OrderBy != "" {
+			orderBy = find.OrderBy
+		}
+		descending = find.Descending
+	}
+
+	column, ok := productOrderColumns[orderBy]
+	if !ok {
+		return nil, fmt.Errorf("unsupported product order field %q", orderBy)
+	}
+
+	direction, cmp := "ASC", ">"
+	if descending {
+		direction, cmp = "DESC", "<"
+	}
+
+	if find != nil && find.After != nil {
+		if column == "id" {
+			where = append(where, "id "+cmp+" ?")
+			args = append(args, find.After.ID)
+		} else {
+			value := productOrderValue(find.After, orderBy)
+			where = append(where, fmt.Sprintf("(%s %s ? OR (%s = ? AND id %s ?))", column, cmp, column, cmp))
+			args = append(args, value, value, find.After.ID)
+		}
+	}
+
+	stmt := "SELECT id, organization_id, name, description, price, cover, created_at, updated_at FROM products WHERE " + strings.Join(where, " AND ")
+	if column == "id" {
+		stmt += " ORDER BY id " + direction
+	} else {
+		stmt += fmt.Sprintf(" ORDER BY %s %s, id %s", column, direction, direction)
+	}
+
+	if find != nil && find.Limit != nil {
+		stmt += " LIMIT ?"
+		args = append(args, *find.Limit)
+	}
+
+	rows, err := d.conn(ctx).QueryContext(ctx, stmt, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -38,7 +107,7 @@ func (d *DB) ListProducts(ctx context.Context) ([]*store.Product, error) {
 	var products []*store.Product
 	for rows.Next() {
 		var p store.Product
-		if err := rows.Scan(&p.ID, &p.Name, &p.Description, &p.Price, &p.Cover, &p.CreatedAt, &p.UpdatedAt); err != nil {
+		if err := rows.Scan(&p.ID, &p.OrganizationID, &p.Name, &p.Description, &p.Price, &p.Cover, &p.CreatedAt, &p.UpdatedAt); err != nil {
 			return nil, err
 		}
 		products = append(products, &p)
@@ -49,7 +118,158 @@ func (d *DB) ListProducts(ctx context.Context) ([]*store.Product, error) {
 	return products, nil
 }
 
-func (d *DB) DeleteProduct(ctx context.Context, id int64) error {
-	_, err := d.db.ExecContext(ctx, `DELETE FROM products WHERE id = ?`, id)
+func (d *DB) CountProducts(ctx context.Context, find *store.FindProduct) (int64, error) {
+	where, args := productFilterClause(find)
+
+	var count int64
+	stmt := "SELECT COUNT(*) FROM products WHERE " + strings.Join(where, " AND ")
+	if err := d.conn(ctx).QueryRowContext(ctx, stmt, args...).Scan(&count); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+var productOrderColumns = map[store.ProductOrderField]string{
+	store.ProductOrderByID:        "id",
+	store.ProductOrderByName:      "name",
+	store.ProductOrderByPrice:     "price",
+	store.ProductOrderByCreatedAt: "created_at",
+	store.ProductOrderByUpdatedAt: "updated_at",
+}
+
+func productOrderValue(p *store.Product, field store.ProductOrderField) any {
+	switch field {
+	case store.ProductOrderByName:
+		return p.Name
+	case store.ProductOrderByPrice:
+		return p.Price
+	case store.ProductOrderByCreatedAt:
+		return p.CreatedAt
+	case store.ProductOrderByUpdatedAt:
+		return p.UpdatedAt
+	default:
+		return p.ID
+	}
+}
+
+// productFilterClause builds the WHERE conditions shared by ListProducts and
+// CountProducts. The cursor and limit of find are not taken into account.
+func productFilterClause(find *store.FindProduct) ([]string, []any) {
+	where, args := []string{"1 = 1"}, []any{}
+	if find == nil {
+		return where, args
+	}
+
+	if v := find.OrganizationID; v != nil {
+		where = append(where, "organization_id = ?")
+		args = append(args, *v)
+	}
+	if v := find.Name; v != nil && *v != "" {
+		where = append(where, `name LIKE '%' || ? || '%' ESCAPE '\'`)
+		args = append(args, escapeLike(*v))
+	}
+	if v := find.MinPrice; v != nil {
+		where = append(where, "price >= ?")
+		args = append(args, *v)
+	}
+	if v := find.MaxPrice; v != nil {
+		where = append(where, "price <= ?")
+		args = append(args, *v)
+	}
+	if v := find.CreatedAfter; v != nil {
+		where = append(where, "created_at >= ?")
+		args = append(args, *v)
+	}
+	if v := find.CreatedBefore; v != nil {
+		where = append(where, "created_at < ?")
+		args = append(args, *v)
+	}
+	if v := find.UpdatedAfter; v != nil {
+		where = append(where, "updated_at >= ?")
+		args = append(args, *v)
+	}
+	if v := find.UpdatedBefore; v != nil {
+		where = append(where, "updated_at < ?")
+		args = append(args, *v)
+	}
+
+	return where, args
+}
+
+// escapeLike escapes the LIKE wildcards in s so it is matched literally.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
+
+func (d *DB) SearchProducts(ctx context.Context, search *store.SearchProduct) ([]*store.ProductSearchResult, error) {
+	query := ftsMatchQuery(search.Terms)
+	if query == "" {
+		return nil, nil
+	}
+
+	stmt := `SELECT p.id, p.organization_id, p.name, p.description, p.price, p.cover, p.created_at, p.updated_at,
+                highlight(products_fts, 0, '<mark>', '</mark>'),
+                snippet(products_fts, 1, '<mark>', '</mark>', '…', 16),
+                bm25(products_fts)
+        FROM products_fts
+        JOIN products p ON p.id = products_fts.rowid
+        WHERE products_fts MATCH ?`
+	args := []any{query}
+
+	if search.OrganizationID != nil {
+		stmt += " AND p.organization_id = ?"
+		args = append(args, *search.OrganizationID)
+	}
+
+	stmt += " ORDER BY bm25(products_fts), p.id"
+
+	if search.Limit != nil {
+		stmt += " LIMIT ?"
+		args = append(args, *search.Limit)
+	}
+
+	rows, err := d.conn(ctx).QueryContext(ctx, stmt, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []*store.ProductSearchResult
+	for rows.Next() {
+		var p store.Product
+		var result store.ProductSearchResult
+		var rank float64
+		if err := rows.Scan(&p.ID, &p.OrganizationID, &p.Name, &p.Description, &p.Price, &p.Cover, &p.CreatedAt, &p.UpdatedAt,
+			&result.NameHighlight, &result.DescriptionSnippet, &rank); err != nil {
+			return nil, err
+		}
+		result.Product = &p
+		// bm25 returns lower values for better matches.
+		result.Score = -rank
+		results = append(results, &result)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// ftsMatchQuery turns search terms into an FTS5 query that requires every
+// term as a prefix. Terms are quoted so FTS5 syntax in user input is matched
+// literally.
+func ftsMatchQuery(terms []string) string {
+	var parts []string
+	for _, term := range terms {
+		term = strings.ReplaceAll(term, `"`, `""`)
+		if strings.TrimSpace(term) == "" {
+			continue
+		}
+		parts = append(parts, `"`+term+`"*`)
+	}
+	return strings.Join(parts, " ")
+}
+
+func (d *DB) DeleteProduct(ctx context.Context, organizationId, id int64) error {
+	_, err := d.conn(ctx).ExecContext(ctx, `DELETE FROM products WHERE id = ? AND organization_id = ?`, id, organizationId)
 	return err
 }
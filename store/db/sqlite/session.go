@@ -8,9 +8,9 @@ import (
 )
 
 func (d *DB) CreateSession(ctx context.Context, session *store.Session) (*store.Session, error) {
-	query := `INSERT INTO sessions (user_id, session_id, last_accessed_time) VALUES (?, ?, ?)`
+	query := `INSERT INTO sessions (user_id, session_id, created_time, last_accessed_time, expires_at, user_agent, client_ip) VALUES (?, ?, ?, ?, ?, ?, ?)`
 
-	if _, err := d.db.ExecContext(ctx, query, session.UserID, session.SessionID, session.LastAccessedTime); err != nil {
+	if _, err := d.conn(ctx).ExecContext(ctx, query, session.UserID, session.SessionID, session.CreatedTime, session.LastAccessedTime, session.ExpiresAt, session.UserAgent, session.ClientIP); err != nil {
 		return nil, err
 	}
 
@@ -18,9 +18,9 @@ func (d *DB) CreateSession(ctx context.Context, session *store.Session) (*store.
 }
 
 func (d *DB) GetUserSessions(ctx context.Context, userId int64) ([]*store.Session, error) {
-	query := `SELECT session_id, user_id, last_accessed_time FROM sessions WHERE user_id = ?`
+	query := `SELECT session_id, user_id, created_time, last_accessed_time, expires_at, user_agent, client_ip FROM sessions WHERE user_id = ? ORDER BY created_time`
 
-	rows, err := d.db.QueryContext(ctx, query, userId)
+	rows, err := d.conn(ctx).QueryContext(ctx, query, userId)
 	if err != nil {
 		return nil, err
 	}
@@ -29,7 +29,7 @@ func (d *DB) GetUserSessions(ctx context.Context, userId int64) ([]*store.Sessio
 	var sessions []*store.Session
 	for rows.Next() {
 		session := &store.Session{}
-		err := rows.Scan(&session.SessionID, &session.UserID, &session.LastAccessedTime)
+		err := rows.Scan(&session.SessionID, &session.UserID, &session.CreatedTime, &session.LastAccessedTime, &session.ExpiresAt, &session.UserAgent, &session.ClientIP)
 		if err != nil {
 			return nil, err
 		}
@@ -43,9 +43,48 @@ func (d *DB) GetUserSessions(ctx context.Context, userId int64) ([]*store.Sessio
 	return sessions, nil
 }
 
+func (d *DB) DeleteSession(ctx context.Context, sessionId string) error {
+	query := `DELETE FROM sessions WHERE session_id = ?`
+
+	_, err := d.conn(ctx).ExecContext(ctx, query, sessionId)
+	return err
+}
+
+func (d *DB) DeleteUserSessions(ctx context.Context, userId int64, exceptSessionId string) error {
+	query := `DELETE FROM sessions WHERE user_id = ? AND session_id != ?`
+
+	_, err := d.conn(ctx).ExecContext(ctx, query, userId, exceptSessionId)
+	return err
+}
+
+func (d *DB) DeleteExpiredSessions(ctx context.Context, lastAccessedBefore, expiresBefore time.Time) ([]int64, error) {
+	query := `DELETE FROM sessions WHERE last_accessed_time < ? OR expires_at < ? RETURNING user_id`
+
+	rows, err := d.conn(ctx).QueryContext(ctx, query, lastAccessedBefore, expiresBefore)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var userIds []int64
+	for rows.Next() {
+		var userId int64
+		if err := rows.Scan(&userId); err != nil {
+			return nil, err
+		}
+		userIds = append(userIds, userId)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return userIds, nil
+}
+
 func (d *DB) UpdateLastAccessedTime(ctx context.Context, sessionId string, lastAccessTime time.Time) error {
 	query := `UPDATE sessions SET last_accessed_time = ? WHERE session_id = ?`
 
-	_, err := d.db.ExecContext(ctx, query, lastAccessTime, sessionId)
+	_, err := d.conn(ctx).ExecContext(ctx, query, lastAccessTime, sessionId)
 	return err
 }
@@ -2,18 +2,30 @@ package sqlite
 
 import (
 	"database/sql"
+	"embed"
 	"errors"
+	"io/fs"
+	"strings"
 
 	"github.com/thetnaingtn/dirty-hand/internal/config"
-
-	_ "modernc.org/sqlite"
+	"github.com/thetnaingtn/dirty-hand/store/migrate"
 )
 
 var ErrNoDatabaseURL = errors.New("no database URL provided")
 
+// ErrNoFullTextSearch is returned by NewDB when SQLite was built without
+// FTS5, which product search needs. mattn/go-sqlite3 only includes it with
+// the sqlite_fts5 build tag.
+var ErrNoFullTextSearch = errors.New("SQLite was built without FTS5, build with -tags sqlite_fts5")
+
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
 type DB struct {
 	db     *sql.DB
 	config *config.Config
+
+	migrator *migrate.Migrator
 }
 
 func NewDB(cfg *config.Config) (*DB, error) {
@@ -21,7 +33,7 @@ func NewDB(cfg *config.Config) (*DB, error) {
 		return nil, ErrNoDatabaseURL
 	}
 
-	db, err := sql.Open("sqlite3", cfg.Database.DSN)
+	db, err := sql.Open("sqlite3", withImmediateTx(withForeignKeys(cfg.Database.DSN)))
 	if err != nil {
 		return nil, err
 	}
@@ -32,43 +44,73 @@ func NewDB(cfg *config.Config) (*DB, error) {
 		return nil, err
 	}
 
-	schema := `CREATE TABLE IF NOT EXISTS products (
-                id INTEGER PRIMARY KEY AUTOINCREMENT,
-                name TEXT NOT NULL,
-                description TEXT,
-                price REAL NOT NULL,
-                cover TEXT,
-                created_at DATETIME NOT NULL,
-                updated_at DATETIME NOT NULL
-        );
-
-        CREATE TABLE IF NOT EXISTS users (
-                id INTEGER PRIMARY KEY AUTOINCREMENT,
-                username TEXT NOT NULL UNIQUE,
-                password_hash TEXT NOT NULL,
-                role TEXT NOT NULL
-        );
-
-        CREATE TABLE IF NOT EXISTS sessions (
-                user_id INTEGER NOT NULL,
-                session_id CHAR(36) NOT NULL PRIMARY KEY,
-                last_accessed_time DATETIME NOT NULL,
-                FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
-        );
-		
-		`
-
-	if _, err := db.Exec(schema); err != nil {
+	migrations, err := loadMigrations()
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	var fullTextSearch bool
+	if err := db.QueryRow(`SELECT sqlite_compileoption_used('ENABLE_FTS5')`).Scan(&fullTextSearch); err != nil {
 		db.Close()
 		return nil, err
 	}
 
+	if !fullTextSearch {
+		db.Close()
+		return nil, ErrNoFullTextSearch
+	}
+
 	return &DB{
-		db:     db,
-		config: cfg,
+		db:       db,
+		config:   cfg,
+		migrator: migrate.New(db, migrate.QuestionMarkDialect, migrations),
 	}, nil
 }
 
+func loadMigrations() ([]*migrate.Migration, error) {
+	dir, err := fs.Sub(migrationFiles, "migrations")
+	if err != nil {
+		return nil, err
+	}
+
+	return migrate.Load(dir)
+}
+
+// Migrator returns the migrator of the SQLite schema.
+func (d *DB) Migrator() *migrate.Migrator {
+	return d.migrator
+}
+
+// withForeignKeys asks the driver to enable foreign keys on every pooled
+// connection, which a single PRAGMA statement cannot do.
+func withForeignKeys(dsn string) string {
+	if strings.Contains(dsn, "_foreign_keys=") || strings.Contains(dsn, "_fk=") {
+		return dsn
+	}
+
+	return withParam(dsn, "_foreign_keys=on")
+}
+
+// withImmediateTx asks the driver to begin transactions with the write lock.
+// A deferred transaction that reads before it writes fails, rather than
+// waits, when another connection wrote in the meantime.
+func withImmediateTx(dsn string) string {
+	if strings.Contains(dsn, "_txlock=") {
+		return dsn
+	}
+
+	return withParam(dsn, "_txlock=immediate")
+}
+
+func withParam(dsn, param string) string {
+	if strings.Contains(dsn, "?") {
+		return dsn + "&" + param
+	}
+
+	return dsn + "?" + param
+}
+
 func (d *DB) Close() error {
 	return d.db.Close()
 }
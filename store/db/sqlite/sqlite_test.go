@@ -0,0 +1,45 @@
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+
+	"github.com/thetnaingtn/dirty-hand/internal/config"
+	"github.com/thetnaingtn/dirty-hand/store"
+	"github.com/thetnaingtn/dirty-hand/store/storetest"
+)
+
+var databaseCount atomic.Int64
+
+// newTestDB returns a migrated in-memory database of its own.
+func newTestDB(t *testing.T) store.Driver {
+	t.Helper()
+
+	cfg := &config.Config{Database: config.DatabaseConfig{
+		Driver: config.DatabaseSQLite,
+		DSN:    fmt.Sprintf("file:storetest%d?mode=memory&cache=shared", databaseCount.Add(1)),
+	}}
+
+	// The memory database lives as long as a connection to it is open.
+	db, err := NewDB(cfg)
+	if errors.Is(err, ErrNoFullTextSearch) {
+		t.Skip(err)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	if _, err := db.Migrator().Up(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	return db
+}
+
+func TestDriver(t *testing.T) {
+	storetest.Run(t, newTestDB)
+}
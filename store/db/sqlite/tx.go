@@ -0,0 +1,131 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"math/rand/v2"
+	"time"
+
+	"github.com/mattn/go-sqlite3"
+)
+
+const (
+	// maxTxAttempts bounds how often WithTx runs a transaction that failed
+	// because the database was busy.
+	maxTxAttempts = 10
+	txRetryDelay  = 20 * time.Millisecond
+)
+
+// txContextKey is the context key of the transaction WithTx started on db.
+type txContextKey struct {
+	db *DB
+}
+
+// querier runs statements on the database or in a transaction.
+type querier interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// WithTx runs fn in a transaction, which is committed when fn returns nil and
+// rolled back otherwise. Calls made with the context passed to fn run in the
+// transaction, and nested calls join it. Transactions take the write lock
+// when they begin, and fn is run again when the database is busy.
+func (d *DB) WithTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txContextKey{d}).(*sql.Tx); ok {
+		return fn(ctx)
+	}
+
+	for attempt := 1; ; attempt++ {
+		err := d.runTx(ctx, fn)
+		if err == nil || attempt == maxTxAttempts || !isBusy(err) {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(retryDelay(attempt)):
+		}
+	}
+}
+
+// retryDelay returns how long to wait before running a transaction again.
+// The delay grows with the attempts and is jittered so that transactions
+// that conflicted do not conflict again.
+func retryDelay(attempt int) time.Duration {
+	return time.Duration(attempt)*txRetryDelay/2 + rand.N(time.Duration(attempt)*txRetryDelay)
+}
+
+func (d *DB) runTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	tx, err := d.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := fn(context.WithValue(ctx, txContextKey{d}, tx)); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// conn returns the transaction WithTx started for ctx, or the database when
+// there is none.
+func (d *DB) conn(ctx context.Context) querier {
+	if tx, ok := ctx.Value(txContextKey{d}).(*sql.Tx); ok {
+		return localTimes{tx}
+	}
+
+	return localTimes{d.db}
+}
+
+// localTimes binds times in the local time zone. SQLite stores times as text
+// with their UTC offset, which only compares correctly between times of the
+// same offset.
+type localTimes struct {
+	querier
+}
+
+func (q localTimes) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	return q.querier.ExecContext(ctx, query, inLocalTime(args)...)
+}
+
+func (q localTimes) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	return q.querier.QueryContext(ctx, query, inLocalTime(args)...)
+}
+
+func (q localTimes) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	return q.querier.QueryRowContext(ctx, query, inLocalTime(args)...)
+}
+
+func inLocalTime(args []any) []any {
+	converted := make([]any, len(args))
+	for i, arg := range args {
+		switch v := arg.(type) {
+		case time.Time:
+			arg = v.In(time.Local)
+		case *time.Time:
+			if v != nil {
+				arg = v.In(time.Local)
+			}
+		}
+		converted[i] = arg
+	}
+
+	return converted
+}
+
+// isBusy reports whether err means another connection held a lock the
+// transaction needed.
+func isBusy(err error) bool {
+	var sqliteErr sqlite3.Error
+	if !errors.As(err, &sqliteErr) {
+		return false
+	}
+
+	return sqliteErr.Code == sqlite3.ErrBusy || sqliteErr.Code == sqlite3.ErrLocked
+}
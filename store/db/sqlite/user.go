@@ -2,6 +2,8 @@ package sqlite
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"strings"
 
 	"github.com/thetnaingtn/dirty-hand/store"
@@ -14,7 +16,7 @@ func (d *DB) CreateUser(ctx context.Context, create *store.User) (*store.User, e
 
 	stmt := "INSERT INTO users (" + strings.Join(fields, ", ") + ") VALUES (" + strings.Join(placeholders, ", ") + ") RETURNING id"
 
-	if err := d.db.QueryRowContext(ctx, stmt, values...).Scan(&create.ID); err != nil {
+	if err := d.conn(ctx).QueryRowContext(ctx, stmt, values...).Scan(&create.ID); err != nil {
 		return nil, err
 	}
 
@@ -24,7 +26,7 @@ func (d *DB) CreateUser(ctx context.Context, create *store.User) (*store.User, e
 func (d *DB) ListUsers(ctx context.Context, filter *store.FindUser) ([]store.User, error) {
 	var users []store.User
 
-	stmt := "SELECT id, username, password_hash, role FROM users WHERE 1=1"
+	stmt := "SELECT id, username, password_hash, role, disabled FROM users WHERE 1=1"
 	var args []any
 
 	if filter != nil {
@@ -32,9 +34,31 @@ func (d *DB) ListUsers(ctx context.Context, filter *store.FindUser) ([]store.Use
 			stmt += " AND role = ?"
 			args = append(args, *filter.Role)
 		}
+
+		if filter.ID != nil {
+			stmt += " AND id = ?"
+			args = append(args, *filter.ID)
+		}
+
+		if filter.Username != nil {
+			stmt += " AND username = ?"
+			args = append(args, *filter.Username)
+		}
+
+		if filter.AfterID != nil {
+			stmt += " AND id > ?"
+			args = append(args, *filter.AfterID)
+		}
+	}
+
+	stmt += " ORDER BY id"
+
+	if filter != nil && filter.Limit != nil {
+		stmt += " LIMIT ?"
+		args = append(args, *filter.Limit)
 	}
 
-	rows, err := d.db.QueryContext(ctx, stmt, args...)
+	rows, err := d.conn(ctx).QueryContext(ctx, stmt, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -42,12 +66,16 @@ func (d *DB) ListUsers(ctx context.Context, filter *store.FindUser) ([]store.Use
 
 	for rows.Next() {
 		var user store.User
-		if err := rows.Scan(&user.ID, &user.Username, &user.PasswordHash, &user.Role); err != nil {
+		if err := rows.Scan(&user.ID, &user.Username, &user.PasswordHash, &user.Role, &user.Disabled); err != nil {
 			return nil, err
 		}
 		users = append(users, user)
 	}
 
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
 	return users, nil
 }
 
@@ -72,11 +100,48 @@ func (d *DB) GetUser(ctx context.Context, filter *store.FindUser) (*store.User,
 		}
 	}
 
-	stmt := "SELECT id, username, password_hash, role FROM users WHERE " + strings.Join(where, " AND ")
+	stmt := "SELECT id, username, password_hash, role, disabled FROM users WHERE " + strings.Join(where, " AND ")
 
-	if err := d.db.QueryRowContext(ctx, stmt, args...).Scan(&user.ID, &user.Username, &user.PasswordHash, &user.Role); err != nil {
+	if err := d.conn(ctx).QueryRowContext(ctx, stmt, args...).Scan(&user.ID, &user.Username, &user.PasswordHash, &user.Role, &user.Disabled); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
 		return nil, err
 	}
 
 	return &user, nil
 }
+
+func (d *DB) UpdateUser(ctx context.Context, update *store.UpdateUser) (*store.User, error) {
+	set, args := []string{}, []any{}
+
+	if v := update.PasswordHash; v != nil {
+		set = append(set, "password_hash = ?")
+		args = append(args, *v)
+	}
+
+	if v := update.Role; v != nil {
+		set = append(set, "role = ?")
+		args = append(args, *v)
+	}
+
+	if v := update.Disabled; v != nil {
+		set = append(set, "disabled = ?")
+		args = append(args, *v)
+	}
+
+	if len(set) > 0 {
+		args = append(args, update.ID)
+		stmt := "UPDATE users SET " + strings.Join(set, ", ") + " WHERE id = ?"
+		if _, err := d.conn(ctx).ExecContext(ctx, stmt, args...); err != nil {
+			return nil, err
+		}
+	}
+
+	return d.GetUser(ctx, &store.FindUser{ID: &update.ID})
+}
+
+func (d *DB) DeleteUser(ctx context.Context, id int64) error {
+	_, err := d.conn(ctx).ExecContext(ctx, `DELETE FROM users WHERE id = ?`, id)
+	return err
+}
@@ -0,0 +1,63 @@
+package sqlite
+
+import (
+	"context"
+	"strings"
+
+	"github.com/thetnaingtn/dirty-hand/store"
+)
+
+func (d *DB) CreateUserIdentity(ctx context.Context, create *store.UserIdentity) (*store.UserIdentity, error) {
+	stmt := `INSERT INTO user_identities (user_id, issuer, subject, created_time) VALUES (?, ?, ?, ?)`
+
+	if _, err := d.conn(ctx).ExecContext(ctx, stmt, create.UserID, create.Issuer, create.Subject, create.CreatedTime); err != nil {
+		return nil, err
+	}
+
+	return create, nil
+}
+
+func (d *DB) ListUserIdentities(ctx context.Context, find *store.FindUserIdentity) ([]*store.UserIdentity, error) {
+	where, args := []string{"1 = 1"}, []any{}
+
+	if find != nil {
+		if v := find.UserID; v != nil {
+			where = append(where, "user_id = ?")
+			args = append(args, *v)
+		}
+
+		if v := find.Issuer; v != nil {
+			where = append(where, "issuer = ?")
+			args = append(args, *v)
+		}
+
+		if v := find.Subject; v != nil {
+			where = append(where, "subject = ?")
+			args = append(args, *v)
+		}
+	}
+
+	stmt := "SELECT user_id, issuer, subject, created_time FROM user_identities WHERE " + strings.Join(where, " AND ") + " ORDER BY created_time"
+
+	rows, err := d.conn(ctx).QueryContext(ctx, stmt, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var identities []*store.UserIdentity
+	for rows.Next() {
+		var identity store.UserIdentity
+		if err := rows.Scan(&identity.UserID, &identity.Issuer, &identity.Subject, &identity.CreatedTime); err != nil {
+			return nil, err
+		}
+
+		identities = append(identities, &identity)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return identities, nil
+}
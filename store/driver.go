@@ -3,6 +3,8 @@ package store
 import (
 	"context"
 	"time"
+
+	"github.com/thetnaingtn/dirty-hand/store/migrate"
 )
 
 // Driver defines the data layer operations available to the store.
@@ -12,17 +14,84 @@ import (
 // contain application specific logic.
 type Driver interface {
 	Close() error
+	// Migrator returns the migrator that brings the schema of the database
+	// up to date.
+	Migrator() *migrate.Migrator
+	// WithTx runs fn in a transaction, which is committed when fn returns
+	// nil and rolled back otherwise. Calls made with the context passed to
+	// fn run in the transaction, and nested calls join it. fn may be run
+	// more than once when the transaction conflicts with another one.
+	WithTx(ctx context.Context, fn func(ctx context.Context) error) error
 
 	CreateProduct(ctx context.Context, p *Product) (*Product, error)
 	UpdateProduct(ctx context.Context, p *Product) (*Product, error)
-	ListProducts(ctx context.Context) ([]*Product, error)
-	DeleteProduct(ctx context.Context, id int64) error
+	GetProduct(ctx context.Context, organizationId, id int64) (*Product, error)
+	ListProducts(ctx context.Context, find *FindProduct) ([]*Product, error)
+	CountProducts(ctx context.Context, find *FindProduct) (int64, error)
+	SearchProducts(ctx context.Context, search *SearchProduct) ([]*ProductSearchResult, error)
+	DeleteProduct(ctx context.Context, organizationId, id int64) error
+
+	CreateOrganization(ctx context.Context, organization *Organization) (*Organization, error)
+	ListOrganizations(ctx context.Context, find *FindOrganization) ([]*Organization, error)
+	DeleteOrganization(ctx context.Context, id int64) error
+	UpsertMembership(ctx context.Context, membership *Membership) (*Membership, error)
+	ListMemberships(ctx context.Context, find *FindMembership) ([]*Membership, error)
+	DeleteMembership(ctx context.Context, organizationId, userId int64) error
 
 	CreateUser(ctx context.Context, user *User) (*User, error)
 	ListUsers(ctx context.Context, filter *FindUser) ([]User, error)
 	GetUser(ctx context.Context, filter *FindUser) (*User, error)
+	UpdateUser(ctx context.Context, update *UpdateUser) (*User, error)
+	DeleteUser(ctx context.Context, id int64) error
+
+	CreatePasswordResetToken(ctx context.Context, token *PasswordResetToken) (*PasswordResetToken, error)
+	GetPasswordResetToken(ctx context.Context, tokenHash string, now time.Time) (*PasswordResetToken, error)
+	ConsumePasswordResetToken(ctx context.Context, tokenHash string, now time.Time) (*PasswordResetToken, error)
+	DeletePasswordResetTokens(ctx context.Context, userId int64) error
+
+	CreateAccessToken(ctx context.Context, token *AccessToken) (*AccessToken, error)
+	ListAccessTokens(ctx context.Context, find *FindAccessToken) ([]*AccessToken, error)
+	UpdateAccessTokenLastUsedTime(ctx context.Context, id int64, lastUsedTime time.Time) error
+	DeleteAccessToken(ctx context.Context, id int64) error
+
+	GetUserTOTP(ctx context.Context, userId int64) (*UserTOTP, error)
+	UpsertUserTOTP(ctx context.Context, totp *UserTOTP) (*UserTOTP, error)
+	EnableUserTOTP(ctx context.Context, userId int64) error
+	AdvanceUserTOTPStep(ctx context.Context, userId int64, step int64) (bool, error)
+	DeleteUserTOTP(ctx context.Context, userId int64) error
+	CreateRecoveryCodes(ctx context.Context, userId int64, codeHashes []string) error
+	ConsumeRecoveryCode(ctx context.Context, userId int64, codeHash string, now time.Time) (bool, error)
+	DeleteRecoveryCodes(ctx context.Context, userId int64) error
+
+	CreateSecondFactorChallenge(ctx context.Context, challenge *SecondFactorChallenge) (*SecondFactorChallenge, error)
+	GetSecondFactorChallenge(ctx context.Context, tokenHash string, now time.Time) (*SecondFactorChallenge, error)
+	IncrementSecondFactorChallengeAttempts(ctx context.Context, tokenHash string) error
+	ConsumeSecondFactorChallenge(ctx context.Context, tokenHash string, now time.Time) (*SecondFactorChallenge, error)
+	DeleteExpiredSecondFactorChallenges(ctx context.Context, before time.Time) (int64, error)
+
+	GetSignInThrottle(ctx context.Context, key string) (*SignInThrottle, error)
+	UpsertSignInThrottle(ctx context.Context, throttle *SignInThrottle) (*SignInThrottle, error)
+	DeleteSignInThrottle(ctx context.Context, key string) error
+	DeleteStaleSignInThrottles(ctx context.Context, lastFailureBefore, now time.Time) (int64, error)
+
+	CreateInvitation(ctx context.Context, invitation *Invitation) (*Invitation, error)
+	ListInvitations(ctx context.Context, find *FindInvitation) ([]*Invitation, error)
+	ConsumeInvitation(ctx context.Context, codeHash string, userId int64, now time.Time) (*Invitation, error)
+	DeleteInvitation(ctx context.Context, id int64) error
+
+	CreateAuditEvent(ctx context.Context, event *AuditEvent) (*AuditEvent, error)
+	ListAuditEvents(ctx context.Context, find *FindAuditEvent) ([]*AuditEvent, error)
+
+	CreateUserIdentity(ctx context.Context, identity *UserIdentity) (*UserIdentity, error)
+	ListUserIdentities(ctx context.Context, find *FindUserIdentity) ([]*UserIdentity, error)
 
 	CreateSession(ctx context.Context, session *Session) (*Session, error)
 	GetUserSessions(ctx context.Context, id int64) ([]*Session, error)
 	UpdateLastAccessedTime(ctx context.Context, sessionId string, lastAccessTime time.Time) error
+	DeleteSession(ctx context.Context, sessionId string) error
+	DeleteUserSessions(ctx context.Context, userId int64, exceptSessionId string) error
+	// DeleteExpiredSessions deletes the sessions last accessed before
+	// lastAccessedBefore or expiring before expiresBefore and returns the
+	// user IDs of the deleted sessions.
+	DeleteExpiredSessions(ctx context.Context, lastAccessedBefore, expiresBefore time.Time) ([]int64, error)
 }
@@ -0,0 +1,97 @@
+package store
+
+import (
+	"context"
+	"time"
+)
+
+// Invitation lets someone sign up while registration is not open. Only a
+// hash of the invitation code is stored.
+type Invitation struct {
+	ID       int64
+	CodeHash string
+	Role     Role
+	// CreatedBy is 0 once the user who created the invitation is deleted.
+	CreatedBy   int64
+	CreatedTime time.Time
+	ExpiresTime time.Time
+	UsedTime    *time.Time
+	UsedBy      *int64
+}
+
+type FindInvitation struct {
+	ID       *int64
+	CodeHash *string
+}
+
+func (s *Store) CreateInvitation(ctx context.Context, invitation *Invitation) (*Invitation, error) {
+	invitation, err := s.driver.CreateInvitation(ctx, invitation)
+	if err != nil {
+		return nil, err
+	}
+
+	recordChange(ctx, "invitation", invitation.ID, nil, invitation.auditSnapshot())
+
+	return invitation, nil
+}
+
+func (s *Store) ListInvitations(ctx context.Context, find *FindInvitation) ([]*Invitation, error) {
+	return s.driver.ListInvitations(ctx, find)
+}
+
+// GetInvitation returns the first invitation matching find, or nil when
+// there is none.
+func (s *Store) GetInvitation(ctx context.Context, find *FindInvitation) (*Invitation, error) {
+	invitations, err := s.driver.ListInvitations(ctx, find)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(invitations) == 0 {
+		return nil, nil
+	}
+
+	return invitations[0], nil
+}
+
+// ConsumeInvitation marks the invitation with the given code hash as used by
+// a user and returns it. It returns nil when the invitation does not exist,
+// has expired or was already used.
+func (s *Store) ConsumeInvitation(ctx context.Context, codeHash string, userId int64) (*Invitation, error) {
+	var before *Invitation
+	if auditing(ctx) {
+		var err error
+		if before, err = s.GetInvitation(ctx, &FindInvitation{CodeHash: &codeHash}); err != nil {
+			return nil, err
+		}
+	}
+
+	invitation, err := s.driver.ConsumeInvitation(ctx, codeHash, userId, time.Now())
+	if err != nil || invitation == nil {
+		return nil, err
+	}
+
+	recordChange(ctx, "invitation", invitation.ID, before.auditSnapshot(), invitation.auditSnapshot())
+
+	return invitation, nil
+}
+
+func (s *Store) DeleteInvitation(ctx context.Context, id int64) error {
+	var before *Invitation
+	if auditing(ctx) {
+		var err error
+		if before, err = s.GetInvitation(ctx, &FindInvitation{ID: &id}); err != nil {
+			return err
+		}
+	}
+
+	if err := s.driver.DeleteInvitation(ctx, id); err != nil {
+		return err
+	}
+
+	if before != nil {
+		recordChange(ctx, "invitation", id, before.auditSnapshot(), nil)
+	}
+
+	return nil
+}
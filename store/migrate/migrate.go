@@ -0,0 +1,381 @@
+// Package migrate applies versioned schema migrations to a database.
+//
+// Migrations are SQL files named "<version>_<name>.up.sql", each with an
+// optional "<version>_<name>.down.sql" that reverts it. Applied migrations
+// are recorded in the schema_migrations table together with a checksum of
+// their up step, so that a migration edited after it was applied is
+// detected instead of silently diverging.
+package migrate
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+)
+
+var (
+	// ErrSchemaBehind is returned by Check when migrations are pending.
+	ErrSchemaBehind = errors.New("database schema is behind, run \"migrate up\"")
+	// ErrChecksumMismatch is returned when an applied migration was changed.
+	ErrChecksumMismatch = errors.New("applied migration was modified")
+	// ErrUnknownMigration is returned when the database has a migration
+	// applied that is not known, usually because a newer version ran.
+	ErrUnknownMigration = errors.New("database has an unknown migration applied")
+)
+
+// Migration is one versioned step of a schema.
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	// Down reverts Up. It is empty when the migration cannot be reverted.
+	Down string
+	// Checksum is the hex encoded SHA-256 of Up.
+	Checksum string
+}
+
+// Status is the state of one migration in a database.
+type Status struct {
+	Version     int64
+	Name        string
+	Applied     bool
+	AppliedTime time.Time
+	// Modified reports that the migration changed after it was applied.
+	Modified bool
+	// Unknown reports an applied migration missing from the known ones.
+	Unknown bool
+}
+
+// Dialect adapts the statements of the migrator to a database.
+type Dialect struct {
+	// Placeholder returns the bind parameter for the nth argument of a
+	// statement, counting from 1.
+	Placeholder func(n int) string
+}
+
+// QuestionMarkDialect suits databases that bind parameters with "?", such
+// as SQLite and MySQL.
+var QuestionMarkDialect = Dialect{Placeholder: func(int) string { return "?" }}
+
+var fileNamePattern = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+// Load reads the migrations in the root of fsys, ordered by version.
+func Load(fsys fs.FS) ([]*Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := map[int64]*Migration{}
+	for _, entry := range entries {
+		if entry.IsDir() || path.Ext(entry.Name()) != ".sql" {
+			continue
+		}
+
+		match := fileNamePattern.FindStringSubmatch(entry.Name())
+		if match == nil {
+			return nil, fmt.Errorf("invalid migration file name %q", entry.Name())
+		}
+
+		version, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil || version <= 0 {
+			return nil, fmt.Errorf("invalid migration version in %q", entry.Name())
+		}
+
+		data, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = migration
+		} else if migration.Name != match[2] {
+			return nil, fmt.Errorf("migration %d has two names, %q and %q", version, migration.Name, match[2])
+		}
+
+		if match[3] == "up" {
+			migration.Up = string(data)
+		} else {
+			migration.Down = string(data)
+		}
+	}
+
+	migrations := make([]*Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.Up == "" {
+			return nil, fmt.Errorf("migration %04d_%s has no up step", migration.Version, migration.Name)
+		}
+
+		sum := sha256.Sum256([]byte(migration.Up))
+		migration.Checksum = hex.EncodeToString(sum[:])
+		migrations = append(migrations, migration)
+	}
+
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+
+	return migrations, nil
+}
+
+// Migrator applies migrations to a database.
+type Migrator struct {
+	db         *sql.DB
+	dialect    Dialect
+	migrations []*Migration
+}
+
+func New(db *sql.DB, dialect Dialect, migrations []*Migration) *Migrator {
+	return &Migrator{
+		db:         db,
+		dialect:    dialect,
+		migrations: migrations,
+	}
+}
+
+// Latest returns the version of the newest known migration, or 0 when there
+// are none.
+func (m *Migrator) Latest() int64 {
+	if len(m.migrations) == 0 {
+		return 0
+	}
+
+	return m.migrations[len(m.migrations)-1].Version
+}
+
+type appliedMigration struct {
+	name        string
+	checksum    string
+	appliedTime time.Time
+}
+
+func (m *Migrator) ensureTable(ctx context.Context) error {
+	_, err := m.db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+                version BIGINT NOT NULL PRIMARY KEY,
+                name VARCHAR(255) NOT NULL,
+                checksum CHAR(64) NOT NULL,
+                applied_time TIMESTAMP NOT NULL
+        )`)
+	return err
+}
+
+func (m *Migrator) applied(ctx context.Context) (map[int64]appliedMigration, error) {
+	if err := m.ensureTable(ctx); err != nil {
+		return nil, err
+	}
+
+	rows, err := m.db.QueryContext(ctx, `SELECT version, name, checksum, applied_time FROM schema_migrations`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	applied := map[int64]appliedMigration{}
+	for rows.Next() {
+		var version int64
+		var migration appliedMigration
+		if err := rows.Scan(&version, &migration.name, &migration.checksum, &migration.appliedTime); err != nil {
+			return nil, err
+		}
+		applied[version] = migration
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return applied, nil
+}
+
+// Status returns the state of every known migration followed by the applied
+// migrations that are not known, ordered by version.
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var statuses []Status
+	for _, migration := range m.migrations {
+		status := Status{Version: migration.Version, Name: migration.Name}
+		if a, ok := applied[migration.Version]; ok {
+			status.Applied = true
+			status.AppliedTime = a.appliedTime
+			status.Modified = a.checksum != migration.Checksum
+			delete(applied, migration.Version)
+		}
+		statuses = append(statuses, status)
+	}
+
+	for version, a := range applied {
+		statuses = append(statuses, Status{
+			Version:     version,
+			Name:        a.name,
+			Applied:     true,
+			AppliedTime: a.appliedTime,
+			Unknown:     true,
+		})
+	}
+
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Version < statuses[j].Version })
+
+	return statuses, nil
+}
+
+// verify returns an error when an applied migration was modified or is not
+// known.
+func verify(statuses []Status) error {
+	for _, status := range statuses {
+		switch {
+		case status.Modified:
+			return fmt.Errorf("%w: %04d_%s", ErrChecksumMismatch, status.Version, status.Name)
+		case status.Unknown:
+			return fmt.Errorf("%w: %04d_%s", ErrUnknownMigration, status.Version, status.Name)
+		}
+	}
+
+	return nil
+}
+
+// Check returns nil when every known migration is applied unchanged, and
+// ErrSchemaBehind when some are pending.
+func (m *Migrator) Check(ctx context.Context) error {
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := verify(statuses); err != nil {
+		return err
+	}
+
+	for _, status := range statuses {
+		if !status.Applied {
+			return fmt.Errorf("%w: %04d_%s is pending", ErrSchemaBehind, status.Version, status.Name)
+		}
+	}
+
+	return nil
+}
+
+// Up applies the pending migrations in order, each in its own transaction,
+// and returns the applied ones. It stops at the first failure, leaving the
+// migrations before it applied.
+func (m *Migrator) Up(ctx context.Context) ([]*Migration, error) {
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := verify(statuses); err != nil {
+		return nil, err
+	}
+
+	applied := map[int64]bool{}
+	for _, status := range statuses {
+		applied[status.Version] = status.Applied
+	}
+
+	insert := fmt.Sprintf(`INSERT INTO schema_migrations (version, name, checksum, applied_time) VALUES (%s, %s, %s, %s)`,
+		m.dialect.Placeholder(1), m.dialect.Placeholder(2), m.dialect.Placeholder(3), m.dialect.Placeholder(4))
+
+	var done []*Migration
+	for _, migration := range m.migrations {
+		if applied[migration.Version] {
+			continue
+		}
+
+		err := m.inTx(ctx, func(tx *sql.Tx) error {
+			if _, err := tx.ExecContext(ctx, migration.Up); err != nil {
+				return err
+			}
+
+			_, err := tx.ExecContext(ctx, insert, migration.Version, migration.Name, migration.Checksum, time.Now().UTC())
+			return err
+		})
+		if err != nil {
+			return done, fmt.Errorf("failed to apply migration %04d_%s: %w", migration.Version, migration.Name, err)
+		}
+
+		done = append(done, migration)
+	}
+
+	return done, nil
+}
+
+// DownTo reverts the applied migrations newer than version, newest first and
+// each in its own transaction, and returns the reverted ones.
+func (m *Migrator) DownTo(ctx context.Context, version int64) ([]*Migration, error) {
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := verify(statuses); err != nil {
+		return nil, err
+	}
+
+	applied := map[int64]bool{}
+	for _, status := range statuses {
+		applied[status.Version] = status.Applied
+	}
+
+	// Refuse before changing anything when a step cannot be reverted.
+	var pending []*Migration
+	for i := len(m.migrations) - 1; i >= 0; i-- {
+		migration := m.migrations[i]
+		if migration.Version <= version || !applied[migration.Version] {
+			continue
+		}
+
+		if migration.Down == "" {
+			return nil, fmt.Errorf("migration %04d_%s cannot be reverted", migration.Version, migration.Name)
+		}
+
+		pending = append(pending, migration)
+	}
+
+	remove := fmt.Sprintf(`DELETE FROM schema_migrations WHERE version = %s`, m.dialect.Placeholder(1))
+
+	var done []*Migration
+	for _, migration := range pending {
+		err := m.inTx(ctx, func(tx *sql.Tx) error {
+			if _, err := tx.ExecContext(ctx, migration.Down); err != nil {
+				return err
+			}
+
+			_, err := tx.ExecContext(ctx, remove, migration.Version)
+			return err
+		})
+		if err != nil {
+			return done, fmt.Errorf("failed to revert migration %04d_%s: %w", migration.Version, migration.Name, err)
+		}
+
+		done = append(done, migration)
+	}
+
+	return done, nil
+}
+
+func (m *Migrator) inTx(ctx context.Context, fn func(tx *sql.Tx) error) error {
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	if err := fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,167 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// DefaultOrganizationName names the organization every database has. New
+// users join it, and product calls that select no organization act on it.
+const DefaultOrganizationName = "Default"
+
+// Organization is a tenant. Products belong to exactly one organization.
+type Organization struct {
+	ID          int64
+	Name        string
+	CreatedTime time.Time
+}
+
+type FindOrganization struct {
+	ID   *int64
+	Name *string
+	// MemberID limits the result to the organizations the user belongs to.
+	MemberID *int64
+}
+
+// Membership makes a user a member of an organization. Its role decides what
+// the user may do with the organization's products.
+type Membership struct {
+	OrganizationID int64
+	UserID         int64
+	Role           Role
+	CreatedTime    time.Time
+}
+
+type FindMembership struct {
+	OrganizationID *int64
+	UserID         *int64
+}
+
+func (s *Store) CreateOrganization(ctx context.Context, organization *Organization) (*Organization, error) {
+	organization, err := s.driver.CreateOrganization(ctx, organization)
+	if err != nil {
+		return nil, err
+	}
+
+	recordChange(ctx, "organization", organization.ID, nil, organization.auditSnapshot())
+
+	return organization, nil
+}
+
+func (s *Store) ListOrganizations(ctx context.Context, find *FindOrganization) ([]*Organization, error) {
+	return s.driver.ListOrganizations(ctx, find)
+}
+
+// GetOrganization returns the first organization matching find, or nil when
+// there is none.
+func (s *Store) GetOrganization(ctx context.Context, find *FindOrganization) (*Organization, error) {
+	organizations, err := s.driver.ListOrganizations(ctx, find)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(organizations) == 0 {
+		return nil, nil
+	}
+
+	return organizations[0], nil
+}
+
+// GetDefaultOrganization returns the organization named
+// DefaultOrganizationName.
+func (s *Store) GetDefaultOrganization(ctx context.Context) (*Organization, error) {
+	name := DefaultOrganizationName
+	organization, err := s.GetOrganization(ctx, &FindOrganization{Name: &name})
+	if err != nil {
+		return nil, err
+	}
+
+	if organization == nil {
+		return nil, errors.New("the default organization does not exist")
+	}
+
+	return organization, nil
+}
+
+// DeleteOrganization removes an organization together with its products and
+// memberships.
+func (s *Store) DeleteOrganization(ctx context.Context, id int64) error {
+	var before *Organization
+	if auditing(ctx) {
+		var err error
+		if before, err = s.GetOrganization(ctx, &FindOrganization{ID: &id}); err != nil {
+			return err
+		}
+	}
+
+	if err := s.driver.DeleteOrganization(ctx, id); err != nil {
+		return err
+	}
+
+	if before != nil {
+		recordChange(ctx, "organization", id, before.auditSnapshot(), nil)
+	}
+
+	return nil
+}
+
+// UpsertMembership adds a user to an organization, or changes the role of an
+// existing member.
+func (s *Store) UpsertMembership(ctx context.Context, membership *Membership) (*Membership, error) {
+	var before *Membership
+	if auditing(ctx) {
+		var err error
+		if before, err = s.GetMembership(ctx, &FindMembership{OrganizationID: &membership.OrganizationID, UserID: &membership.UserID}); err != nil {
+			return nil, err
+		}
+	}
+
+	membership, err := s.driver.UpsertMembership(ctx, membership)
+	if err != nil {
+		return nil, err
+	}
+
+	recordChange(ctx, "membership", membership.auditResourceID(), before.auditSnapshot(), membership.auditSnapshot())
+
+	return membership, nil
+}
+
+func (s *Store) ListMemberships(ctx context.Context, find *FindMembership) ([]*Membership, error) {
+	return s.driver.ListMemberships(ctx, find)
+}
+
+// GetMembership returns the first membership matching find, or nil when
+// there is none.
+func (s *Store) GetMembership(ctx context.Context, find *FindMembership) (*Membership, error) {
+	memberships, err := s.driver.ListMemberships(ctx, find)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(memberships) == 0 {
+		return nil, nil
+	}
+
+	return memberships[0], nil
+}
+
+func (s *Store) DeleteMembership(ctx context.Context, organizationId, userId int64) error {
+	var before *Membership
+	if auditing(ctx) {
+		var err error
+		if before, err = s.GetMembership(ctx, &FindMembership{OrganizationID: &organizationId, UserID: &userId}); err != nil {
+			return err
+		}
+	}
+
+	if err := s.driver.DeleteMembership(ctx, organizationId, userId); err != nil {
+		return err
+	}
+
+	if before != nil {
+		recordChange(ctx, "membership", before.auditResourceID(), before.auditSnapshot(), nil)
+	}
+
+	return nil
+}
@@ -0,0 +1,38 @@
+package store
+
+import (
+	"context"
+	"time"
+)
+
+// PasswordResetToken is a single-use token that lets a user set a new
+// password. Only a hash of the token is stored.
+type PasswordResetToken struct {
+	TokenHash   string
+	UserID      int64
+	CreatedTime time.Time
+	ExpiresTime time.Time
+}
+
+// CreatePasswordResetToken stores a new reset token for a user, replacing
+// any token issued to them before.
+func (s *Store) CreatePasswordResetToken(ctx context.Context, token *PasswordResetToken) (*PasswordResetToken, error) {
+	if err := s.driver.DeletePasswordResetTokens(ctx, token.UserID); err != nil {
+		return nil, err
+	}
+
+	return s.driver.CreatePasswordResetToken(ctx, token)
+}
+
+// GetPasswordResetToken returns the unused and unexpired token with the given
+// hash, or nil when there is none.
+func (s *Store) GetPasswordResetToken(ctx context.Context, tokenHash string) (*PasswordResetToken, error) {
+	return s.driver.GetPasswordResetToken(ctx, tokenHash, time.Now())
+}
+
+// ConsumePasswordResetToken marks the token with the given hash as used and
+// returns it. It returns nil when the token does not exist, has expired or
+// was already used.
+func (s *Store) ConsumePasswordResetToken(ctx context.Context, tokenHash string) (*PasswordResetToken, error) {
+	return s.driver.ConsumePasswordResetToken(ctx, tokenHash, time.Now())
+}
@@ -6,13 +6,69 @@ import (
 )
 
 type Product struct {
-	ID          int64     `json:"id"`
-	Name        string    `json:"name"`
-	Description string    `json:"description"`
-	Price       float64   `json:"price"`
-	Cover       string    `json:"cover"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID             int64     `json:"id"`
+	OrganizationID int64     `json:"organization_id"`
+	Name           string    `json:"name"`
+	Description    string    `json:"description"`
+	Price          float64   `json:"price"`
+	Cover          string    `json:"cover"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+type ProductOrderField string
+
+const (
+	ProductOrderByID        ProductOrderField = "id"
+	ProductOrderByName      ProductOrderField = "name"
+	ProductOrderByPrice     ProductOrderField = "price"
+	ProductOrderByCreatedAt ProductOrderField = "created_at"
+	ProductOrderByUpdatedAt ProductOrderField = "updated_at"
+)
+
+type FindProduct struct {
+	// OrganizationID limits the result to the products of one organization.
+	OrganizationID *int64
+
+	// Name matches products whose name contains the value, ignoring case.
+	Name          *string
+	MinPrice      *float64
+	MaxPrice      *float64
+	CreatedAfter  *time.Time
+	CreatedBefore *time.Time
+	UpdatedAfter  *time.Time
+	UpdatedBefore *time.Time
+
+	// OrderBy defaults to ProductOrderByID. Ties are broken by ID in the
+	// same direction so that the ordering is total.
+	OrderBy    ProductOrderField
+	Descending bool
+
+	// After is the last product of the previous page. When set only products
+	// that sort strictly after it are returned.
+	After *Product
+	Limit *int
+}
+
+type SearchProduct struct {
+	// OrganizationID limits the result to the products of one organization.
+	OrganizationID *int64
+
+	// Terms are matched against product names and descriptions. Every term
+	// must match and each one is treated as a prefix.
+	Terms []string
+	Limit *int
+}
+
+type ProductSearchResult struct {
+	Product *Product
+	// NameHighlight is the product name with matched terms wrapped in
+	// <mark></mark>; DescriptionSnippet is a fragment of the description
+	// around the matches, marked up the same way.
+	NameHighlight      string
+	DescriptionSnippet string
+	// Score is the relevance of the match, higher is better.
+	Score float64
 }
 
 // CreateProduct stores a new product in the database after applying business logic.
@@ -23,24 +79,82 @@ func (s *Store) CreateProduct(ctx context.Context, p *Product) (*Product, error)
 	now := time.Now()
 	p.CreatedAt = now
 	p.UpdatedAt = now
-	return s.driver.CreateProduct(ctx, p)
+
+	created, err := s.driver.CreateProduct(ctx, p)
+	if err != nil {
+		return nil, err
+	}
+
+	recordChange(ctx, "product", created.ID, nil, created.auditSnapshot())
+
+	return created, nil
 }
 
-// UpdateProduct updates an existing product in the database.
+// UpdateProduct updates an existing product of the organization given by
+// p.OrganizationID. It returns nil when the organization has no such product.
 func (s *Store) UpdateProduct(ctx context.Context, p *Product) (*Product, error) {
 	if p == nil {
 		return nil, nil
 	}
+	var before *Product
+	if auditing(ctx) {
+		var err error
+		if before, err = s.driver.GetProduct(ctx, p.OrganizationID, p.ID); err != nil {
+			return nil, err
+		}
+	}
+
 	p.UpdatedAt = time.Now()
-	return s.driver.UpdateProduct(ctx, p)
+	updated, err := s.driver.UpdateProduct(ctx, p)
+	if err != nil || updated == nil {
+		return nil, err
+	}
+
+	recordChange(ctx, "product", updated.ID, before.auditSnapshot(), updated.auditSnapshot())
+
+	return updated, nil
+}
+
+// GetProduct retrieves a single product of an organization by ID. It returns
+// nil when the organization has no product with the given ID.
+func (s *Store) GetProduct(ctx context.Context, organizationId, id int64) (*Product, error) {
+	return s.driver.GetProduct(ctx, organizationId, id)
 }
 
-// ListProducts retrieves all products from the database.
-func (s *Store) ListProducts(ctx context.Context) ([]*Product, error) {
-	return s.driver.ListProducts(ctx)
+// ListProducts retrieves the products matching the given filter.
+func (s *Store) ListProducts(ctx context.Context, find *FindProduct) ([]*Product, error) {
+	return s.driver.ListProducts(ctx, find)
 }
 
-// DeleteProduct removes a product by ID.
-func (s *Store) DeleteProduct(ctx context.Context, id int64) error {
-	return s.driver.DeleteProduct(ctx, id)
+// CountProducts returns the number of products matching the given filter,
+// ignoring its cursor and limit.
+func (s *Store) CountProducts(ctx context.Context, find *FindProduct) (int64, error) {
+	return s.driver.CountProducts(ctx, find)
+}
+
+// SearchProducts runs a full-text search over product names and
+// descriptions, returning the best matches first.
+func (s *Store) SearchProducts(ctx context.Context, search *SearchProduct) ([]*ProductSearchResult, error) {
+	return s.driver.SearchProducts(ctx, search)
+}
+
+// DeleteProduct removes a product of an organization by ID. It returns
+// false when the organization has no product with the given ID.
+func (s *Store) DeleteProduct(ctx context.Context, organizationId, id int64) (bool, error) {
+	var before *Product
+	err := s.WithTx(ctx, func(ctx context.Context) error {
+		var err error
+		if before, err = s.driver.GetProduct(ctx, organizationId, id); err != nil || before == nil {
+			return err
+		}
+
+		return s.driver.DeleteProduct(ctx, organizationId, id)
+	})
+	if err != nil || before == nil {
+		return false, err
+	}
+
+	recordChange(ctx, "product", id, before.auditSnapshot(), nil)
+
+	return true, nil
 }
@@ -2,7 +2,6 @@ package store
 
 import (
 	"context"
-	"log/slog"
 	"strconv"
 	"time"
 )
@@ -10,26 +9,108 @@ import (
 type Session struct {
 	SessionID        string
 	UserID           int64
+	CreatedTime      time.Time
 	LastAccessedTime time.Time
+	// ExpiresAt is the absolute expiry of the session.
+	ExpiresAt time.Time
+	UserAgent string
+	ClientIP  string
 }
 
+// CreateSession stores a session and refreshes the cached sessions of its
+// user with the ones read in the same transaction.
 func (s *Store) CreateSession(ctx context.Context, session *Session) (*Session, error) {
-	res, err := s.driver.CreateSession(ctx, session)
+	var created *Session
+	var sessions []*Session
+	err := s.WithTx(ctx, func(ctx context.Context) error {
+		var err error
+		if created, err = s.driver.CreateSession(ctx, session); err != nil {
+			return err
+		}
+
+		sessions, err = s.driver.GetUserSessions(ctx, session.UserID)
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	sessions, err := s.GetUserSessions(ctx, session.UserID)
+	key := strconv.FormatInt(session.UserID, 10)
+	afterCommit(ctx, func() { s.sessionCache.Set(key, sessions) })
+
+	return created, nil
+}
+
+// DeleteOtherSessions revokes every session of the given user except
+// keepSessionId.
+func (s *Store) DeleteOtherSessions(ctx context.Context, userId int64, keepSessionId string) error {
+	if err := s.driver.DeleteUserSessions(ctx, userId, keepSessionId); err != nil {
+		return err
+	}
+
+	afterCommit(ctx, func() { s.sessionCache.Delete(strconv.FormatInt(userId, 10)) })
+
+	return nil
+}
+
+// DeleteExpiredSessions deletes the sessions that are past the idle timeout
+// or their absolute expiry and evicts the affected users' cached sessions.
+// It returns the number of deleted sessions.
+func (s *Store) DeleteExpiredSessions(ctx context.Context, idleTimeout time.Duration) (int, error) {
+	now := time.Now()
+	lastAccessedBefore := time.Time{}
+	if idleTimeout > 0 {
+		lastAccessedBefore = now.Add(-idleTimeout)
+	}
+
+	userIds, err := s.driver.DeleteExpiredSessions(ctx, lastAccessedBefore, now)
 	if err != nil {
-		slog.Error("can't get all user sessions")
+		return 0, err
 	}
 
-	key := strconv.FormatInt(session.UserID, 10)
-	s.sessionCache.Set(key, sessions)
+	for _, userId := range userIds {
+		afterCommit(ctx, func() { s.sessionCache.Delete(strconv.FormatInt(userId, 10)) })
+	}
 
-	return res, err
+	return len(userIds), nil
 }
 
-func (s *Store) UpdateLastAccessedTime(ctx context.Context, sessionId string, lastAccessTime time.Time) error {
-	return s.driver.UpdateLastAccessedTime(ctx, sessionId, lastAccessTime)
+// DeleteSession revokes a session of the given user and drops the user's
+// cached sessions so the revocation takes effect immediately.
+func (s *Store) DeleteSession(ctx context.Context, userId int64, sessionId string) error {
+	if err := s.driver.DeleteSession(ctx, sessionId); err != nil {
+		return err
+	}
+
+	afterCommit(ctx, func() { s.sessionCache.Delete(strconv.FormatInt(userId, 10)) })
+
+	return nil
+}
+
+func (s *Store) UpdateLastAccessedTime(ctx context.Context, userId int64, sessionId string, lastAccessTime time.Time) error {
+	if err := s.driver.UpdateLastAccessedTime(ctx, sessionId, lastAccessTime); err != nil {
+		return err
+	}
+
+	// Cached sessions are shared between requests, so the cache entry is
+	// replaced with updated copies rather than modified in place.
+	key := strconv.FormatInt(userId, 10)
+	afterCommit(ctx, func() {
+		if item, ok := s.sessionCache.Get(key); ok {
+			if sessions, ok := item.([]*Session); ok {
+				updated := make([]*Session, 0, len(sessions))
+				for _, session := range sessions {
+					if session.SessionID == sessionId {
+						copied := *session
+						copied.LastAccessedTime = lastAccessTime
+						session = &copied
+					}
+					updated = append(updated, session)
+				}
+				s.sessionCache.Set(key, updated)
+			}
+		}
+	})
+
+	return nil
 }
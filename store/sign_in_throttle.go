@@ -0,0 +1,38 @@
+package store
+
+import (
+	"context"
+	"time"
+)
+
+// SignInThrottle counts recent failed sign-ins for a key, such as a username
+// or a client IP address, so that further attempts can be slowed down or
+// refused.
+type SignInThrottle struct {
+	Key             string
+	Failures        int
+	LastFailureTime time.Time
+	// LockedUntil is zero unless the key is locked out.
+	LockedUntil time.Time
+}
+
+// GetSignInThrottle returns the throttle of a key, or nil when the key has
+// no recorded failures.
+func (s *Store) GetSignInThrottle(ctx context.Context, key string) (*SignInThrottle, error) {
+	return s.driver.GetSignInThrottle(ctx, key)
+}
+
+func (s *Store) UpsertSignInThrottle(ctx context.Context, throttle *SignInThrottle) (*SignInThrottle, error) {
+	return s.driver.UpsertSignInThrottle(ctx, throttle)
+}
+
+func (s *Store) DeleteSignInThrottle(ctx context.Context, key string) error {
+	return s.driver.DeleteSignInThrottle(ctx, key)
+}
+
+// DeleteStaleSignInThrottles deletes the throttles whose last failure was
+// before lastFailureBefore and that are not locked out, and returns how many
+// were deleted.
+func (s *Store) DeleteStaleSignInThrottles(ctx context.Context, lastFailureBefore time.Time) (int64, error) {
+	return s.driver.DeleteStaleSignInThrottles(ctx, lastFailureBefore, time.Now())
+}
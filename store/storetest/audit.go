@@ -0,0 +1,39 @@
+package storetest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/thetnaingtn/dirty-hand/store"
+)
+
+func testAuditEvents(t *testing.T, driver store.Driver) {
+	ctx := context.Background()
+
+	actorId := int64(7)
+	event, err := driver.CreateAuditEvent(ctx, &store.AuditEvent{
+		CreatedTime:  now(),
+		ActorID:      &actorId,
+		Method:       "/api.v1.ProductService/CreateProduct",
+		ResourceType: "product",
+		ResourceID:   "1",
+		Changes: []store.AuditChange{{
+			ResourceType: "product",
+			ResourceID:   "1",
+			Action:       store.AuditActionCreate,
+			After:        map[string]any{"name": "Red kettle"},
+		}},
+		Outcome: "success",
+	})
+	if err != nil {
+		t.Fatalf("CreateAuditEvent: %v", err)
+	}
+
+	events, err := driver.ListAuditEvents(ctx, &store.FindAuditEvent{ActorID: &actorId})
+	if err != nil {
+		t.Fatalf("ListAuditEvents: %v", err)
+	}
+	if len(events) != 1 || events[0].ID != event.ID || len(events[0].Changes) != 1 || events[0].Changes[0].After["name"] != "Red kettle" {
+		t.Fatalf("ListAuditEvents = %+v, want the created event", events)
+	}
+}
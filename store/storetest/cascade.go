@@ -0,0 +1,192 @@
+package storetest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/thetnaingtn/dirty-hand/store"
+)
+
+// testUserCascade deletes a user and checks that everything that belongs to
+// them goes too, while the invitations they created or used are kept.
+func testUserCascade(t *testing.T, driver store.Driver) {
+	ctx := context.Background()
+	current := now()
+
+	organization := createOrganization(t, driver, "acme")
+	alice := createUser(t, driver, "alice", store.RoleAdmin)
+	bob := createUser(t, driver, "bob", store.RoleProductView)
+
+	for _, user := range []*store.User{alice, bob} {
+		mustDo(t, "UpsertMembership", func() error {
+			_, err := driver.UpsertMembership(ctx, &store.Membership{OrganizationID: organization.ID, UserID: user.ID, Role: store.RoleProductEdit, CreatedTime: current})
+			return err
+		})
+		mustDo(t, "CreateSession", func() error {
+			_, err := driver.CreateSession(ctx, &store.Session{SessionID: "session-of-" + user.Username, UserID: user.ID, CreatedTime: current,
+				LastAccessedTime: current, ExpiresAt: current.Add(time.Hour)})
+			return err
+		})
+		mustDo(t, "CreateAccessToken", func() error {
+			_, err := driver.CreateAccessToken(ctx, &store.AccessToken{UserID: user.ID, Name: "ci", TokenHash: "token-of-" + user.Username,
+				Scopes: []store.Role{store.RoleProductView}, CreatedTime: current})
+			return err
+		})
+		mustDo(t, "CreateUserIdentity", func() error {
+			_, err := driver.CreateUserIdentity(ctx, &store.UserIdentity{UserID: user.ID, Issuer: "https://login.example.com", Subject: user.Username,
+				CreatedTime: current})
+			return err
+		})
+		mustDo(t, "UpsertUserTOTP", func() error {
+			_, err := driver.UpsertUserTOTP(ctx, &store.UserTOTP{UserID: user.ID, Secret: "secret", CreatedTime: current})
+			return err
+		})
+		mustDo(t, "CreateRecoveryCodes", func() error {
+			return driver.CreateRecoveryCodes(ctx, user.ID, []string{"code-of-" + user.Username})
+		})
+		mustDo(t, "CreateSecondFactorChallenge", func() error {
+			_, err := driver.CreateSecondFactorChallenge(ctx, &store.SecondFactorChallenge{TokenHash: "challenge-of-" + user.Username, UserID: user.ID,
+				CreatedTime: current, ExpiresTime: current.Add(time.Hour)})
+			return err
+		})
+		mustDo(t, "CreatePasswordResetToken", func() error {
+			_, err := driver.CreatePasswordResetToken(ctx, &store.PasswordResetToken{TokenHash: "reset-of-" + user.Username, UserID: user.ID,
+				CreatedTime: current, ExpiresTime: current.Add(time.Hour)})
+			return err
+		})
+	}
+
+	invitation, err := driver.CreateInvitation(ctx, &store.Invitation{CodeHash: "invitation", Role: store.RoleProductView, CreatedBy: alice.ID,
+		CreatedTime: current, ExpiresTime: current.Add(time.Hour)})
+	if err != nil {
+		t.Fatalf("CreateInvitation: %v", err)
+	}
+	if used, err := driver.ConsumeInvitation(ctx, invitation.CodeHash, alice.ID, current); err != nil || used == nil {
+		t.Fatalf("ConsumeInvitation = %+v, %v; want the invitation", used, err)
+	}
+
+	if err := driver.DeleteUser(ctx, alice.ID); err != nil {
+		t.Fatalf("DeleteUser: %v", err)
+	}
+
+	// Everything of alice is gone and everything of bob is kept.
+	for _, test := range []struct {
+		user *store.User
+		want int
+	}{{alice, 0}, {bob, 1}} {
+		counts := map[string]int{}
+
+		memberships, err := driver.ListMemberships(ctx, &store.FindMembership{UserID: &test.user.ID})
+		mustNot(t, "ListMemberships", err)
+		counts["memberships"] = len(memberships)
+
+		sessions, err := driver.GetUserSessions(ctx, test.user.ID)
+		mustNot(t, "GetUserSessions", err)
+		counts["sessions"] = len(sessions)
+
+		tokens, err := driver.ListAccessTokens(ctx, &store.FindAccessToken{UserID: &test.user.ID})
+		mustNot(t, "ListAccessTokens", err)
+		counts["access tokens"] = len(tokens)
+
+		identities, err := driver.ListUserIdentities(ctx, &store.FindUserIdentity{UserID: &test.user.ID})
+		mustNot(t, "ListUserIdentities", err)
+		counts["identities"] = len(identities)
+
+		totp, err := driver.GetUserTOTP(ctx, test.user.ID)
+		mustNot(t, "GetUserTOTP", err)
+		counts["totp"] = boolCount(totp != nil)
+
+		consumed, err := driver.ConsumeRecoveryCode(ctx, test.user.ID, "code-of-"+test.user.Username, current)
+		mustNot(t, "ConsumeRecoveryCode", err)
+		counts["recovery codes"] = boolCount(consumed)
+
+		challenge, err := driver.GetSecondFactorChallenge(ctx, "challenge-of-"+test.user.Username, current)
+		mustNot(t, "GetSecondFactorChallenge", err)
+		counts["second factor challenges"] = boolCount(challenge != nil)
+
+		token, err := driver.GetPasswordResetToken(ctx, "reset-of-"+test.user.Username, current)
+		mustNot(t, "GetPasswordResetToken", err)
+		counts["password reset tokens"] = boolCount(token != nil)
+
+		for name, count := range counts {
+			if count != test.want {
+				t.Errorf("%s of %s after deleting alice: %d, want %d", name, test.user.Username, count, test.want)
+			}
+		}
+	}
+
+	invitations, err := driver.ListInvitations(ctx, &store.FindInvitation{ID: &invitation.ID})
+	if err != nil || len(invitations) != 1 {
+		t.Fatalf("ListInvitations after deleting its creator = %+v, %v; want the invitation", invitations, err)
+	}
+	if invitations[0].UsedTime == nil {
+		t.Errorf("invitation after deleting its user = %+v, want it still used", invitations[0])
+	}
+}
+
+// testOrganizationCascade deletes an organization and checks that its
+// products and memberships go too, but not its members.
+func testOrganizationCascade(t *testing.T, driver store.Driver) {
+	ctx := context.Background()
+	current := now()
+
+	acme := createOrganization(t, driver, "acme")
+	other := createOrganization(t, driver, "other")
+	alice := createUser(t, driver, "alice", store.RoleProductView)
+
+	for _, organization := range []*store.Organization{acme, other} {
+		createProduct(t, driver, &store.Product{OrganizationID: organization.ID, Name: "Red kettle", Price: 12.5, CreatedAt: current, UpdatedAt: current})
+		if _, err := driver.UpsertMembership(ctx, &store.Membership{OrganizationID: organization.ID, UserID: alice.ID, Role: store.RoleProductEdit,
+			CreatedTime: current}); err != nil {
+			t.Fatalf("UpsertMembership: %v", err)
+		}
+	}
+
+	if err := driver.DeleteOrganization(ctx, acme.ID); err != nil {
+		t.Fatalf("DeleteOrganization: %v", err)
+	}
+
+	for _, test := range []struct {
+		organization *store.Organization
+		want         int
+	}{{acme, 0}, {other, 1}} {
+		count, err := driver.CountProducts(ctx, &store.FindProduct{OrganizationID: &test.organization.ID})
+		if err != nil || count != int64(test.want) {
+			t.Errorf("CountProducts of %s = %d, %v; want %d", test.organization.Name, count, err, test.want)
+		}
+
+		memberships, err := driver.ListMemberships(ctx, &store.FindMembership{OrganizationID: &test.organization.ID})
+		if err != nil || len(memberships) != test.want {
+			t.Errorf("ListMemberships of %s = %+v, %v; want %d", test.organization.Name, memberships, err, test.want)
+		}
+	}
+
+	if user, err := driver.GetUser(ctx, &store.FindUser{ID: &alice.ID}); err != nil || user == nil {
+		t.Errorf("GetUser of a member of the deleted organization = %+v, %v; want the user", user, err)
+	}
+}
+
+func mustDo(t *testing.T, name string, do func() error) {
+	t.Helper()
+
+	if err := do(); err != nil {
+		t.Fatalf("%s: %v", name, err)
+	}
+}
+
+func mustNot(t *testing.T, name string, err error) {
+	t.Helper()
+
+	if err != nil {
+		t.Fatalf("%s: %v", name, err)
+	}
+}
+
+func boolCount(ok bool) int {
+	if ok {
+		return 1
+	}
+
+	return 0
+}
@@ -0,0 +1,129 @@
+package storetest
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/thetnaingtn/dirty-hand/store"
+)
+
+// testConcurrentWrites creates and updates rows from many goroutines at once
+// and checks that none of the writes is lost.
+func testConcurrentWrites(t *testing.T, driver store.Driver) {
+	ctx := context.Background()
+	current := now()
+
+	organization := createOrganization(t, driver, "acme")
+	user := createUser(t, driver, "alice", store.RoleAdmin)
+
+	const writers, writes = 8, 10
+	var wg sync.WaitGroup
+	errs := make(chan error, writers)
+	for i := range writers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- func() error {
+				for j := range writes {
+					if _, err := driver.CreateProduct(ctx, &store.Product{OrganizationID: organization.ID, Name: fmt.Sprintf("Kettle %d-%d", i, j),
+						Price: 12.5, CreatedAt: current, UpdatedAt: current}); err != nil {
+						return err
+					}
+
+					sessionId := fmt.Sprintf("%018d%018d", i, j)
+					if _, err := driver.CreateSession(ctx, &store.Session{SessionID: sessionId, UserID: user.ID, CreatedTime: current,
+						LastAccessedTime: current, ExpiresAt: current.Add(time.Hour)}); err != nil {
+						return err
+					}
+					if err := driver.UpdateLastAccessedTime(ctx, sessionId, current.Add(time.Minute)); err != nil {
+						return err
+					}
+				}
+				return nil
+			}()
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("concurrent write: %v", err)
+		}
+	}
+
+	count, err := driver.CountProducts(ctx, &store.FindProduct{OrganizationID: &organization.ID})
+	if err != nil || count != writers*writes {
+		t.Fatalf("CountProducts = %d, %v; want %d", count, err, writers*writes)
+	}
+
+	sessions, err := driver.GetUserSessions(ctx, user.ID)
+	if err != nil || len(sessions) != writers*writes {
+		t.Fatalf("GetUserSessions returned %d sessions, %v; want %d", len(sessions), err, writers*writes)
+	}
+	for _, session := range sessions {
+		if !session.LastAccessedTime.Equal(current.Add(time.Minute)) {
+			t.Fatalf("session %s was last accessed at %v, want the updated time", session.SessionID, session.LastAccessedTime)
+		}
+	}
+}
+
+// testConcurrentBootstrap signs up users concurrently, each making itself
+// the admin when there is none yet, and checks that only one of them does.
+func testConcurrentBootstrap(t *testing.T, driver store.Driver) {
+	ctx := context.Background()
+
+	const signUps = 8
+	var wg sync.WaitGroup
+	errs := make(chan error, signUps)
+	for i := range signUps {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- driver.WithTx(ctx, func(ctx context.Context) error {
+				adminRole := store.RoleAdmin
+				admins, err := driver.ListUsers(ctx, &store.FindUser{Role: &adminRole})
+				if err != nil {
+					return err
+				}
+
+				// Give the other sign-ups time to check for admins too.
+				time.Sleep(10 * time.Millisecond)
+
+				role := store.RoleProductView
+				if len(admins) == 0 {
+					role = store.RoleAdmin
+				}
+
+				_, err = driver.CreateUser(ctx, &store.User{Username: fmt.Sprintf("user%d", i), PasswordHash: "hash", Role: role})
+				return err
+			})
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("WithTx: %v", err)
+		}
+	}
+
+	all, err := driver.ListUsers(ctx, &store.FindUser{})
+	if err != nil {
+		t.Fatalf("ListUsers: %v", err)
+	}
+
+	admins := 0
+	for _, user := range all {
+		if user.Role == store.RoleAdmin {
+			admins++
+		}
+	}
+	if len(all) != signUps || admins != 1 {
+		t.Fatalf("got %d users of which %d admins, want %d users of which 1 admin", len(all), admins, signUps)
+	}
+}
@@ -0,0 +1,98 @@
+package storetest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/thetnaingtn/dirty-hand/store"
+)
+
+// testNotFound checks that looking up what does not exist returns nil and no
+// error, and that deleting it is not an error either.
+func testNotFound(t *testing.T, driver store.Driver) {
+	ctx := context.Background()
+	missing := int64(1 << 40)
+	username := "nobody"
+	current := now()
+
+	if user, err := driver.GetUser(ctx, &store.FindUser{ID: &missing}); err != nil || user != nil {
+		t.Errorf("GetUser by ID = %+v, %v; want nil, nil", user, err)
+	}
+	if user, err := driver.GetUser(ctx, &store.FindUser{Username: &username}); err != nil || user != nil {
+		t.Errorf("GetUser by username = %+v, %v; want nil, nil", user, err)
+	}
+	if users, err := driver.ListUsers(ctx, &store.FindUser{ID: &missing}); err != nil || len(users) != 0 {
+		t.Errorf("ListUsers = %+v, %v; want none", users, err)
+	}
+
+	role := store.RoleAdmin
+	if user, err := driver.UpdateUser(ctx, &store.UpdateUser{ID: missing, Role: &role}); err != nil || user != nil {
+		t.Errorf("UpdateUser = %+v, %v; want nil, nil", user, err)
+	}
+
+	if product, err := driver.GetProduct(ctx, missing, missing); err != nil || product != nil {
+		t.Errorf("GetProduct = %+v, %v; want nil, nil", product, err)
+	}
+	if product, err := driver.UpdateProduct(ctx, &store.Product{ID: missing, OrganizationID: missing, Name: "Red kettle",
+		CreatedAt: current, UpdatedAt: current}); err != nil || product != nil {
+		t.Errorf("UpdateProduct = %+v, %v; want nil, nil", product, err)
+	}
+	if products, err := driver.ListProducts(ctx, &store.FindProduct{OrganizationID: &missing}); err != nil || len(products) != 0 {
+		t.Errorf("ListProducts = %+v, %v; want none", products, err)
+	}
+
+	if sessions, err := driver.GetUserSessions(ctx, missing); err != nil || len(sessions) != 0 {
+		t.Errorf("GetUserSessions = %+v, %v; want none", sessions, err)
+	}
+	if userIds, err := driver.DeleteExpiredSessions(ctx, current, current); err != nil || len(userIds) != 0 {
+		t.Errorf("DeleteExpiredSessions = %v, %v; want none", userIds, err)
+	}
+
+	if totp, err := driver.GetUserTOTP(ctx, missing); err != nil || totp != nil {
+		t.Errorf("GetUserTOTP = %+v, %v; want nil, nil", totp, err)
+	}
+	if consumed, err := driver.ConsumeRecoveryCode(ctx, missing, "hash", current); err != nil || consumed {
+		t.Errorf("ConsumeRecoveryCode = %v, %v; want false, nil", consumed, err)
+	}
+	if challenge, err := driver.GetSecondFactorChallenge(ctx, "hash", current); err != nil || challenge != nil {
+		t.Errorf("GetSecondFactorChallenge = %+v, %v; want nil, nil", challenge, err)
+	}
+	if challenge, err := driver.ConsumeSecondFactorChallenge(ctx, "hash", current); err != nil || challenge != nil {
+		t.Errorf("ConsumeSecondFactorChallenge = %+v, %v; want nil, nil", challenge, err)
+	}
+	if token, err := driver.GetPasswordResetToken(ctx, "hash", current); err != nil || token != nil {
+		t.Errorf("GetPasswordResetToken = %+v, %v; want nil, nil", token, err)
+	}
+	if token, err := driver.ConsumePasswordResetToken(ctx, "hash", current); err != nil || token != nil {
+		t.Errorf("ConsumePasswordResetToken = %+v, %v; want nil, nil", token, err)
+	}
+	if invitation, err := driver.ConsumeInvitation(ctx, "hash", missing, current); err != nil || invitation != nil {
+		t.Errorf("ConsumeInvitation = %+v, %v; want nil, nil", invitation, err)
+	}
+	if throttle, err := driver.GetSignInThrottle(ctx, "user:nobody"); err != nil || throttle != nil {
+		t.Errorf("GetSignInThrottle = %+v, %v; want nil, nil", throttle, err)
+	}
+
+	deletes := []struct {
+		name   string
+		delete func() error
+	}{
+		{"DeleteUser", func() error { return driver.DeleteUser(ctx, missing) }},
+		{"DeleteProduct", func() error { return driver.DeleteProduct(ctx, missing, missing) }},
+		{"DeleteOrganization", func() error { return driver.DeleteOrganization(ctx, missing) }},
+		{"DeleteMembership", func() error { return driver.DeleteMembership(ctx, missing, missing) }},
+		{"DeleteSession", func() error { return driver.DeleteSession(ctx, "missing") }},
+		{"DeleteUserSessions", func() error { return driver.DeleteUserSessions(ctx, missing, "") }},
+		{"DeleteAccessToken", func() error { return driver.DeleteAccessToken(ctx, missing) }},
+		{"DeleteUserTOTP", func() error { return driver.DeleteUserTOTP(ctx, missing) }},
+		{"DeleteRecoveryCodes", func() error { return driver.DeleteRecoveryCodes(ctx, missing) }},
+		{"DeletePasswordResetTokens", func() error { return driver.DeletePasswordResetTokens(ctx, missing) }},
+		{"DeleteInvitation", func() error { return driver.DeleteInvitation(ctx, missing) }},
+		{"DeleteSignInThrottle", func() error { return driver.DeleteSignInThrottle(ctx, "user:nobody") }},
+	}
+	for _, test := range deletes {
+		if err := test.delete(); err != nil {
+			t.Errorf("%s: %v", test.name, err)
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package storetest
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/thetnaingtn/dirty-hand/store"
+)
+
+func createProduct(t *testing.T, driver store.Driver, product *store.Product) *store.Product {
+	t.Helper()
+
+	product, err := driver.CreateProduct(context.Background(), product)
+	if err != nil {
+		t.Fatalf("CreateProduct: %v", err)
+	}
+
+	return product
+}
+
+func testProducts(t *testing.T, driver store.Driver) {
+	ctx := context.Background()
+	organization := createOrganization(t, driver, "acme")
+	other := createOrganization(t, driver, "other")
+
+	created := now()
+	kettle := createProduct(t, driver, &store.Product{OrganizationID: organization.ID, Name: "Red kettle", Description: "A steel kettle that whistles",
+		Price: 12.5, CreatedAt: created, UpdatedAt: created})
+	mug := createProduct(t, driver, &store.Product{OrganizationID: organization.ID, Name: "Blue mug", Description: "Ceramic",
+		Price: 3.99, CreatedAt: created.Add(time.Second), UpdatedAt: created.Add(time.Second)})
+
+	if kettle.ID == 0 || mug.ID == 0 || kettle.ID == mug.ID {
+		t.Fatalf("CreateProduct returned IDs %d and %d, want two distinct IDs", kettle.ID, mug.ID)
+	}
+
+	got, err := driver.GetProduct(ctx, organization.ID, kettle.ID)
+	if err != nil {
+		t.Fatalf("GetProduct: %v", err)
+	}
+	if got == nil || got.Name != "Red kettle" || got.Price != 12.5 || !got.CreatedAt.Equal(created) {
+		t.Fatalf("GetProduct = %+v, want the kettle", got)
+	}
+
+	if got, err := driver.GetProduct(ctx, other.ID, kettle.ID); err != nil || got != nil {
+		t.Fatalf("GetProduct of another organization = %+v, %v; want nil, nil", got, err)
+	}
+
+	name := "MUG"
+	products, err := driver.ListProducts(ctx, &store.FindProduct{OrganizationID: &organization.ID, Name: &name})
+	if err != nil {
+		t.Fatalf("ListProducts: %v", err)
+	}
+	if len(products) != 1 || products[0].ID != mug.ID {
+		t.Fatalf("ListProducts by name = %+v, want the mug", products)
+	}
+
+	limit := 1
+	page, err := driver.ListProducts(ctx, &store.FindProduct{OrderBy: store.ProductOrderByPrice, Limit: &limit})
+	if err != nil || len(page) != 1 || page[0].ID != mug.ID {
+		t.Fatalf("first page by price = %+v, %v; want the mug", page, err)
+	}
+	page, err = driver.ListProducts(ctx, &store.FindProduct{OrderBy: store.ProductOrderByPrice, After: page[0], Limit: &limit})
+	if err != nil || len(page) != 1 || page[0].ID != kettle.ID {
+		t.Fatalf("second page by price = %+v, %v; want the kettle", page, err)
+	}
+
+	if count, err := driver.CountProducts(ctx, &store.FindProduct{OrganizationID: &other.ID}); err != nil || count != 0 {
+		t.Fatalf("CountProducts of another organization = %d, %v; want 0", count, err)
+	}
+
+	kettle.Price = 14
+	kettle.UpdatedAt = created.Add(time.Minute)
+	updated, err := driver.UpdateProduct(ctx, kettle)
+	if err != nil || updated == nil || updated.Price != 14 || !updated.UpdatedAt.Equal(kettle.UpdatedAt) {
+		t.Fatalf("UpdateProduct = %+v, %v; want the new price", updated, err)
+	}
+
+	moved := *kettle
+	moved.OrganizationID = other.ID
+	if updated, err := driver.UpdateProduct(ctx, &moved); err != nil || updated != nil {
+		t.Fatalf("UpdateProduct in another organization = %+v, %v; want nil, nil", updated, err)
+	}
+
+	if err := driver.DeleteOrganization(ctx, organization.ID); err != nil {
+		t.Fatalf("DeleteOrganization: %v", err)
+	}
+	if count, err := driver.CountProducts(ctx, nil); err != nil || count != 0 {
+		t.Fatalf("CountProducts after deleting the organization = %d, %v; want 0", count, err)
+	}
+}
+
+func testProductSearch(t *testing.T, driver store.Driver) {
+	ctx := context.Background()
+	organization := createOrganization(t, driver, "acme")
+	other := createOrganization(t, driver, "other")
+
+	created := now()
+	kettle := createProduct(t, driver, &store.Product{OrganizationID: organization.ID, Name: "Red kettle", Description: "A steel kettle that whistles",
+		Price: 12.5, CreatedAt: created, UpdatedAt: created})
+	createProduct(t, driver, &store.Product{OrganizationID: other.ID, Name: "Green kettle", Description: "Also whistles",
+		Price: 10, CreatedAt: created, UpdatedAt: created})
+
+	results, err := driver.SearchProducts(ctx, &store.SearchProduct{OrganizationID: &organization.ID, Terms: []string{"whist"}})
+	if err != nil {
+		t.Fatalf("SearchProducts: %v", err)
+	}
+	if len(results) != 1 || results[0].Product.ID != kettle.ID {
+		t.Fatalf("SearchProducts = %+v, want the kettle of the organization", results)
+	}
+	if !strings.Contains(results[0].DescriptionSnippet, "<mark>whistles</mark>") {
+		t.Errorf("DescriptionSnippet = %q, want the match marked", results[0].DescriptionSnippet)
+	}
+
+	results, err = driver.SearchProducts(ctx, &store.SearchProduct{Terms: []string{"kettle", "red"}})
+	if err != nil {
+		t.Fatalf("SearchProducts: %v", err)
+	}
+	if len(results) != 1 || results[0].NameHighlight != "<mark>Red</mark> <mark>kettle</mark>" {
+		t.Fatalf("SearchProducts for every term = %+v, want the red kettle with its name marked", results)
+	}
+}
@@ -0,0 +1,110 @@
+// Package storetest tests that a store.Driver behaves the way the store
+// expects, so that every database implementation can run the same suite.
+// A driver runs it from a test of its own, with a function that opens an
+// empty, migrated database for every test:
+//
+//	func TestDriver(t *testing.T) {
+//		storetest.Run(t, newTestDB)
+//	}
+package storetest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/thetnaingtn/dirty-hand/store"
+)
+
+// NewDriver returns a driver for an empty database whose schema is up to
+// date. It is called once for every test and should close the driver when
+// the test ends.
+type NewDriver func(t *testing.T) store.Driver
+
+// Run runs the conformance tests against the drivers made by newDriver.
+func Run(t *testing.T, newDriver NewDriver) {
+	tests := []struct {
+		name string
+		test func(t *testing.T, driver store.Driver)
+	}{
+		{"Migrations", testMigrations},
+		{"Products", testProducts},
+		{"ProductSearch", testProductSearch},
+		{"Users", testUsers},
+		{"FindUser", testFindUser},
+		{"Sessions", testSessions},
+		{"NotFound", testNotFound},
+		{"Timestamps", testTimestamps},
+		{"UserCascade", testUserCascade},
+		{"OrganizationCascade", testOrganizationCascade},
+		{"AuditEvents", testAuditEvents},
+		{"Transactions", testTransactions},
+		{"ConcurrentWrites", testConcurrentWrites},
+		{"ConcurrentBootstrap", testConcurrentBootstrap},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			test.test(t, newDriver(t))
+		})
+	}
+}
+
+func testMigrations(t *testing.T, driver store.Driver) {
+	ctx := context.Background()
+	migrator := driver.Migrator()
+
+	if err := migrator.Check(ctx); err != nil {
+		t.Fatalf("Check on a new database: %v", err)
+	}
+
+	if _, err := migrator.DownTo(ctx, 0); err != nil {
+		t.Fatalf("DownTo(0): %v", err)
+	}
+
+	if err := migrator.Check(ctx); err == nil {
+		t.Fatal("Check after DownTo(0) succeeded, want the schema to be behind")
+	}
+
+	if _, err := migrator.Up(ctx); err != nil {
+		t.Fatalf("Up after DownTo(0): %v", err)
+	}
+
+	if err := migrator.Check(ctx); err != nil {
+		t.Fatalf("Check after Up: %v", err)
+	}
+
+	name := store.DefaultOrganizationName
+	organizations, err := driver.ListOrganizations(ctx, &store.FindOrganization{Name: &name})
+	if err != nil || len(organizations) != 1 {
+		t.Errorf("ListOrganizations(%s) = %v, %v; want the organization the migrations create", name, organizations, err)
+	}
+}
+
+// now returns the current time at the microsecond precision that every
+// database keeps.
+func now() time.Time {
+	return time.Now().Truncate(time.Microsecond)
+}
+
+func createOrganization(t *testing.T, driver store.Driver, name string) *store.Organization {
+	t.Helper()
+
+	organization, err := driver.CreateOrganization(context.Background(), &store.Organization{Name: name, CreatedTime: now()})
+	if err != nil {
+		t.Fatalf("CreateOrganization: %v", err)
+	}
+
+	return organization
+}
+
+func createUser(t *testing.T, driver store.Driver, username string, role store.Role) *store.User {
+	t.Helper()
+
+	user, err := driver.CreateUser(context.Background(), &store.User{Username: username, PasswordHash: "hash", Role: role})
+	if err != nil {
+		t.Fatalf("CreateUser: %v", err)
+	}
+
+	return user
+}
@@ -0,0 +1,114 @@
+package storetest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/thetnaingtn/dirty-hand/store"
+)
+
+// testTimestamps stores times in one time zone and compares them with times
+// in another. Whatever the zone of the times a driver is given, they must
+// read back equal to the microsecond and compare as instants.
+func testTimestamps(t *testing.T, driver store.Driver) {
+	ctx := context.Background()
+
+	east := time.FixedZone("UTC+5:30", 5*60*60+30*60)
+	west := time.FixedZone("UTC-7", -7*60*60)
+	stored := time.Date(2024, 2, 29, 23, 59, 58, 123456000, east)
+
+	organization := createOrganization(t, driver, "acme")
+	product := createProduct(t, driver, &store.Product{OrganizationID: organization.ID, Name: "Red kettle", Price: 12.5,
+		CreatedAt: stored, UpdatedAt: stored.Add(time.Microsecond)})
+
+	got, err := driver.GetProduct(ctx, organization.ID, product.ID)
+	if err != nil || got == nil {
+		t.Fatalf("GetProduct = %+v, %v; want the kettle", got, err)
+	}
+	assertTime(t, "product created time", got.CreatedAt, stored)
+	assertTime(t, "product updated time", got.UpdatedAt, stored.Add(time.Microsecond))
+
+	for _, test := range []struct {
+		name          string
+		after, before time.Time
+		want          int64
+	}{
+		{"around the created time", stored.Add(-time.Microsecond), stored.Add(time.Microsecond), 1},
+		{"from the created time", stored, stored.Add(time.Hour), 1},
+		{"until the created time", stored.Add(-time.Hour), stored, 0},
+		{"after the created time", stored.Add(time.Microsecond), stored.Add(time.Hour), 0},
+	} {
+		after, before := test.after.In(west), test.before.In(west)
+		count, err := driver.CountProducts(ctx, &store.FindProduct{CreatedAfter: &after, CreatedBefore: &before})
+		if err != nil {
+			t.Fatalf("CountProducts: %v", err)
+		}
+		if count != test.want {
+			t.Errorf("CountProducts created %s = %d, want %d", test.name, count, test.want)
+		}
+	}
+
+	user := createUser(t, driver, "alice", store.RoleAdmin)
+	session := &store.Session{SessionID: "000000000000000000000000000000000001", UserID: user.ID, CreatedTime: stored,
+		LastAccessedTime: stored, ExpiresAt: stored.Add(time.Hour)}
+	if _, err := driver.CreateSession(ctx, session); err != nil {
+		t.Fatalf("CreateSession: %v", err)
+	}
+
+	sessions, err := driver.GetUserSessions(ctx, user.ID)
+	if err != nil || len(sessions) != 1 {
+		t.Fatalf("GetUserSessions = %+v, %v; want the session", sessions, err)
+	}
+	assertSession(t, sessions[0], session)
+
+	// Neither bound is past the times of the session yet.
+	if userIds, err := driver.DeleteExpiredSessions(ctx, stored.In(west), stored.Add(time.Hour).In(west)); err != nil || len(userIds) != 0 {
+		t.Fatalf("DeleteExpiredSessions at the times of the session = %v, %v; want none deleted", userIds, err)
+	}
+	if userIds, err := driver.DeleteExpiredSessions(ctx, stored.Add(time.Microsecond).In(west), time.Time{}); err != nil || len(userIds) != 1 {
+		t.Fatalf("DeleteExpiredSessions past the last access = %v, %v; want the session deleted", userIds, err)
+	}
+
+	expiresAt := stored.Add(24 * time.Hour)
+	token, err := driver.CreateAccessToken(ctx, &store.AccessToken{UserID: user.ID, Name: "ci", TokenHash: "hash", Scopes: []store.Role{store.RoleProductView},
+		CreatedTime: stored, ExpiresAt: &expiresAt})
+	if err != nil {
+		t.Fatalf("CreateAccessToken: %v", err)
+	}
+
+	lastUsed := stored.Add(time.Minute).In(west)
+	if err := driver.UpdateAccessTokenLastUsedTime(ctx, token.ID, lastUsed); err != nil {
+		t.Fatalf("UpdateAccessTokenLastUsedTime: %v", err)
+	}
+
+	tokens, err := driver.ListAccessTokens(ctx, &store.FindAccessToken{ID: &token.ID})
+	if err != nil || len(tokens) != 1 {
+		t.Fatalf("ListAccessTokens = %+v, %v; want the token", tokens, err)
+	}
+	assertTime(t, "access token created time", tokens[0].CreatedTime, stored)
+	if tokens[0].ExpiresAt == nil || tokens[0].LastUsedTime == nil {
+		t.Fatalf("access token = %+v, want its expiry and last use", tokens[0])
+	}
+	assertTime(t, "access token expiry", *tokens[0].ExpiresAt, expiresAt)
+	assertTime(t, "access token last use", *tokens[0].LastUsedTime, lastUsed)
+
+	if _, err := driver.CreateAuditEvent(ctx, &store.AuditEvent{CreatedTime: stored, Method: "/api.v1.UserService/DeleteUser", Outcome: "OK"}); err != nil {
+		t.Fatalf("CreateAuditEvent: %v", err)
+	}
+
+	createdAfter, createdBefore := stored.In(west), stored.Add(time.Microsecond).In(west)
+	events, err := driver.ListAuditEvents(ctx, &store.FindAuditEvent{CreatedAfter: &createdAfter, CreatedBefore: &createdBefore})
+	if err != nil || len(events) != 1 {
+		t.Fatalf("ListAuditEvents around the created time = %+v, %v; want the event", events, err)
+	}
+	assertTime(t, "audit event created time", events[0].CreatedTime, stored)
+}
+
+func assertTime(t *testing.T, name string, got, want time.Time) {
+	t.Helper()
+
+	if !got.Equal(want) {
+		t.Errorf("%s = %v, want %v", name, got, want)
+	}
+}
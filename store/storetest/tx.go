@@ -0,0 +1,70 @@
+package storetest
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/thetnaingtn/dirty-hand/store"
+)
+
+var errRollback = errors.New("roll back")
+
+func testTransactions(t *testing.T, driver store.Driver) {
+	ctx := context.Background()
+
+	err := driver.WithTx(ctx, func(ctx context.Context) error {
+		createUserInTx(t, ctx, driver, "committed", store.RoleProductView)
+
+		username := "committed"
+		user, err := driver.GetUser(ctx, &store.FindUser{Username: &username})
+		if err != nil || user == nil {
+			t.Errorf("GetUser in the transaction = %+v, %v; want the user it created", user, err)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("WithTx: %v", err)
+	}
+	assertUserExists(t, driver, "committed", true)
+
+	err = driver.WithTx(ctx, func(ctx context.Context) error {
+		createUserInTx(t, ctx, driver, "outer", store.RoleProductView)
+
+		// Nested transactions join the outer one, so they are rolled back
+		// with it.
+		if err := driver.WithTx(ctx, func(ctx context.Context) error {
+			createUserInTx(t, ctx, driver, "inner", store.RoleProductView)
+			return nil
+		}); err != nil {
+			t.Errorf("nested WithTx: %v", err)
+		}
+
+		return errRollback
+	})
+	if !errors.Is(err, errRollback) {
+		t.Fatalf("WithTx = %v, want the error of fn", err)
+	}
+	assertUserExists(t, driver, "outer", false)
+	assertUserExists(t, driver, "inner", false)
+}
+
+func createUserInTx(t *testing.T, ctx context.Context, driver store.Driver, username string, role store.Role) {
+	t.Helper()
+
+	if _, err := driver.CreateUser(ctx, &store.User{Username: username, PasswordHash: "hash", Role: role}); err != nil {
+		t.Errorf("CreateUser(%q): %v", username, err)
+	}
+}
+
+func assertUserExists(t *testing.T, driver store.Driver, username string, want bool) {
+	t.Helper()
+
+	user, err := driver.GetUser(context.Background(), &store.FindUser{Username: &username})
+	if err != nil {
+		t.Fatalf("GetUser(%q): %v", username, err)
+	}
+	if got := user != nil; got != want {
+		t.Fatalf("user %q exists = %v, want %v", username, got, want)
+	}
+}
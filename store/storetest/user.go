@@ -0,0 +1,217 @@
+package storetest
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/thetnaingtn/dirty-hand/store"
+)
+
+func testUsers(t *testing.T, driver store.Driver) {
+	ctx := context.Background()
+
+	user := createUser(t, driver, "alice", store.RoleAdmin)
+	if user.ID == 0 {
+		t.Fatal("CreateUser returned no ID")
+	}
+
+	if _, err := driver.CreateUser(ctx, &store.User{Username: "alice", PasswordHash: "hash", Role: store.RoleProductView}); err == nil {
+		t.Fatal("CreateUser with a taken username succeeded")
+	}
+
+	got, err := driver.GetUser(ctx, &store.FindUser{ID: &user.ID})
+	if err != nil {
+		t.Fatalf("GetUser: %v", err)
+	}
+	if got == nil || *got != *user {
+		t.Fatalf("GetUser = %+v, want %+v", got, user)
+	}
+
+	passwordHash, role, disabled := "new hash", store.RoleProductEdit, true
+	updated, err := driver.UpdateUser(ctx, &store.UpdateUser{ID: user.ID, PasswordHash: &passwordHash, Role: &role, Disabled: &disabled})
+	if err != nil {
+		t.Fatalf("UpdateUser: %v", err)
+	}
+	want := store.User{ID: user.ID, Username: "alice", PasswordHash: passwordHash, Role: role, Disabled: true}
+	if updated == nil || *updated != want {
+		t.Fatalf("UpdateUser = %+v, want %+v", updated, want)
+	}
+
+	// An update without changes returns the user as it is.
+	if unchanged, err := driver.UpdateUser(ctx, &store.UpdateUser{ID: user.ID}); err != nil || unchanged == nil || *unchanged != want {
+		t.Fatalf("UpdateUser without changes = %+v, %v; want %+v", unchanged, err, want)
+	}
+
+	if err := driver.DeleteUser(ctx, user.ID); err != nil {
+		t.Fatalf("DeleteUser: %v", err)
+	}
+	if got, err := driver.GetUser(ctx, &store.FindUser{ID: &user.ID}); err != nil || got != nil {
+		t.Fatalf("GetUser after DeleteUser = %+v, %v; want nil, nil", got, err)
+	}
+}
+
+func testFindUser(t *testing.T, driver store.Driver) {
+	ctx := context.Background()
+
+	alice := createUser(t, driver, "alice", store.RoleAdmin)
+	bob := createUser(t, driver, "bob", store.RoleProductView)
+	carol := createUser(t, driver, "carol", store.RoleProductView)
+	createUser(t, driver, "dave", store.RoleProductEdit)
+
+	adminRole, viewRole := store.RoleAdmin, store.RoleProductView
+	bobName, missingName := "bob", "nobody"
+	afterId, limit := alice.ID, 2
+
+	tests := []struct {
+		name string
+		find *store.FindUser
+		want []string
+	}{
+		{"nil filter", nil, []string{"alice", "bob", "carol", "dave"}},
+		{"empty filter", &store.FindUser{}, []string{"alice", "bob", "carol", "dave"}},
+		{"role", &store.FindUser{Role: &viewRole}, []string{"bob", "carol"}},
+		{"id", &store.FindUser{ID: &carol.ID}, []string{"carol"}},
+		{"username", &store.FindUser{Username: &bobName}, []string{"bob"}},
+		{"missing username", &store.FindUser{Username: &missingName}, nil},
+		{"role and username", &store.FindUser{Role: &adminRole, Username: &bobName}, nil},
+		{"after id", &store.FindUser{AfterID: &afterId}, []string{"bob", "carol", "dave"}},
+		{"limit", &store.FindUser{Limit: &limit}, []string{"alice", "bob"}},
+		{"after id and limit", &store.FindUser{AfterID: &afterId, Limit: &limit}, []string{"bob", "carol"}},
+		{"role and limit", &store.FindUser{Role: &viewRole, Limit: &limit}, []string{"bob", "carol"}},
+	}
+
+	for _, test := range tests {
+		users, err := driver.ListUsers(ctx, test.find)
+		if err != nil {
+			t.Fatalf("ListUsers by %s: %v", test.name, err)
+		}
+
+		if got, want := userNames(users), fmt.Sprint(test.want); got != want {
+			t.Errorf("ListUsers by %s = %s, want %s", test.name, got, want)
+		}
+	}
+
+	// GetUser combines its filters too.
+	if got, err := driver.GetUser(ctx, &store.FindUser{Role: &viewRole, Username: &bobName}); err != nil || got == nil || got.ID != bob.ID {
+		t.Errorf("GetUser by role and username = %+v, %v; want bob", got, err)
+	}
+	if got, err := driver.GetUser(ctx, &store.FindUser{Role: &adminRole, ID: &bob.ID}); err != nil || got != nil {
+		t.Errorf("GetUser by another role = %+v, %v; want nil, nil", got, err)
+	}
+}
+
+func testSessions(t *testing.T, driver store.Driver) {
+	ctx := context.Background()
+
+	alice := createUser(t, driver, "alice", store.RoleAdmin)
+	bob := createUser(t, driver, "bob", store.RoleProductView)
+
+	created := now()
+	var sessions []*store.Session
+	for i, user := range []*store.User{alice, alice, alice, bob} {
+		expiresAt := created.Add(time.Hour)
+		if user == bob {
+			expiresAt = created.Add(time.Minute)
+		}
+
+		session := &store.Session{
+			SessionID:        fmt.Sprintf("%036d", i),
+			UserID:           user.ID,
+			CreatedTime:      created.Add(time.Duration(i) * time.Second),
+			LastAccessedTime: created.Add(time.Duration(i) * time.Second),
+			ExpiresAt:        expiresAt,
+			UserAgent:        fmt.Sprintf("agent %d", i),
+			ClientIP:         "192.0.2.1",
+		}
+		if _, err := driver.CreateSession(ctx, session); err != nil {
+			t.Fatalf("CreateSession: %v", err)
+		}
+		sessions = append(sessions, session)
+	}
+
+	if _, err := driver.CreateSession(ctx, &store.Session{SessionID: sessions[0].SessionID, UserID: bob.ID, CreatedTime: created,
+		LastAccessedTime: created, ExpiresAt: created}); err == nil {
+		t.Fatal("CreateSession with a taken session ID succeeded")
+	}
+
+	got, err := driver.GetUserSessions(ctx, alice.ID)
+	if err != nil {
+		t.Fatalf("GetUserSessions: %v", err)
+	}
+	if len(got) != 3 {
+		t.Fatalf("GetUserSessions returned %d sessions, want 3", len(got))
+	}
+	for i, session := range got {
+		assertSession(t, session, sessions[i])
+	}
+
+	lastAccessed := created.Add(time.Minute)
+	if err := driver.UpdateLastAccessedTime(ctx, sessions[1].SessionID, lastAccessed); err != nil {
+		t.Fatalf("UpdateLastAccessedTime: %v", err)
+	}
+	got, err = driver.GetUserSessions(ctx, alice.ID)
+	if err != nil || len(got) != 3 || !got[1].LastAccessedTime.Equal(lastAccessed) || !got[0].LastAccessedTime.Equal(sessions[0].LastAccessedTime) {
+		t.Fatalf("GetUserSessions after UpdateLastAccessedTime = %+v, %v; want only the second session accessed", got, err)
+	}
+
+	if err := driver.DeleteSession(ctx, sessions[0].SessionID); err != nil {
+		t.Fatalf("DeleteSession: %v", err)
+	}
+	if got, err := driver.GetUserSessions(ctx, alice.ID); err != nil || len(got) != 2 || got[0].SessionID != sessions[1].SessionID {
+		t.Fatalf("GetUserSessions after DeleteSession = %+v, %v; want the other two sessions", got, err)
+	}
+
+	if err := driver.DeleteUserSessions(ctx, alice.ID, sessions[2].SessionID); err != nil {
+		t.Fatalf("DeleteUserSessions: %v", err)
+	}
+	if got, err := driver.GetUserSessions(ctx, alice.ID); err != nil || len(got) != 1 || got[0].SessionID != sessions[2].SessionID {
+		t.Fatalf("GetUserSessions after DeleteUserSessions = %+v, %v; want the kept session", got, err)
+	}
+	if got, err := driver.GetUserSessions(ctx, bob.ID); err != nil || len(got) != 1 {
+		t.Fatalf("GetUserSessions of another user = %+v, %v; want their session kept", got, err)
+	}
+
+	// The session of bob expires before the one of alice.
+	userIds, err := driver.DeleteExpiredSessions(ctx, created, created.Add(30*time.Minute))
+	if err != nil {
+		t.Fatalf("DeleteExpiredSessions: %v", err)
+	}
+	if len(userIds) != 1 || userIds[0] != bob.ID {
+		t.Fatalf("DeleteExpiredSessions by expiry = %v, want the user ID of bob", userIds)
+	}
+
+	// The session of alice was last accessed two seconds after it was
+	// created.
+	userIds, err = driver.DeleteExpiredSessions(ctx, created.Add(10*time.Second), created)
+	if err != nil {
+		t.Fatalf("DeleteExpiredSessions: %v", err)
+	}
+	if len(userIds) != 1 || userIds[0] != alice.ID {
+		t.Fatalf("DeleteExpiredSessions by last access = %v, want the user ID of alice", userIds)
+	}
+	if got, err := driver.GetUserSessions(ctx, alice.ID); err != nil || len(got) != 0 {
+		t.Fatalf("GetUserSessions after DeleteExpiredSessions = %+v, %v; want none", got, err)
+	}
+}
+
+func assertSession(t *testing.T, got, want *store.Session) {
+	t.Helper()
+
+	if got.SessionID != want.SessionID || got.UserID != want.UserID || got.UserAgent != want.UserAgent || got.ClientIP != want.ClientIP ||
+		!got.CreatedTime.Equal(want.CreatedTime) || !got.LastAccessedTime.Equal(want.LastAccessedTime) || !got.ExpiresAt.Equal(want.ExpiresAt) {
+		t.Errorf("session = %+v, want %+v", got, want)
+	}
+}
+
+// userNames lists the usernames of users in order, which reads better in
+// failure messages than the users themselves.
+func userNames(users []store.User) string {
+	names := make([]string, 0, len(users))
+	for _, user := range users {
+		names = append(names, user.Username)
+	}
+
+	return fmt.Sprint(names)
+}
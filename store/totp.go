@@ -0,0 +1,106 @@
+package store
+
+import (
+	"context"
+	"time"
+)
+
+// UserTOTP holds the secret of a user's TOTP authenticator. An enrollment is
+// pending until the user proves they can generate codes with it.
+type UserTOTP struct {
+	UserID  int64
+	Secret  string
+	Enabled bool
+	// LastUsedStep is the time step of the last accepted code, so that a
+	// code cannot be used twice.
+	LastUsedStep int64
+	CreatedTime  time.Time
+}
+
+// SecondFactorChallenge is issued when a user with two-factor authentication
+// signs in with a correct password. It is exchanged for a session together
+// with a second factor. Only a hash of the challenge is stored.
+type SecondFactorChallenge struct {
+	TokenHash   string
+	UserID      int64
+	RememberMe  bool
+	Attempts    int
+	CreatedTime time.Time
+	ExpiresTime time.Time
+}
+
+func (s *Store) GetUserTOTP(ctx context.Context, userId int64) (*UserTOTP, error) {
+	return s.driver.GetUserTOTP(ctx, userId)
+}
+
+// EnrollUserTOTP stores a pending TOTP enrollment with its recovery codes,
+// replacing any earlier enrollment of the user.
+func (s *Store) EnrollUserTOTP(ctx context.Context, totp *UserTOTP, recoveryCodeHashes []string) (*UserTOTP, error) {
+	if err := s.driver.DeleteRecoveryCodes(ctx, totp.UserID); err != nil {
+		return nil, err
+	}
+
+	totp, err := s.driver.UpsertUserTOTP(ctx, totp)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.driver.CreateRecoveryCodes(ctx, totp.UserID, recoveryCodeHashes); err != nil {
+		return nil, err
+	}
+
+	return totp, nil
+}
+
+func (s *Store) EnableUserTOTP(ctx context.Context, userId int64) error {
+	return s.driver.EnableUserTOTP(ctx, userId)
+}
+
+// AdvanceUserTOTPStep records step as the last used time step. It reports
+// false when a code of that or a later step was already accepted.
+func (s *Store) AdvanceUserTOTPStep(ctx context.Context, userId int64, step int64) (bool, error) {
+	return s.driver.AdvanceUserTOTPStep(ctx, userId, step)
+}
+
+// DeleteUserTOTP turns two-factor authentication off for a user and removes
+// their recovery codes.
+func (s *Store) DeleteUserTOTP(ctx context.Context, userId int64) error {
+	if err := s.driver.DeleteRecoveryCodes(ctx, userId); err != nil {
+		return err
+	}
+
+	return s.driver.DeleteUserTOTP(ctx, userId)
+}
+
+// ConsumeRecoveryCode marks an unused recovery code of the user as used. It
+// reports false when there is no such code.
+func (s *Store) ConsumeRecoveryCode(ctx context.Context, userId int64, codeHash string) (bool, error) {
+	return s.driver.ConsumeRecoveryCode(ctx, userId, codeHash, time.Now())
+}
+
+func (s *Store) CreateSecondFactorChallenge(ctx context.Context, challenge *SecondFactorChallenge) (*SecondFactorChallenge, error) {
+	return s.driver.CreateSecondFactorChallenge(ctx, challenge)
+}
+
+// GetSecondFactorChallenge returns the unexpired challenge with the given
+// hash, or nil when there is none.
+func (s *Store) GetSecondFactorChallenge(ctx context.Context, tokenHash string) (*SecondFactorChallenge, error) {
+	return s.driver.GetSecondFactorChallenge(ctx, tokenHash, time.Now())
+}
+
+func (s *Store) IncrementSecondFactorChallengeAttempts(ctx context.Context, tokenHash string) error {
+	return s.driver.IncrementSecondFactorChallengeAttempts(ctx, tokenHash)
+}
+
+// ConsumeSecondFactorChallenge deletes the unexpired challenge with the
+// given hash and returns it. It returns nil when the challenge does not
+// exist, has expired or was already used.
+func (s *Store) ConsumeSecondFactorChallenge(ctx context.Context, tokenHash string) (*SecondFactorChallenge, error) {
+	return s.driver.ConsumeSecondFactorChallenge(ctx, tokenHash, time.Now())
+}
+
+// DeleteExpiredSecondFactorChallenges deletes expired challenges and returns
+// how many were deleted.
+func (s *Store) DeleteExpiredSecondFactorChallenges(ctx context.Context) (int64, error) {
+	return s.driver.DeleteExpiredSecondFactorChallenges(ctx, time.Now())
+}
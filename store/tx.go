@@ -0,0 +1,64 @@
+package store
+
+import (
+	"context"
+	"sync"
+)
+
+type txContextKey struct{}
+
+// txState holds the work the store defers until a transaction commits.
+type txState struct {
+	mu          sync.Mutex
+	afterCommit []func()
+}
+
+// WithTx runs fn in a transaction, which is committed when fn returns nil
+// and rolled back otherwise. Store calls made with the context passed to fn
+// run in the transaction, and nested calls join it. Cache updates and audit
+// changes take effect once the transaction commits.
+//
+// fn may be run more than once when the transaction conflicts with another
+// one, so it must not have side effects outside the store.
+func (s *Store) WithTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if inTx(ctx) {
+		return fn(ctx)
+	}
+
+	var state *txState
+	err := s.driver.WithTx(ctx, func(ctx context.Context) error {
+		// A retried transaction starts over.
+		state = &txState{}
+		return fn(context.WithValue(ctx, txContextKey{}, state))
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, f := range state.afterCommit {
+		f()
+	}
+
+	return nil
+}
+
+// inTx reports whether ctx belongs to a transaction started by WithTx. The
+// caches are bypassed in transactions, as they only hold committed data.
+func inTx(ctx context.Context) bool {
+	_, ok := ctx.Value(txContextKey{}).(*txState)
+	return ok
+}
+
+// afterCommit runs f once the transaction of ctx commits, or right away
+// outside a transaction.
+func afterCommit(ctx context.Context, f func()) {
+	state, ok := ctx.Value(txContextKey{}).(*txState)
+	if !ok {
+		f()
+		return
+	}
+
+	state.mu.Lock()
+	state.afterCommit = append(state.afterCommit, f)
+	state.mu.Unlock()
+}
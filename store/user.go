@@ -2,6 +2,7 @@ package store
 
 import (
 	"context"
+	"errors"
 	"strconv"
 )
 
@@ -13,17 +14,32 @@ const (
 	RoleProductEdit Role = "product:edit"
 )
 
+// ErrLastAdmin is returned when a change would leave no enabled admin.
+var ErrLastAdmin = errors.New("cannot remove the last admin")
+
 type User struct {
 	ID           int64
 	Username     string
 	PasswordHash string
 	Role         Role
+	Disabled     bool
 }
 
 type FindUser struct {
 	Role     *Role
 	ID       *int64
 	Username *string
+
+	// AfterID and Limit page through users ordered by ID.
+	AfterID *int64
+	Limit   *int
+}
+
+type UpdateUser struct {
+	ID           int64
+	PasswordHash *string
+	Role         *Role
+	Disabled     *bool
 }
 
 func (s *Store) CreateUser(ctx context.Context, user *User) (*User, error) {
@@ -33,7 +49,9 @@ func (s *Store) CreateUser(ctx context.Context, user *User) (*User, error) {
 	}
 
 	key := strconv.FormatInt(user.ID, 10)
-	s.userCache.Set(key, user)
+	afterCommit(ctx, func() { s.userCache.Set(key, user) })
+
+	recordChange(ctx, "user", user.ID, nil, user.auditSnapshot())
 
 	return user, nil
 }
@@ -43,7 +61,7 @@ func (s *Store) ListUsers(ctx context.Context, filter *FindUser) ([]User, error)
 }
 
 func (s *Store) GetUser(ctx context.Context, filter *FindUser) (*User, error) {
-	if filter.ID != nil {
+	if filter.ID != nil && !inTx(ctx) {
 		key := strconv.FormatInt(*filter.ID, 10)
 		item, exist := s.userCache.Get(key)
 		if exist {
@@ -56,12 +74,106 @@ func (s *Store) GetUser(ctx context.Context, filter *FindUser) (*User, error) {
 	return s.driver.GetUser(ctx, filter)
 }
 
+// UpdateUser applies the given changes to a user. It refuses to demote or
+// disable the last enabled admin.
+func (s *Store) UpdateUser(ctx context.Context, update *UpdateUser) (*User, error) {
+	var user *User
+	err := s.WithTx(ctx, func(ctx context.Context) error {
+		before, err := s.driver.GetUser(ctx, &FindUser{ID: &update.ID})
+		if err != nil || before == nil {
+			user = nil
+			return err
+		}
+
+		demoted := update.Role != nil && *update.Role != RoleAdmin
+		disabled := update.Disabled != nil && *update.Disabled
+		if before.Role == RoleAdmin && !before.Disabled && (demoted || disabled) {
+			if err := s.ensureAnotherAdmin(ctx, before.ID); err != nil {
+				return err
+			}
+		}
+
+		if user, err = s.driver.UpdateUser(ctx, update); err != nil {
+			return err
+		}
+
+		recordChange(ctx, "user", user.ID, before.auditSnapshot(), user.auditSnapshot())
+
+		key := strconv.FormatInt(user.ID, 10)
+		afterCommit(ctx, func() { s.userCache.Set(key, user) })
+
+		if user.Disabled {
+			if err := s.driver.DeleteUserSessions(ctx, user.ID, ""); err != nil {
+				return err
+			}
+			afterCommit(ctx, func() { s.sessionCache.Delete(key) })
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// DeleteUser removes a user together with their sessions. It refuses to
+// delete the last enabled admin.
+func (s *Store) DeleteUser(ctx context.Context, id int64) error {
+	return s.WithTx(ctx, func(ctx context.Context) error {
+		user, err := s.driver.GetUser(ctx, &FindUser{ID: &id})
+		if err != nil || user == nil {
+			return err
+		}
+
+		if user.Role == RoleAdmin && !user.Disabled {
+			if err := s.ensureAnotherAdmin(ctx, user.ID); err != nil {
+				return err
+			}
+		}
+
+		if err := s.driver.DeleteUser(ctx, id); err != nil {
+			return err
+		}
+
+		recordChange(ctx, "user", id, user.auditSnapshot(), nil)
+
+		key := strconv.FormatInt(id, 10)
+		afterCommit(ctx, func() {
+			s.userCache.Delete(key)
+			s.sessionCache.Delete(key)
+		})
+
+		return nil
+	})
+}
+
+// ensureAnotherAdmin returns ErrLastAdmin unless an enabled admin other than
+// the given user exists.
+func (s *Store) ensureAnotherAdmin(ctx context.Context, userId int64) error {
+	adminRole := RoleAdmin
+	admins, err := s.driver.ListUsers(ctx, &FindUser{Role: &adminRole})
+	if err != nil {
+		return err
+	}
+
+	for _, admin := range admins {
+		if admin.ID != userId && !admin.Disabled {
+			return nil
+		}
+	}
+
+	return ErrLastAdmin
+}
+
 func (s *Store) GetUserSessions(ctx context.Context, userId int64) ([]*Session, error) {
-	key := strconv.FormatInt(userId, 10)
-	if item, ok := s.sessionCache.Get(key); ok {
-		sessions, ok := item.([]*Session)
-		if ok {
-			return sessions, nil
+	if !inTx(ctx) {
+		key := strconv.FormatInt(userId, 10)
+		if item, ok := s.sessionCache.Get(key); ok {
+			if sessions, ok := item.([]*Session); ok {
+				return sessions, nil
+			}
 		}
 	}
 
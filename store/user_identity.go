@@ -0,0 +1,44 @@
+package store
+
+import (
+	"context"
+	"time"
+)
+
+// UserIdentity links a user to an account at an external identity provider,
+// identified by the provider's issuer and the subject it assigned.
+type UserIdentity struct {
+	UserID      int64
+	Issuer      string
+	Subject     string
+	CreatedTime time.Time
+}
+
+type FindUserIdentity struct {
+	UserID  *int64
+	Issuer  *string
+	Subject *string
+}
+
+func (s *Store) CreateUserIdentity(ctx context.Context, identity *UserIdentity) (*UserIdentity, error) {
+	return s.driver.CreateUserIdentity(ctx, identity)
+}
+
+func (s *Store) ListUserIdentities(ctx context.Context, find *FindUserIdentity) ([]*UserIdentity, error) {
+	return s.driver.ListUserIdentities(ctx, find)
+}
+
+// GetUserIdentity returns the first identity matching find, or nil when there
+// is none.
+func (s *Store) GetUserIdentity(ctx context.Context, find *FindUserIdentity) (*UserIdentity, error) {
+	identities, err := s.driver.ListUserIdentities(ctx, find)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(identities) == 0 {
+		return nil, nil
+	}
+
+	return identities[0], nil
+}
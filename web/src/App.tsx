@@ -5,6 +5,7 @@ import CreatePage from './pages/CreatePage'
 import UpdatePage from './pages/UpdatePage'
 import { UserCreatePage } from "./pages/user"
 import { SigninPage } from './pages/user/SigninPage'
+import { OrganizationSelect } from './components/organization-select'
 
 export default function App() {
   return (
@@ -12,6 +13,7 @@ export default function App() {
       <header className="border-b p-4">
         <div className="mx-auto max-w-5xl flex items-center justify-between">
           <Link to="/" className="text-lg font-semibold">Atlas</Link>
+          <OrganizationSelect />
         </div>
       </header>
       <main className="mx-auto max-w-5xl">
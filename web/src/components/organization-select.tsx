@@ -0,0 +1,48 @@
+import { useEffect, useState } from 'react'
+import { getOrganizationId, listOrganizations, setOrganizationId, type Organization } from '../organization'
+
+const defaultOrganizationName = 'Default'
+
+export function OrganizationSelect() {
+  const [organizations, setOrganizations] = useState<Organization[]>([])
+  const [selected, setSelected] = useState(getOrganizationId())
+
+  useEffect(() => {
+    listOrganizations()
+      .then((organizations) => {
+        setOrganizations(organizations)
+
+        // Until the user picks one, calls act on the Default organization.
+        if (selected === null || !organizations.some((o) => o.id === selected)) {
+          const fallback = organizations.find((o) => o.name === defaultOrganizationName) ?? organizations[0]
+          if (fallback) {
+            setOrganizationId(fallback.id)
+            setSelected(fallback.id)
+          }
+        }
+      })
+      .catch((e) => console.error(e))
+  }, [])
+
+  if (organizations.length < 2) {
+    return null
+  }
+
+  const handleChange = (id: string) => {
+    setOrganizationId(id)
+    // Every page shows the products of the organization, so load it again.
+    window.location.reload()
+  }
+
+  return (
+    <select
+      className="border rounded p-1"
+      value={selected ?? ''}
+      onChange={(e) => handleChange(e.target.value)}
+    >
+      {organizations.map((o) => (
+        <option key={o.id} value={o.id}>{o.name}</option>
+      ))}
+    </select>
+  )
+}
@@ -1,6 +1,7 @@
-import { createChannel, createClientFactory, FetchTransport } from 'nice-grpc-web'
+import { createChannel, createClientFactory, FetchTransport, Metadata, type CallOptions, type ClientMiddlewareCall } from 'nice-grpc-web'
 import { ProductServiceDefinition } from './types/proto/api/v1/product'
 import { UserServiceDefinition } from './types/proto/api/v1/user'
+import { getOrganizationId, organizationIdHeader } from './organization'
 
 const channel = createChannel(
     window.location.origin,
@@ -9,7 +10,18 @@ const channel = createChannel(
     })
 )
 
-const clientFactory = createClientFactory()
+// organizationMiddleware sends the selected organization with every call.
+async function* organizationMiddleware<Request, Response>(call: ClientMiddlewareCall<Request, Response>, options: CallOptions) {
+    const organizationId = getOrganizationId()
+    if (organizationId === null) {
+        return yield* call.next(call.request, options)
+    }
+
+    const metadata = Metadata(options.metadata).set(organizationIdHeader, organizationId)
+    return yield* call.next(call.request, { ...options, metadata })
+}
+
+const clientFactory = createClientFactory().use(organizationMiddleware)
 
 export const productClient = clientFactory.create(ProductServiceDefinition, channel)
 export const userClient = clientFactory.create(UserServiceDefinition, channel)
\ No newline at end of file
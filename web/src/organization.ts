@@ -0,0 +1,29 @@
+// The organization product calls act on is remembered per browser. Without
+// one the server uses the Default organization.
+const storageKey = 'organizationId'
+
+export const organizationIdHeader = 'x-organization-id'
+
+export function getOrganizationId(): string | null {
+  return localStorage.getItem(storageKey)
+}
+
+export function setOrganizationId(id: string) {
+  localStorage.setItem(storageKey, id)
+}
+
+export interface Organization {
+  id: string
+  name: string
+}
+
+// listOrganizations returns the organizations the signed-in user can act on.
+export async function listOrganizations(): Promise<Organization[]> {
+  const res = await fetch('/v1/organizations', { credentials: 'include' })
+  if (!res.ok) {
+    throw new Error(`failed to list organizations: ${res.status}`)
+  }
+
+  const body: { organizations?: Organization[] } = await res.json()
+  return body.organizations ?? []
+}